@@ -0,0 +1,228 @@
+package testcontainers
+
+import (
+	"archive/tar"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/client"
+
+	"github.com/testcontainers/testcontainers-go/internal/core"
+)
+
+// buildCacheRepo is the repository of the images built with [WithBuildCache] without a Repo.
+const buildCacheRepo = "testcontainers-build-cache"
+
+// buildCacheKey returns the content hash of the build defined by the build options.
+// The files of the build context are hashed by name, type, mode and content, ignoring
+// the modification times and the owners, which change with every checkout of the sources,
+// as does the build identity label, which holds the path of the build context.
+func buildCacheKey(opts client.ImageBuildOptions, platform string) (string, error) {
+	h := sha256.New()
+
+	labels := maps.Clone(opts.Labels)
+	delete(labels, core.LabelBuildCacheID)
+
+	params, err := json.Marshal(struct {
+		Dockerfile string
+		BuildArgs  map[string]*string
+		Target     string
+		Platform   string
+		Platforms  []string
+		Labels     map[string]string
+		Version    string
+	}{
+		Dockerfile: opts.Dockerfile,
+		BuildArgs:  opts.BuildArgs,
+		Target:     opts.Target,
+		Platform:   platform,
+		Platforms:  platformStrings(opts),
+		Labels:     labels,
+		Version:    string(opts.Version),
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal build options: %w", err)
+	}
+	h.Write(params)
+
+	if err := hashBuildContext(h, opts.Context); err != nil {
+		return "", fmt.Errorf("hash build context: %w", err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// platformStrings returns the platforms of the build options as strings.
+func platformStrings(opts client.ImageBuildOptions) []string {
+	platforms := make([]string, 0, len(opts.Platforms))
+	for _, p := range opts.Platforms {
+		platforms = append(platforms, fmt.Sprintf("%s/%s/%s", p.OS, p.Architecture, p.Variant))
+	}
+
+	return platforms
+}
+
+// hashBuildContext writes the entries of the tar archive of the build context to h.
+func hashBuildContext(h hash.Hash, buildContext io.Reader) error {
+	tr := tar.NewReader(buildContext)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read archive: %w", err)
+		}
+
+		fmt.Fprintf(h, "%s\x00%c\x00%o\x00%s\x00%d\x00", hdr.Name, hdr.Typeflag, hdr.Mode, hdr.Linkname, hdr.Size)
+		if _, err := io.Copy(h, tr); err != nil {
+			return fmt.Errorf("read %s: %w", hdr.Name, err)
+		}
+	}
+}
+
+// buildCacheID returns the identity of the build of the request, independent of the content
+// of its sources: the hash of the absolute path of the build context directory, the Dockerfile
+// and the target. The builds from a context archive or an inline context have no context path,
+// so they are only identified by their Dockerfile and target.
+func (c *ContainerRequest) buildCacheID() string {
+	contextPath := ""
+	if c.ContextArchive == nil && c.Inline == nil && c.Context != "" {
+		contextPath = c.Context
+		if abs, err := filepath.Abs(c.Context); err == nil {
+			contextPath = abs
+		}
+	}
+
+	sum := sha256.Sum256([]byte(contextPath + "\x00" + c.GetDockerfile() + "\x00" + c.Target))
+	return hex.EncodeToString(sum[:])
+}
+
+// buildCacheTag sets the repository and the content-addressed tag of the image in the request,
+// returning the tag of the image to build.
+func (c *ContainerRequest) buildCacheTag() (string, error) {
+	if c.Repo == "" {
+		c.Repo = buildCacheRepo
+	}
+
+	opts, err := c.BuildOptions()
+	if err != nil {
+		return "", fmt.Errorf("build options: %w", err)
+	}
+	defer tryClose(opts.Context)
+
+	key, err := buildCacheKey(opts, c.ImagePlatform)
+	if err != nil {
+		return "", err
+	}
+
+	// The archive was read to compute the key, rewind it for the build.
+	if c.ContextArchive != nil {
+		if _, err := c.ContextArchive.Seek(0, io.SeekStart); err != nil {
+			return "", fmt.Errorf("seek context archive to start: %w", err)
+		}
+	}
+
+	c.Tag = key
+	return c.substituteBuildTag(c.Repo + ":" + c.Tag)
+}
+
+// buildCachedImage returns the image built with the content-addressed tag of the request,
+// building it only if it does not exist locally.
+func (p *DockerProvider) buildCachedImage(ctx context.Context, req *ContainerRequest) (string, error) {
+	tag, err := req.buildCacheTag()
+	if err != nil {
+		return "", fmt.Errorf("build cache tag: %w", err)
+	}
+
+	_, err = p.client.ImageInspect(ctx, tag)
+	if err == nil {
+		p.Logger.Printf("🗃️ Reusing cached image %s", tag)
+		return tag, nil
+	}
+
+	if !errdefs.IsNotFound(err) {
+		return "", fmt.Errorf("inspect cached image: %w", err)
+	}
+
+	return p.BuildImage(ctx, req)
+}
+
+// PruneBuildCache removes the images built with [WithBuildCache] which were created more
+// than olderThan ago, except the most recent image of each build, identified by its repository,
+// build context directory, Dockerfile and target, so every project keeps its latest cached image.
+// Use zero to remove all the images but the most recent of each build. It returns the tags of the
+// removed images.
+func PruneBuildCache(ctx context.Context, olderThan time.Duration) ([]string, error) {
+	cli, err := NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("new docker client: %w", err)
+	}
+	defer cli.Close()
+
+	list, err := cli.ImageList(ctx, client.ImageListOptions{
+		Filters: make(client.Filters).Add("label", core.LabelBuildCache+"=true"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list images: %w", err)
+	}
+
+	// Sort the images from the most recent, so the first image of each build is kept.
+	images := slices.Clone(list.Items)
+	slices.SortFunc(images, func(a, b image.Summary) int {
+		return cmp.Compare(b.Created, a.Created)
+	})
+
+	deadline := time.Now().Add(-olderThan)
+	latest := map[string]bool{}
+
+	var removed []string
+	var errs []error
+	for _, img := range images {
+		for _, tag := range img.RepoTags {
+			build := buildCacheImageBuild(tag, img.Labels)
+			if !latest[build] {
+				latest[build] = true
+				continue
+			}
+
+			if time.Unix(img.Created, 0).After(deadline) {
+				continue
+			}
+
+			if _, err := cli.ImageRemove(ctx, tag, client.ImageRemoveOptions{PruneChildren: true}); err != nil {
+				errs = append(errs, fmt.Errorf("remove image %s: %w", tag, err))
+				continue
+			}
+
+			removed = append(removed, tag)
+		}
+	}
+
+	return removed, errors.Join(errs...)
+}
+
+// buildCacheImageBuild returns the build of the image with the given tag and labels, which is
+// its repository and its build identity label, if any.
+func buildCacheImageBuild(tag string, labels map[string]string) string {
+	repo := tag
+	if i := strings.LastIndex(tag, ":"); i > strings.LastIndex(tag, "/") {
+		repo = tag[:i]
+	}
+
+	return repo + "\x00" + labels[core.LabelBuildCacheID]
+}
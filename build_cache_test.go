@@ -0,0 +1,227 @@
+package testcontainers
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/internal/core"
+)
+
+// buildContextArchive returns a tar archive with the given files, all of them
+// with the given modification time.
+func buildContextArchive(t *testing.T, modTime time.Time, files map[string]string) *bytes.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range []string{"Dockerfile", "app.sh"} {
+		content, ok := files[name]
+		if !ok {
+			continue
+		}
+
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+			ModTime:  modTime,
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestBuildCacheKey(t *testing.T) {
+	files := map[string]string{
+		"Dockerfile": "FROM alpine\nCOPY app.sh /\n",
+		"app.sh":     "echo hello",
+	}
+
+	key := func(modTime time.Time, files map[string]string, args map[string]*string) string {
+		t.Helper()
+
+		k, err := buildCacheKey(client.ImageBuildOptions{
+			Dockerfile: "Dockerfile",
+			BuildArgs:  args,
+			Context:    buildContextArchive(t, modTime, files),
+		}, "")
+		require.NoError(t, err)
+
+		return k
+	}
+
+	now := time.Now()
+	base := key(now, files, nil)
+
+	t.Run("same-content-different-mtime", func(t *testing.T) {
+		require.Equal(t, base, key(now.Add(-time.Hour), files, nil))
+	})
+
+	t.Run("different-content", func(t *testing.T) {
+		require.NotEqual(t, base, key(now, map[string]string{
+			"Dockerfile": files["Dockerfile"],
+			"app.sh":     "echo bye",
+		}, nil))
+	})
+
+	t.Run("different-build-args", func(t *testing.T) {
+		v := "bar"
+		require.NotEqual(t, base, key(now, files, map[string]*string{"FOO": &v}))
+	})
+
+	t.Run("different-platform", func(t *testing.T) {
+		k, err := buildCacheKey(client.ImageBuildOptions{
+			Dockerfile: "Dockerfile",
+			Context:    buildContextArchive(t, now, files),
+		}, "linux/arm64")
+		require.NoError(t, err)
+		require.NotEqual(t, base, k)
+	})
+}
+
+func TestBuildCacheTag(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM alpine\n"), 0o644))
+
+	tag := func(df FromDockerfile) string {
+		t.Helper()
+
+		req := ContainerRequest{FromDockerfile: df}
+		req.BuildCache = true
+
+		tag, err := req.buildCacheTag()
+		require.NoError(t, err)
+
+		return tag
+	}
+
+	first := tag(FromDockerfile{Context: dir})
+	require.True(t, strings.HasPrefix(first, buildCacheRepo+":"))
+	require.Equal(t, first, tag(FromDockerfile{Context: dir}))
+
+	// touching the files does not change the tag.
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "Dockerfile"), time.Now(), time.Now().Add(time.Hour)))
+	require.Equal(t, first, tag(FromDockerfile{Context: dir}))
+
+	custom := tag(FromDockerfile{Context: dir, Repo: "my-app"})
+	require.True(t, strings.HasPrefix(custom, "my-app:"))
+	require.Equal(t, strings.TrimPrefix(first, buildCacheRepo), strings.TrimPrefix(custom, "my-app"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM alpine:3\n"), 0o644))
+	require.NotEqual(t, first, tag(FromDockerfile{Context: dir}))
+}
+
+func TestBuildCacheID(t *testing.T) {
+	dir1 := t.TempDir()
+	dir2 := t.TempDir()
+	for _, dir := range []string{dir1, dir2} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM alpine\n"), 0o644))
+	}
+
+	build := func(df FromDockerfile) (string, string) {
+		t.Helper()
+
+		req := ContainerRequest{FromDockerfile: df}
+		req.BuildCache = true
+
+		tag, err := req.buildCacheTag()
+		require.NoError(t, err)
+
+		opts, err := req.BuildOptions()
+		require.NoError(t, err)
+		defer tryClose(opts.Context)
+
+		return tag, opts.Labels[core.LabelBuildCacheID]
+	}
+
+	tag1, id1 := build(FromDockerfile{Context: dir1})
+	require.NotEmpty(t, id1)
+
+	t.Run("same-sources-different-directory", func(t *testing.T) {
+		// the content hash does not depend on the directory, the build identity does.
+		tag2, id2 := build(FromDockerfile{Context: dir2})
+		require.Equal(t, tag1, tag2)
+		require.NotEqual(t, id1, id2)
+	})
+
+	t.Run("different-sources-same-directory", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir1, "Dockerfile"), []byte("FROM alpine:3\n"), 0o644))
+
+		tag, id := build(FromDockerfile{Context: dir1})
+		require.NotEqual(t, tag1, tag)
+		require.Equal(t, id1, id)
+	})
+
+	t.Run("prune-groups", func(t *testing.T) {
+		labels1 := map[string]string{core.LabelBuildCacheID: id1}
+		labels2 := map[string]string{core.LabelBuildCacheID: "other"}
+
+		require.Equal(t, buildCacheImageBuild(buildCacheRepo+":a", labels1), buildCacheImageBuild(buildCacheRepo+":b", labels1))
+		require.NotEqual(t, buildCacheImageBuild(buildCacheRepo+":a", labels1), buildCacheImageBuild(buildCacheRepo+":a", labels2))
+		require.NotEqual(t, buildCacheImageBuild(buildCacheRepo+":a", labels1), buildCacheImageBuild("my-app:a", labels1))
+		require.Equal(t, buildCacheImageBuild("localhost:5000/my-app:a", nil), buildCacheImageBuild("localhost:5000/my-app:b", nil))
+	})
+}
+
+func TestBuildCache_reuse(t *testing.T) {
+	ctx := context.Background()
+
+	provider, err := NewDockerProvider()
+	require.NoError(t, err)
+	defer provider.Close()
+
+	run := func() (string, string) {
+		t.Helper()
+
+		// withBuildCache {
+		ctr, err := Run(ctx, "",
+			WithDockerfile(FromDockerfile{
+				Context:    "testdata",
+				Dockerfile: "echo.Dockerfile",
+			}),
+			WithBuildCache(),
+		)
+		// }
+		CleanupContainer(t, ctr)
+		require.NoError(t, err)
+
+		inspect, err := ctr.Inspect(ctx)
+		require.NoError(t, err)
+
+		return inspect.Config.Image, inspect.Image
+	}
+
+	tag, id := run()
+	require.True(t, strings.HasPrefix(tag, buildCacheRepo+":"))
+
+	// the second run reuses the image.
+	tag2, id2 := run()
+	require.Equal(t, tag, tag2)
+	require.Equal(t, id, id2)
+
+	// the image is kept after the container is terminated, and it's the most recent of its repository.
+	removed, err := PruneBuildCache(ctx, 0)
+	require.NoError(t, err)
+	require.NotContains(t, removed, tag)
+
+	_, err = provider.Client().ImageInspect(ctx, tag)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_, err := provider.Client().ImageRemove(ctx, tag, client.ImageRemoveOptions{Force: true, PruneChildren: true})
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,336 @@
+package testcontainers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/moby/moby/api/types/jsonstream"
+	"github.com/moby/moby/client"
+
+	"github.com/testcontainers/testcontainers-go/internal/core/buildkit"
+)
+
+const (
+	// buildCacheArchive is the name of the image archive holding an archive cache.
+	buildCacheArchive = "cache.tar"
+
+	// buildKitInlineCache is the build argument embedding the build cache into the image.
+	buildKitInlineCache = "BUILDKIT_INLINE_CACHE"
+)
+
+// buildCaches are the cache sources and destinations of a build.
+type buildCaches struct {
+	fromImages []string // the images to import the cache from
+	fromDirs   []string // the archive cache directories to import the cache from
+	toDirs     []string // the archive cache directories to export the cache to
+	inline     bool     // whether the cache is embedded into the image
+}
+
+// parseBuildCache parses a CacheFrom or CacheTo entry, which is either an image
+// reference or a comma-separated list of key=value attributes including the type.
+func parseBuildCache(entry string) (map[string]string, error) {
+	if !strings.Contains(entry, "=") {
+		return map[string]string{"type": "registry", "ref": entry}, nil
+	}
+
+	attrs := map[string]string{}
+	for field := range strings.SplitSeq(entry, ",") {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid field %q in cache %q", field, entry)
+		}
+		attrs[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	if attrs["type"] == "" {
+		return nil, fmt.Errorf("missing type in cache %q", entry)
+	}
+
+	return attrs, nil
+}
+
+// errLocalBuildCache is returned for the buildx local directory caches, whose
+// OCI layout the Docker daemon can neither import nor export.
+var errLocalBuildCache = errors.New(`the "local" cache type of buildx is not supported by the Docker daemon, use the "archive" cache type instead`)
+
+// buildCaches returns the cache sources and destinations of the build.
+// The Docker daemon can only export the cache inline, so an archive cache is a
+// directory holding an archive of the image built with the inline cache, loaded
+// back as a cache source in the next builds. It is not compatible with the local
+// directory caches of buildx.
+func (c *ContainerRequest) buildCaches() (buildCaches, error) {
+	var caches buildCaches
+
+	for _, entry := range c.CacheFrom {
+		attrs, err := parseBuildCache(entry)
+		if err != nil {
+			return buildCaches{}, err
+		}
+
+		switch attrs["type"] {
+		case "registry":
+			if attrs["ref"] == "" {
+				return buildCaches{}, fmt.Errorf("missing ref in cache %q", entry)
+			}
+			caches.fromImages = append(caches.fromImages, attrs["ref"])
+		case "archive":
+			if attrs["src"] == "" {
+				return buildCaches{}, fmt.Errorf("missing src in cache %q", entry)
+			}
+			caches.fromDirs = append(caches.fromDirs, attrs["src"])
+		case "local":
+			return buildCaches{}, fmt.Errorf("cache %q: %w", entry, errLocalBuildCache)
+		default:
+			return buildCaches{}, fmt.Errorf("unsupported cache source type %q: only registry and archive are supported", attrs["type"])
+		}
+	}
+
+	for _, entry := range c.CacheTo {
+		attrs, err := parseBuildCache(entry)
+		if err != nil {
+			return buildCaches{}, err
+		}
+
+		switch attrs["type"] {
+		case "inline":
+			caches.inline = true
+		case "archive":
+			if attrs["dest"] == "" {
+				return buildCaches{}, fmt.Errorf("missing dest in cache %q", entry)
+			}
+			caches.toDirs = append(caches.toDirs, attrs["dest"])
+			caches.inline = true
+		case "local":
+			return buildCaches{}, fmt.Errorf("cache %q: %w", entry, errLocalBuildCache)
+		default:
+			return buildCaches{}, fmt.Errorf("unsupported cache destination type %q: only inline and archive are supported by the Docker daemon", attrs["type"])
+		}
+	}
+
+	return caches, nil
+}
+
+// usesBuildKit returns true if the image must be built with BuildKit.
+func (c *ContainerRequest) usesBuildKit() bool {
+	if c.BuildKit || len(c.Secrets) > 0 || len(c.SSH) > 0 || len(c.CacheTo) > 0 {
+		return true
+	}
+
+	caches, err := c.buildCaches()
+	return err == nil && len(caches.fromDirs) > 0
+}
+
+// buildSession returns the BuildKit session exposing the secrets and the SSH agents of the build.
+func (c *ContainerRequest) buildSession() (*buildkit.Session, error) {
+	secrets := make([]buildkit.Secret, 0, len(c.Secrets))
+	for _, s := range c.Secrets {
+		secrets = append(secrets, buildkit.Secret{ID: s.ID, File: s.File, Env: s.Env})
+	}
+
+	agents := make([]buildkit.SSH, 0, len(c.SSH))
+	for _, s := range c.SSH {
+		agents = append(agents, buildkit.SSH{ID: s.ID, Paths: s.Paths})
+	}
+
+	return buildkit.NewSession(secrets, agents)
+}
+
+// buildKitBuildInfo is implemented by the ImageBuildInfo able to build the image with BuildKit.
+type buildKitBuildInfo interface {
+	usesBuildKit() bool
+	buildCaches() (buildCaches, error)
+	buildSession() (*buildkit.Session, error)
+}
+
+// buildKitBuild is the state of a BuildKit build: the session serving the secrets
+// and the SSH agents, and the images loaded from the archive caches.
+// A nil buildKitBuild is a build with the classic builder.
+type buildKitBuild struct {
+	provider    *DockerProvider
+	session     *buildkit.Session
+	caches      buildCaches
+	cacheImages []string
+	progress    *buildkit.Progress
+}
+
+// startBuildKitBuild starts the BuildKit session of the build and loads its archive caches.
+func (p *DockerProvider) startBuildKitBuild(ctx context.Context, info buildKitBuildInfo, logWriter io.Writer) (*buildKitBuild, error) {
+	caches, err := info.buildCaches()
+	if err != nil {
+		return nil, fmt.Errorf("build caches: %w", err)
+	}
+
+	session, err := info.buildSession()
+	if err != nil {
+		return nil, fmt.Errorf("build session: %w", err)
+	}
+
+	err = session.Start(ctx, func(ctx context.Context, proto string, meta map[string][]string) (net.Conn, error) {
+		return p.client.DialHijack(ctx, "/session", proto, meta)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("start build session: %w", err)
+	}
+
+	bk := &buildKitBuild{
+		provider: p,
+		session:  session,
+		caches:   caches,
+		progress: buildkit.NewProgress(logWriter),
+	}
+
+	for _, dir := range caches.fromDirs {
+		images, err := p.loadBuildCache(ctx, dir)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("load build cache from %s: %w", dir, err), bk.close(ctx, ""))
+		}
+		bk.cacheImages = append(bk.cacheImages, images...)
+	}
+
+	return bk, nil
+}
+
+// apply sets the session and the images loaded from the archive caches in the build options.
+func (bk *buildKitBuild) apply(opts *client.ImageBuildOptions) {
+	if bk == nil {
+		return
+	}
+
+	opts.SessionID = bk.session.ID()
+	opts.CacheFrom = append(opts.CacheFrom, bk.cacheImages...)
+}
+
+// auxCallback prints the BuildKit build status held by the trace messages of the build output.
+func (bk *buildKitBuild) auxCallback(msg jsonstream.Message) {
+	if bk == nil || msg.ID != buildkit.TraceID || msg.Aux == nil {
+		return
+	}
+
+	if err := bk.progress.WriteTrace(*msg.Aux); err != nil {
+		bk.provider.Logger.Printf("Failed to decode the build status: %s", err)
+	}
+}
+
+// exportCache exports the cache of the built image to the archive caches.
+func (bk *buildKitBuild) exportCache(ctx context.Context, image string) error {
+	if bk == nil {
+		return nil
+	}
+
+	for _, dir := range bk.caches.toDirs {
+		if err := bk.provider.saveBuildCache(ctx, dir, image); err != nil {
+			return fmt.Errorf("save build cache to %s: %w", dir, err)
+		}
+	}
+
+	return nil
+}
+
+// close closes the session and removes the images loaded from the archive caches,
+// except the built image, which may have the same reference. Failing to remove a cache
+// image is not an error, as it does not affect the build.
+func (bk *buildKitBuild) close(ctx context.Context, image string) error {
+	if bk == nil {
+		return nil
+	}
+
+	for _, ref := range bk.cacheImages {
+		if ref == image {
+			continue
+		}
+
+		if _, err := bk.provider.client.ImageRemove(ctx, ref, client.ImageRemoveOptions{PruneChildren: true}); err != nil {
+			bk.provider.Logger.Printf("Failed to remove the build cache image %s: %s", ref, err)
+		}
+	}
+
+	if err := bk.session.Close(); err != nil {
+		return fmt.Errorf("close build session: %w", err)
+	}
+
+	return nil
+}
+
+// loadBuildCache loads the image archive of the archive cache directory,
+// returning the references of the loaded images. A missing archive is an empty cache.
+func (p *DockerProvider) loadBuildCache(ctx context.Context, dir string) ([]string, error) {
+	f, err := os.Open(filepath.Join(dir, buildCacheArchive))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("open cache archive: %w", err)
+	}
+	defer f.Close()
+
+	resp, err := p.client.ImageLoad(ctx, f, client.ImageLoadWithQuiet(true))
+	if err != nil {
+		return nil, fmt.Errorf("image load: %w", err)
+	}
+	defer resp.Close()
+
+	var images []string
+	dec := json.NewDecoder(bufio.NewReader(resp))
+	for {
+		var msg jsonstream.Message
+		if err := dec.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("decode image load output: %w", err)
+		}
+
+		if msg.Error != nil {
+			return nil, fmt.Errorf("image load: %s", msg.Error.Message)
+		}
+
+		if ref, ok := strings.CutPrefix(strings.TrimSpace(msg.Stream), "Loaded image: "); ok {
+			images = append(images, ref)
+		}
+	}
+
+	return images, nil
+}
+
+// saveBuildCache saves the image, built with the inline cache, as the image archive of the
+// archive cache directory. The archive is replaced atomically, so concurrent builds reading
+// the cache never see a partial archive.
+func (p *DockerProvider) saveBuildCache(ctx context.Context, dir string, image string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create cache directory: %w", err)
+	}
+
+	resp, err := p.client.ImageSave(ctx, []string{image})
+	if err != nil {
+		return fmt.Errorf("image save: %w", err)
+	}
+	defer resp.Close()
+
+	tmp, err := os.CreateTemp(dir, buildCacheArchive+".*")
+	if err != nil {
+		return fmt.Errorf("create cache archive: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := io.Copy(tmp, resp); err != nil {
+		return errors.Join(fmt.Errorf("write cache archive: %w", err), tmp.Close())
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close cache archive: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, buildCacheArchive)); err != nil {
+		return fmt.Errorf("rename cache archive: %w", err)
+	}
+
+	return nil
+}
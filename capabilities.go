@@ -0,0 +1,284 @@
+package testcontainers
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/moby/moby/api/types/system"
+	"github.com/moby/moby/client"
+	"github.com/moby/moby/client/pkg/versions"
+)
+
+// imageMountMinAPIVersion is the first Docker API version supporting the image mounts.
+const imageMountMinAPIVersion = "1.48"
+
+// Capability is a capability of the Docker daemon, see [Capabilities.Has] and [SkipUnless].
+type Capability string
+
+// The capabilities of the Docker daemon.
+const (
+	// CapabilityRootful is the capability of a daemon running as root. The Docker in Docker
+	// and k3s containers need it: a rootless daemon runs their privileged containers, but
+	// their root user is mapped to an unprivileged user of the host, so they cannot manage
+	// the cgroups, the mounts and the network of their nested containers.
+	CapabilityRootful Capability = "rootful"
+
+	// CapabilityRootless is the capability of a daemon running as a non-root user.
+	CapabilityRootless Capability = "rootless"
+
+	// CapabilityCgroupV1 is the capability of a daemon using the cgroup v1 hierarchy.
+	CapabilityCgroupV1 Capability = "cgroup-v1"
+
+	// CapabilityCgroupV2 is the capability of a daemon using the cgroup v2 unified hierarchy.
+	CapabilityCgroupV2 Capability = "cgroup-v2"
+
+	// CapabilityUsernsRemap is the capability of a daemon remapping the users of the
+	// containers to a user namespace.
+	CapabilityUsernsRemap Capability = "userns-remap"
+
+	// CapabilityIPv6 is the capability of a daemon whose default bridge network
+	// supports IPv6.
+	CapabilityIPv6 Capability = "ipv6"
+
+	// CapabilityLinuxContainers is the capability of a daemon running Linux containers.
+	CapabilityLinuxContainers Capability = "linux-containers"
+
+	// CapabilityAMD64 is the capability of a daemon running on an amd64 host.
+	CapabilityAMD64 Capability = "amd64"
+
+	// CapabilityARM64 is the capability of a daemon running on an arm64 host.
+	CapabilityARM64 Capability = "arm64"
+
+	// CapabilityImageMount is the capability of a daemon supporting the image mounts,
+	// see [WithImageMount].
+	CapabilityImageMount Capability = "image-mount"
+
+	// CapabilityMemoryLimit is the capability of a daemon limiting the memory of the containers.
+	CapabilityMemoryLimit Capability = "memory-limit"
+
+	// CapabilityCPULimit is the capability of a daemon limiting the CPUs of the containers,
+	// through the CFS quota.
+	CapabilityCPULimit Capability = "cpu-limit"
+
+	// CapabilityPidsLimit is the capability of a daemon limiting the number of processes
+	// of the containers.
+	CapabilityPidsLimit Capability = "pids-limit"
+
+	// CapabilityRuntimeRunc is the capability of a daemon with the runc runtime.
+	CapabilityRuntimeRunc Capability = "runtime-runc"
+
+	// CapabilityRuntimeCrun is the capability of a daemon with the crun runtime.
+	CapabilityRuntimeCrun Capability = "runtime-crun"
+
+	// CapabilityRuntimeGVisor is the capability of a daemon with the gVisor runtime, runsc.
+	CapabilityRuntimeGVisor Capability = "runtime-gvisor"
+)
+
+// runtimeCapabilityPrefix is the prefix of the capabilities of the runtimes by name,
+// see [CapabilityRuntime].
+const runtimeCapabilityPrefix = "runtime:"
+
+// CapabilityRuntime returns the capability of a daemon with the runtime of the given name,
+// as registered in the daemon, such as "kata" or "runsc". See [WithRuntime].
+func CapabilityRuntime(name string) Capability {
+	return Capability(runtimeCapabilityPrefix + name)
+}
+
+// runtimeCapabilities are the capabilities of the runtimes, by runtime binary.
+var runtimeCapabilities = map[string]Capability{
+	"runc":  CapabilityRuntimeRunc,
+	"crun":  CapabilityRuntimeCrun,
+	"runsc": CapabilityRuntimeGVisor,
+}
+
+// Capabilities are the capabilities of a Docker daemon, see [DaemonCapabilities].
+type Capabilities struct {
+	// APIVersion is the version of the Docker API used with the daemon.
+	APIVersion string
+
+	// ServerVersion is the version of the daemon.
+	ServerVersion string
+
+	// OSType is the type of the operating system of the containers, e.g. "linux".
+	OSType string
+
+	// Architecture is the architecture of the daemon host, normalized to the
+	// Go architecture names, e.g. "amd64" or "arm64".
+	Architecture string
+
+	// Rootless reports whether the daemon runs as a non-root user.
+	Rootless bool
+
+	// CgroupVersion is the version of the cgroup hierarchy, "1" or "2".
+	CgroupVersion string
+
+	// StorageDriver is the storage driver of the daemon, e.g. "overlay2".
+	StorageDriver string
+
+	// UsernsRemap reports whether the daemon remaps the users of the containers.
+	UsernsRemap bool
+
+	// IPv6 reports whether the default bridge network of the daemon supports IPv6.
+	IPv6 bool
+
+	// Runtimes are the names of the runtimes of the daemon, sorted.
+	Runtimes []string
+
+	// DefaultRuntime is the name of the default runtime of the daemon.
+	DefaultRuntime string
+
+	// NCPU is the number of CPUs of the daemon host.
+	NCPU int
+
+	// MemoryLimit, CPULimit and PidsLimit report whether the daemon limits the memory,
+	// the CPUs and the number of processes of the containers. A rootless daemon with the
+	// cgroup v1 hierarchy cannot limit any resource.
+	MemoryLimit bool
+	CPULimit    bool
+	PidsLimit   bool
+
+	// runtimes are the capabilities of the runtimes of the daemon.
+	runtimes []Capability
+}
+
+// Has returns true if the daemon has the given capability.
+func (c Capabilities) Has(capability Capability) bool {
+	switch capability {
+	case CapabilityRootful:
+		return !c.Rootless
+	case CapabilityRootless:
+		return c.Rootless
+	case CapabilityCgroupV1:
+		return c.CgroupVersion == "1"
+	case CapabilityCgroupV2:
+		return c.CgroupVersion == "2"
+	case CapabilityUsernsRemap:
+		return c.UsernsRemap
+	case CapabilityIPv6:
+		return c.IPv6
+	case CapabilityLinuxContainers:
+		return c.OSType == "linux"
+	case CapabilityAMD64, CapabilityARM64:
+		return c.Architecture == string(capability)
+	case CapabilityMemoryLimit:
+		return c.MemoryLimit && c.resourceLimits()
+	case CapabilityCPULimit:
+		return c.CPULimit && c.resourceLimits()
+	case CapabilityPidsLimit:
+		return c.PidsLimit && c.resourceLimits()
+	case CapabilityImageMount:
+		return c.APIVersion != "" && versions.GreaterThanOrEqualTo(c.APIVersion, imageMountMinAPIVersion)
+	case CapabilityRuntimeRunc, CapabilityRuntimeCrun, CapabilityRuntimeGVisor:
+		return slices.Contains(c.runtimes, capability)
+	default:
+		if name, ok := strings.CutPrefix(string(capability), runtimeCapabilityPrefix); ok {
+			return slices.Contains(c.Runtimes, name)
+		}
+		return false
+	}
+}
+
+// resourceLimits returns false if the daemon cannot limit the resources of the containers at all,
+// which is the case of a rootless daemon with the cgroup v1 hierarchy, whatever the kernel supports.
+func (c Capabilities) resourceLimits() bool {
+	return !c.Rootless || c.CgroupVersion != "1"
+}
+
+// Missing returns the given capabilities the daemon does not have.
+func (c Capabilities) Missing(capabilities ...Capability) []Capability {
+	var missing []Capability
+	for _, capability := range capabilities {
+		if !c.Has(capability) {
+			missing = append(missing, capability)
+		}
+	}
+
+	return missing
+}
+
+// DaemonCapabilities returns the capabilities of the Docker daemon, from its information,
+// which is retrieved once per Docker host, and from its default bridge network.
+func DaemonCapabilities(ctx context.Context) (Capabilities, error) {
+	cli, err := NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return Capabilities{}, fmt.Errorf("new docker client: %w", err)
+	}
+	defer cli.Close()
+
+	return daemonCapabilities(ctx, cli)
+}
+
+// daemonCapabilities returns the capabilities of the Docker daemon the given client connects to.
+func daemonCapabilities(ctx context.Context, cli client.APIClient) (Capabilities, error) {
+	res, err := cli.Info(ctx, client.InfoOptions{})
+	if err != nil {
+		return Capabilities{}, fmt.Errorf("docker info: %w", err)
+	}
+
+	// the default bridge network has a different name for Podman, and may not be
+	// inspectable, in which case IPv6 is reported as not supported.
+	ipv6 := false
+	if bridge, err := cli.NetworkInspect(ctx, Bridge, client.NetworkInspectOptions{}); err == nil {
+		ipv6 = bridge.Network.EnableIPv6
+	}
+
+	return newCapabilities(res.Info, cli.ClientVersion(), ipv6), nil
+}
+
+// newCapabilities returns the capabilities of a daemon from its information.
+func newCapabilities(info system.Info, apiVersion string, ipv6 bool) Capabilities {
+	c := Capabilities{
+		APIVersion:     apiVersion,
+		ServerVersion:  info.ServerVersion,
+		OSType:         info.OSType,
+		Architecture:   normalizeArchitecture(info.Architecture),
+		CgroupVersion:  info.CgroupVersion,
+		StorageDriver:  info.Driver,
+		IPv6:           ipv6,
+		DefaultRuntime: info.DefaultRuntime,
+		NCPU:           info.NCPU,
+		MemoryLimit:    info.MemoryLimit,
+		CPULimit:       info.CPUCfsQuota,
+		PidsLimit:      info.PidsLimit,
+	}
+
+	for _, opt := range info.SecurityOptions {
+		switch opt {
+		case "name=rootless":
+			c.Rootless = true
+		case "name=userns":
+			c.UsernsRemap = true
+		}
+	}
+
+	for name, runtime := range info.Runtimes {
+		c.Runtimes = append(c.Runtimes, name)
+
+		// the runtimes are identified by their binary, as their names are user defined.
+		binary := name
+		if runtime.Path != "" {
+			binary = filepath.Base(runtime.Path)
+		}
+		if capability, ok := runtimeCapabilities[binary]; ok && !slices.Contains(c.runtimes, capability) {
+			c.runtimes = append(c.runtimes, capability)
+		}
+	}
+	slices.Sort(c.Runtimes)
+
+	return c
+}
+
+// normalizeArchitecture returns the Go name of the given architecture, as reported by the daemon.
+func normalizeArchitecture(arch string) string {
+	switch strings.ToLower(arch) {
+	case "x86_64", "x86-64", "amd64":
+		return "amd64"
+	case "aarch64", "arm64":
+		return "arm64"
+	default:
+		return arch
+	}
+}
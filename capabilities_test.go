@@ -0,0 +1,64 @@
+package testcontainers
+
+import (
+	"testing"
+
+	"github.com/moby/moby/api/types/system"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCapabilities(t *testing.T) {
+	info := system.Info{
+		ServerVersion:   "28.0.1",
+		OSType:          "linux",
+		Architecture:    "aarch64",
+		CgroupVersion:   "2",
+		Driver:          "overlay2",
+		SecurityOptions: []string{"name=seccomp,profile=builtin", "name=rootless", "name=cgroupns"},
+		Runtimes: map[string]system.RuntimeWithStatus{
+			"runc":   {Runtime: system.Runtime{Path: "runc"}},
+			"gvisor": {Runtime: system.Runtime{Path: "/usr/local/bin/runsc"}},
+		},
+		DefaultRuntime: "runc",
+	}
+
+	c := newCapabilities(info, "1.48", false)
+	require.Equal(t, "arm64", c.Architecture)
+	require.True(t, c.Rootless)
+	require.False(t, c.UsernsRemap)
+	require.Equal(t, "overlay2", c.StorageDriver)
+	require.Equal(t, []string{"gvisor", "runc"}, c.Runtimes)
+
+	for _, capability := range []Capability{
+		CapabilityRootless, CapabilityCgroupV2, CapabilityLinuxContainers, CapabilityARM64,
+		CapabilityImageMount, CapabilityRuntimeRunc, CapabilityRuntimeGVisor,
+	} {
+		require.Truef(t, c.Has(capability), "capability %s", capability)
+	}
+
+	require.Equal(t,
+		[]Capability{CapabilityRootful, CapabilityCgroupV1, CapabilityUsernsRemap, CapabilityIPv6, CapabilityAMD64, CapabilityRuntimeCrun},
+		c.Missing(
+			CapabilityRootful, CapabilityCgroupV1, CapabilityUsernsRemap, CapabilityIPv6, CapabilityAMD64,
+			CapabilityRuntimeCrun, CapabilityRuntimeRunc,
+		),
+	)
+
+	t.Run("old-api", func(t *testing.T) {
+		c := newCapabilities(system.Info{Architecture: "x86_64", SecurityOptions: []string{"name=userns"}}, "1.47", true)
+		require.Equal(t, "amd64", c.Architecture)
+		require.True(t, c.Has(CapabilityRootful))
+		require.True(t, c.Has(CapabilityUsernsRemap))
+		require.True(t, c.Has(CapabilityIPv6))
+		require.False(t, c.Has(CapabilityImageMount))
+	})
+}
+
+func TestSkipUnless(t *testing.T) {
+	SkipUnless(t, CapabilityLinuxContainers)
+
+	c, err := DaemonCapabilities(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "linux", c.OSType)
+	require.NotEmpty(t, c.APIVersion)
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/distribution/reference"
+)
+
+// runFuncs are the names of the functions whose second argument is an image.
+var runFuncs = []string{"Run", "RunContainer"}
+
+// findImages returns the sorted images referenced by the Go files of the packages.
+func findImages(packages []string) ([]string, error) {
+	var images []string
+	for _, pkg := range packages {
+		dir, recursive := strings.CutSuffix(pkg, "/...")
+
+		dirs := []string{dir}
+		if recursive {
+			dirs = dirs[:0]
+			err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+
+				if !d.IsDir() {
+					return nil
+				}
+
+				if path != dir && (d.Name() == "testdata" || d.Name() == "vendor" || strings.HasPrefix(d.Name(), ".")) {
+					return filepath.SkipDir
+				}
+
+				dirs = append(dirs, path)
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("walk %s: %w", dir, err)
+			}
+		}
+
+		for _, d := range dirs {
+			found, err := findPackageImages(d)
+			if err != nil {
+				return nil, err
+			}
+			images = append(images, found...)
+		}
+	}
+
+	slices.Sort(images)
+	return slices.Compact(images), nil
+}
+
+// findPackageImages returns the images referenced by the Go files of the directory.
+func findPackageImages(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read package %s: %w", dir, err)
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, filepath.Join(dir, entry.Name()), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", entry.Name(), err)
+		}
+		files = append(files, f)
+	}
+
+	consts := stringConstants(files)
+
+	var images []string
+	add := func(expr ast.Expr) {
+		var value string
+		switch e := expr.(type) {
+		case *ast.BasicLit:
+			if e.Kind != token.STRING {
+				return
+			}
+			value, _ = strconv.Unquote(e.Value)
+		case *ast.Ident:
+			value = consts[e.Name]
+		}
+
+		if isImage(value) {
+			images = append(images, value)
+		}
+	}
+
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.CallExpr:
+				if len(n.Args) >= 2 && slices.Contains(runFuncs, funcName(n.Fun)) {
+					add(n.Args[1])
+				}
+			case *ast.KeyValueExpr:
+				if key, ok := n.Key.(*ast.Ident); ok && key.Name == "Image" {
+					add(n.Value)
+				}
+			}
+			return true
+		})
+	}
+
+	return images, nil
+}
+
+// stringConstants returns the string literals assigned to the constants and variables of the files.
+func stringConstants(files []*ast.File) map[string]string {
+	consts := map[string]string{}
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			decl, ok := n.(*ast.GenDecl)
+			if !ok || (decl.Tok != token.CONST && decl.Tok != token.VAR) {
+				return true
+			}
+
+			for _, spec := range decl.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for i, name := range vs.Names {
+					if i >= len(vs.Values) {
+						break
+					}
+
+					if lit, ok := vs.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+						if value, err := strconv.Unquote(lit.Value); err == nil {
+							consts[name.Name] = value
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	return consts
+}
+
+// funcName returns the name of the called function, without its package or receiver.
+func funcName(fun ast.Expr) string {
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f.Name
+	case *ast.SelectorExpr:
+		return f.Sel.Name
+	default:
+		return ""
+	}
+}
+
+// isImage returns true if the value is an image reference.
+func isImage(value string) bool {
+	if value == "" || strings.Contains(value, "$") {
+		return false
+	}
+
+	_, err := reference.ParseNormalizedNamed(value)
+	return err == nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindImages(t *testing.T) {
+	images, err := findImages([]string{"testdata/pkg"})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"docker.io/postgres:16-alpine",
+		"ghcr.io/org/app:1.0",
+		"nginx:1.27-alpine",
+		"redis:7",
+	}, images)
+
+	images, err = findImages([]string{"testdata/pkg/..."})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"alpine@sha256:1e42bbe2508154c9126d48c2b8a75420c3544343bf86fd041fb7527e017a4b4a",
+		"docker.io/postgres:16-alpine",
+		"ghcr.io/org/app:1.0",
+		"nginx:1.27-alpine",
+		"redis:7",
+	}, images)
+}
+
+func TestArchiveName(t *testing.T) {
+	require.Equal(t, "ghcr.io_org_app_1.0.tar", archiveName("ghcr.io/org/app:1.0"))
+	require.Equal(t, "redis_7.tar", archiveName("redis:7"))
+}
@@ -0,0 +1,94 @@
+// Command imagecache populates a directory with the tarballs of the images referenced by
+// Go test packages, to be loaded with testcontainers.EnsureImagesFromArchive, or with the
+// image.archive.dir property, in environments without access to the registries.
+//
+// Usage:
+//
+//	go run github.com/testcontainers/testcontainers-go/cmd/imagecache -dir <cache directory> [packages]
+//
+// The packages are directories, where a "/..." suffix includes the subdirectories, being the
+// current directory by default. The images are found in the Go files of the packages, including
+// the test files, as the second argument of the Run functions, and as the Image field of the
+// struct literals, either as a string literal or as a string constant of the same package.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+func main() {
+	dir := flag.String("dir", "", "directory of the image tarballs (required)")
+	list := flag.Bool("list", false, "list the images found in the packages, without saving them")
+	force := flag.Bool("force", false, "save the images whose tarball already exists")
+	flag.Parse()
+
+	if err := run(context.Background(), *dir, flag.Args(), *list, *force); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, dir string, packages []string, list, force bool) error {
+	if len(packages) == 0 {
+		packages = []string{"."}
+	}
+
+	images, err := findImages(packages)
+	if err != nil {
+		return err
+	}
+
+	if list {
+		for _, image := range images {
+			fmt.Println(image)
+		}
+		return nil
+	}
+
+	if dir == "" {
+		return errors.New("the -dir flag is required")
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	provider, err := testcontainers.NewDockerProvider()
+	if err != nil {
+		return fmt.Errorf("new docker provider: %w", err)
+	}
+	defer provider.Close()
+
+	for _, image := range images {
+		output := filepath.Join(dir, archiveName(image))
+		if _, err := os.Stat(output); err == nil && !force {
+			fmt.Printf("%s: %s already exists\n", image, output)
+			continue
+		}
+
+		if err := provider.PullImage(ctx, image); err != nil {
+			return fmt.Errorf("pull image %s: %w", image, err)
+		}
+
+		if err := provider.SaveImages(ctx, output, image); err != nil {
+			return fmt.Errorf("save image %s: %w", image, err)
+		}
+
+		fmt.Printf("%s: saved to %s\n", image, output)
+	}
+
+	return nil
+}
+
+// archiveName returns the name of the tarball of the image, e.g. "ghcr.io_org_app_1.0.tar".
+func archiveName(image string) string {
+	return strings.NewReplacer("/", "_", ":", "_", "@", "_").Replace(image) + ".tar"
+}
@@ -0,0 +1,25 @@
+package pkg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+const nginxImage = "nginx:1.27-alpine"
+
+func TestImages(t *testing.T) {
+	ctx := context.Background()
+
+	_, _ = testcontainers.Run(ctx, "redis:7", testcontainers.WithExposedPorts("6379/tcp"))
+	_, _ = testcontainers.Run(ctx, nginxImage)
+	_, _ = postgres.Run(ctx, "docker.io/postgres:16-alpine")
+
+	_ = testcontainers.ContainerRequest{Image: "ghcr.io/org/app:1.0"}
+
+	// not images
+	_, _ = testcontainers.Run(ctx, "")
+	_ = testcontainers.ContainerRequest{Image: "Not an Image"}
+}
@@ -0,0 +1,13 @@
+package sub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+func TestSub(t *testing.T) {
+	_, _ = testcontainers.Run(context.Background(), "redis:7")
+	_, _ = testcontainers.Run(context.Background(), "alpine@sha256:1e42bbe2508154c9126d48c2b8a75420c3544343bf86fd041fb7527e017a4b4a")
+}
@@ -1,6 +1,9 @@
 package testcontainers
 
 import (
+	"strconv"
+	"time"
+
 	"github.com/testcontainers/testcontainers-go/internal/config"
 )
 
@@ -27,3 +30,116 @@ func ReadConfig() TestcontainersConfig {
 		Config:         cfg,
 	}
 }
+
+// ConfigOption sets a configuration property from code, see [Configure].
+type ConfigOption = config.Option
+
+// ConfigurationReport is the report of the configuration: its effective values and their
+// sources, and the problems found in the properties file. See [ConfigReport].
+type ConfigurationReport = config.Report
+
+// ConfigSetting is the effective value of a configuration property, with its source.
+type ConfigSetting = config.Setting
+
+// ConfigSource is the source of a configuration value.
+type ConfigSource = config.Source
+
+// The sources of the configuration values, in increasing order of precedence.
+const (
+	ConfigSourceDefault = config.SourceDefault
+	ConfigSourceProject = config.SourceProject
+	ConfigSourceFile    = config.SourceFile
+	ConfigSourceCode    = config.SourceCode
+	ConfigSourceEnv     = config.SourceEnv
+)
+
+// ErrConfigAlreadyRead is returned by [Configure] when the configuration is already read.
+var ErrConfigAlreadyRead = config.ErrAlreadyRead
+
+// Configure sets configuration values from code, which override the values of the properties
+// file, and are overridden by the environment variables. It must be called before the first
+// use of the library, e.g. in TestMain, as the configuration is read once: it returns
+// [ErrConfigAlreadyRead] otherwise. No value is set if any of the options is invalid.
+func Configure(opts ...ConfigOption) error {
+	return config.Configure(opts...)
+}
+
+// ConfigReport returns the effective configuration values and their sources: default, file,
+// code or env, and the unknown properties and invalid values found in the properties file,
+// which are ignored. It reads the configuration if not read yet.
+func ConfigReport() ConfigurationReport {
+	return config.ReadReport()
+}
+
+// ConfigProperty sets the given property, as in the properties file, e.g. "ryuk.verbose".
+func ConfigProperty(key, value string) ConfigOption {
+	return ConfigOption{Key: key, Value: value}
+}
+
+// ConfigHost sets the Docker host, as the docker.host property.
+func ConfigHost(host string) ConfigOption {
+	return ConfigProperty("docker.host", host)
+}
+
+// ConfigTestcontainersHost sets the Testcontainers host, as the tc.host property.
+func ConfigTestcontainersHost(host string) ConfigOption {
+	return ConfigProperty("tc.host", host)
+}
+
+// ConfigHubImageNamePrefix sets the prefix of the Docker Hub images, as the
+// hub.image.name.prefix property.
+func ConfigHubImageNamePrefix(prefix string) ConfigOption {
+	return ConfigProperty("hub.image.name.prefix", prefix)
+}
+
+// ConfigSessionID sets the session ID, as the session.id property.
+func ConfigSessionID(sessionID string) ConfigOption {
+	return ConfigProperty("session.id", sessionID)
+}
+
+// ConfigRyukDisabled disables the reaper, as the ryuk.disabled property.
+func ConfigRyukDisabled(disabled bool) ConfigOption {
+	return ConfigProperty("ryuk.disabled", strconv.FormatBool(disabled))
+}
+
+// ConfigRyukPrivileged runs the reaper in privileged mode, as the ryuk.container.privileged property.
+func ConfigRyukPrivileged(privileged bool) ConfigOption {
+	return ConfigProperty("ryuk.container.privileged", strconv.FormatBool(privileged))
+}
+
+// ConfigRyukVerbose enables the verbose logging of the reaper, as the ryuk.verbose property.
+func ConfigRyukVerbose(verbose bool) ConfigOption {
+	return ConfigProperty("ryuk.verbose", strconv.FormatBool(verbose))
+}
+
+// ConfigRyukConnectionTimeout sets the timeout of the connection to the reaper,
+// as the ryuk.connection.timeout property.
+func ConfigRyukConnectionTimeout(timeout time.Duration) ConfigOption {
+	return ConfigProperty("ryuk.connection.timeout", timeout.String())
+}
+
+// ConfigRyukReconnectionTimeout sets the time the reaper waits for a reconnection before
+// removing the resources, as the ryuk.reconnection.timeout property.
+func ConfigRyukReconnectionTimeout(timeout time.Duration) ConfigOption {
+	return ConfigProperty("ryuk.reconnection.timeout", timeout.String())
+}
+
+// ConfigHostAccessMode sets the host access mode, as the host.access.mode property.
+func ConfigHostAccessMode(mode string) ConfigOption {
+	return ConfigProperty("host.access.mode", mode)
+}
+
+// ConfigImageSubstitutions sets the image substitutions, as the image.substitutions property.
+func ConfigImageSubstitutions(substitutions string) ConfigOption {
+	return ConfigProperty("image.substitutions", substitutions)
+}
+
+// ConfigPullPolicy sets the default pull policy, as the pull.policy property.
+func ConfigPullPolicy(policy string) ConfigOption {
+	return ConfigProperty("pull.policy", policy)
+}
+
+// ConfigOCIRuntime sets the default OCI runtime of the containers, as the oci.runtime property.
+func ConfigOCIRuntime(name string) ConfigOption {
+	return ConfigProperty("oci.runtime", name)
+}
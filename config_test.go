@@ -0,0 +1,48 @@
+package testcontainers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/internal/config"
+)
+
+func TestConfigure(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", "") // Windows support
+	t.Setenv("TESTCONTAINERS_RYUK_DISABLED", "")
+	t.Setenv("RYUK_VERBOSE", "")
+	t.Setenv("RYUK_CONNECTION_TIMEOUT", "")
+	t.Setenv("TESTCONTAINERS_RYUK_CONNECTION_TIMEOUT", "")
+	t.Setenv("TESTCONTAINERS_PULL_POLICY", "")
+
+	config.Reset()
+	t.Cleanup(config.Reset)
+
+	require.NoError(t, Configure(
+		ConfigRyukVerbose(true),
+		ConfigRyukConnectionTimeout(30*time.Second),
+		ConfigPullPolicy(config.PullPolicyAlways),
+	))
+
+	// the environment variables win over the values set from code.
+	t.Setenv("TESTCONTAINERS_PULL_POLICY", config.PullPolicyNever)
+
+	cfg := ReadConfig().Config
+	require.True(t, cfg.RyukVerbose)
+	require.Equal(t, 30*time.Second, cfg.RyukConnectionTimeout)
+	require.Equal(t, config.PullPolicyNever, cfg.PullPolicy)
+
+	sources := map[string]ConfigSource{}
+	for _, s := range ConfigReport().Settings {
+		sources[s.Key] = s.Source
+	}
+	require.Equal(t, ConfigSourceCode, sources["ryuk.verbose"])
+	require.Equal(t, ConfigSourceCode, sources["ryuk.connection.timeout"])
+	require.Equal(t, ConfigSourceEnv, sources["pull.policy"])
+	require.Equal(t, ConfigSourceDefault, sources["ryuk.disabled"])
+
+	require.ErrorIs(t, Configure(ConfigRyukDisabled(true)), ErrConfigAlreadyRead)
+}
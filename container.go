@@ -2,6 +2,7 @@ package testcontainers
 
 import (
 	"archive/tar"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -10,11 +11,13 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cpuguy83/dockercfg"
 	"github.com/google/uuid"
 	"github.com/moby/go-archive"
+	"github.com/moby/moby/api/types/build"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/api/types/network"
 	"github.com/moby/moby/api/types/registry"
@@ -91,6 +94,7 @@ type ImageBuildInfo interface {
 type FromDockerfile struct {
 	Context        string                         // the path to the context of the docker build
 	ContextArchive io.ReadSeeker                  // the tar archive file to send to docker that contains the build context
+	Inline         *InlineContext                 // the Dockerfile and the files of the build context, assembled in memory
 	Dockerfile     string                         // the path from the context to the Dockerfile for the image, defaults to "Dockerfile"
 	Repo           string                         // the repo label for image, defaults to UUID
 	Tag            string                         // the tag label for image, defaults to UUID
@@ -102,10 +106,46 @@ type FromDockerfile struct {
 	// container image. Useful for images that are built from a Dockerfile and take a
 	// long time to build. Keeping the image also Docker to reuse it.
 	KeepImage bool
+	// BuildCache tags the image with the content hash of the build, replacing Tag,
+	// and reuses the image without building it when it already exists. It implies KeepImage.
+	// Use [PruneBuildCache] to remove the stale images.
+	BuildCache bool
 	// BuildOptionsModifier Modifier for the build options before image build. Use it for
 	// advanced configurations while building the image. Please consider that the modifier
 	// is called after the default build options are set.
 	BuildOptionsModifier func(*client.ImageBuildOptions)
+	// BuildKit builds the image with BuildKit instead of the classic builder. It's implied
+	// by Secrets, SSH, CacheTo and the archive cache sources in CacheFrom, which need BuildKit.
+	BuildKit bool
+	// Target is the stage to build in a multi-stage Dockerfile, defaults to the last stage.
+	Target string
+	// Secrets are the secrets exposed to the build through RUN --mount=type=secret,
+	// without being stored in the layers of the image.
+	Secrets []BuildSecret
+	// SSH are the SSH agents exposed to the build through RUN --mount=type=ssh.
+	SSH []BuildSSH
+	// CacheFrom are the cache sources of the build, either an image reference or an archive
+	// cache directory with "type=archive,src=<dir>". The "local" cache type of buildx is not supported.
+	CacheFrom []string
+	// CacheTo are the cache destinations of the build, either the image itself with
+	// "type=inline" or an archive cache directory with "type=archive,dest=<dir>".
+	CacheTo []string
+}
+
+// BuildSecret is a secret exposed to a BuildKit build, read either from a file or from
+// an environment variable. When neither File nor Env are set, the secret is read from
+// the environment variable named after its ID.
+type BuildSecret struct {
+	ID   string // the id used by RUN --mount=type=secret,id=<id>
+	File string // the path of the file holding the secret
+	Env  string // the environment variable holding the secret
+}
+
+// BuildSSH is an SSH agent exposed to a BuildKit build. Paths is either the path of an
+// agent socket or a list of private key files, defaults to the agent socket in SSH_AUTH_SOCK.
+type BuildSSH struct {
+	ID    string   // the id used by RUN --mount=type=ssh,id=<id>, defaults to "default"
+	Paths []string // the agent socket or the private key files
 }
 
 type ContainerFile struct {
@@ -132,6 +172,7 @@ func (c *ContainerFile) validate() error {
 type ContainerRequest struct {
 	FromDockerfile
 	HostAccessPorts          []int
+	SharedHostAccess         bool // attach the container to the session-wide host access container, see ExposeHostPort
 	Image                    string
 	ImageSubstitutors        []ImageSubstitutor
 	Entrypoint               []string
@@ -159,11 +200,17 @@ type ContainerRequest struct {
 	ReaperOptions            []ContainerOption                          // Deprecated: the reaper is configured at the properties level, for an entire test session
 	AutoRemove               bool                                       // Deprecated: Use HostConfigModifier instead. If set to true, the container will be removed from the host when stopped
 	AlwaysPullImage          bool                                       // Always pull image
+	ImagePullPolicy          PullPolicy                                 // Policy deciding whether to pull the image, it takes precedence over AlwaysPullImage
+	PullProgressConsumer     PullProgressConsumer                       // Consumer of the progress events of the pull of the image
+	ImageLock                *ImageLock                                 // Lock of the digest of the image, it takes precedence over the lock of the configuration
+	ImagePolicy              *ImagePolicy                               // Policy the image must satisfy, it takes precedence over the policy of the configuration
+	PreferIPv6               bool                                       // Prefer the IPv6 host bindings and loopback address when reporting the container endpoints
 	ImagePlatform            string                                     // ImagePlatform describes the platform which the image runs on.
+	Runtime                  string                                     // OCI runtime of the container, it takes precedence over the runtime of the configuration
 	Binds                    []string                                   // Deprecated: Use HostConfigModifier instead
 	ShmSize                  int64                                      // Deprecated: Use [HostConfigModifier] instead. Amount of memory shared with the host (in bytes)
-	CapAdd                   []string                                   // Deprecated: Use HostConfigModifier instead. Add Linux capabilities
-	CapDrop                  []string                                   // Deprecated: Use HostConfigModifier instead. Drop Linux capabilities
+	CapAdd                   []string                                   // Deprecated: Use [WithCapAdd] instead. Add Linux capabilities
+	CapDrop                  []string                                   // Deprecated: Use [WithCapDrop] instead. Drop Linux capabilities
 	ConfigModifier           func(*container.Config)                    // Modifier for the config before container creation
 	HostConfigModifier       func(*container.HostConfig)                // Modifier for the host config before container creation
 	EndpointSettingsModifier func(map[string]*network.EndpointSettings) // Modifier for the network settings before container creation
@@ -235,6 +282,15 @@ func (c *ContainerRequest) GetContext() (io.Reader, error) {
 		return c.ContextArchive, nil
 	}
 
+	if c.Inline != nil {
+		buildContext, err := c.Inline.tarArchive(c.GetDockerfile())
+		if err != nil {
+			return nil, fmt.Errorf("inline context: %w", err)
+		}
+
+		return bytes.NewReader(buildContext), nil
+	}
+
 	// always pass context as absolute path
 	abs, err := filepath.Abs(c.Context)
 	if err != nil {
@@ -333,6 +389,15 @@ func (c *ContainerRequest) GetAuthConfigs() map[string]registry.AuthConfig {
 
 // dockerFileImages returns the images from the request Dockerfile.
 func (c *ContainerRequest) dockerFileImages() ([]string, error) {
+	if c.Inline != nil {
+		buildContext, err := c.Inline.tarArchive(c.GetDockerfile())
+		if err != nil {
+			return nil, fmt.Errorf("inline context: %w", err)
+		}
+
+		return c.dockerFileImagesFromArchive(bytes.NewReader(buildContext))
+	}
+
 	if c.ContextArchive == nil {
 		// Source is a directory, we can read the Dockerfile directly.
 		images, err := core.ExtractImagesFromDockerfile(filepath.Join(c.Context, c.GetDockerfile()), c.GetBuildArgs())
@@ -344,8 +409,23 @@ func (c *ContainerRequest) dockerFileImages() ([]string, error) {
 	}
 
 	// Source is an archive, we need to read it to get the Dockerfile.
+	images, err := c.dockerFileImagesFromArchive(c.ContextArchive)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reset the archive to the beginning.
+	if _, err := c.ContextArchive.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("seek context archive to start: %w", err)
+	}
+
+	return images, nil
+}
+
+// dockerFileImagesFromArchive returns the images from the Dockerfile found in the tar archive.
+func (c *ContainerRequest) dockerFileImagesFromArchive(r io.Reader) ([]string, error) {
 	dockerFile := c.GetDockerfile()
-	tr := tar.NewReader(c.ContextArchive)
+	tr := tar.NewReader(r)
 
 	for {
 		hdr, err := tr.Next()
@@ -366,11 +446,6 @@ func (c *ContainerRequest) dockerFileImages() ([]string, error) {
 			return nil, fmt.Errorf("extract images from Dockerfile: %w", err)
 		}
 
-		// Reset the archive to the beginning.
-		if _, err := c.ContextArchive.Seek(0, io.SeekStart); err != nil {
-			return nil, fmt.Errorf("seek context archive to start: %w", err)
-		}
-
 		return images, nil
 	}
 }
@@ -383,10 +458,7 @@ func getAuthConfigsFromDockerfile(c *ContainerRequest) (map[string]registry.Auth
 	}
 
 	// Get the auth configs once for all images as it can be a time-consuming operation.
-	configs, err := getDockerAuthConfigs()
-	if err != nil {
-		return nil, err
-	}
+	configs := sync.OnceValues(getDockerAuthConfigs)
 
 	authConfigs := map[string]registry.AuthConfig{}
 	for _, image := range images {
@@ -407,11 +479,11 @@ func getAuthConfigsFromDockerfile(c *ContainerRequest) (map[string]registry.Auth
 }
 
 func (c *ContainerRequest) ShouldBuildImage() bool {
-	return c.Context != "" || c.ContextArchive != nil
+	return c.Context != "" || c.ContextArchive != nil || c.Inline != nil
 }
 
 func (c *ContainerRequest) ShouldKeepBuiltImage() bool {
-	return c.KeepImage
+	return c.KeepImage || c.BuildCache
 }
 
 // BuildLogWriter returns the io.Writer for output of log when building a Docker image from
@@ -436,9 +508,20 @@ func (c *ContainerRequest) BuildLogWriter() io.Writer {
 // It will apply some defaults and finally call the BuildOptionsModifier from the FromDockerfile struct,
 // if set.
 func (c *ContainerRequest) BuildOptions() (client.ImageBuildOptions, error) {
+	caches, err := c.buildCaches()
+	if err != nil {
+		return client.ImageBuildOptions{}, fmt.Errorf("build caches: %w", err)
+	}
+
 	buildOptions := client.ImageBuildOptions{
 		Remove:      true,
 		ForceRemove: true,
+		Target:      c.Target,
+		CacheFrom:   caches.fromImages,
+	}
+
+	if c.usesBuildKit() {
+		buildOptions.Version = build.BuilderBuildKit
 	}
 
 	if c.BuildOptionsModifier != nil {
@@ -447,6 +530,14 @@ func (c *ContainerRequest) BuildOptions() (client.ImageBuildOptions, error) {
 
 	// apply mandatory values after the modifier
 	buildOptions.BuildArgs = c.GetBuildArgs()
+	if caches.inline {
+		inlineCache := "1"
+		buildOptions.BuildArgs = maps.Clone(buildOptions.BuildArgs)
+		if buildOptions.BuildArgs == nil {
+			buildOptions.BuildArgs = map[string]*string{}
+		}
+		buildOptions.BuildArgs[buildKitInlineCache] = &inlineCache
+	}
 	buildOptions.Dockerfile = c.GetDockerfile()
 
 	// Make sure the auth configs from the Dockerfile are set right after the user-defined build options.
@@ -462,19 +553,9 @@ func (c *ContainerRequest) BuildOptions() (client.ImageBuildOptions, error) {
 	maps.Copy(buildOptions.AuthConfigs, authsFromDockerfile)
 
 	// make sure the first tag is the one defined in the ContainerRequest
-	tag := fmt.Sprintf("%s:%s", c.GetRepo(), c.GetTag())
-
-	// apply substitutors to the built image
-	for _, is := range c.ImageSubstitutors {
-		modifiedTag, err := is.Substitute(tag)
-		if err != nil {
-			return client.ImageBuildOptions{}, fmt.Errorf("failed to substitute image %s with %s: %w", tag, is.Description(), err)
-		}
-
-		if modifiedTag != tag {
-			log.Printf("✍🏼 Replacing image with %s. From: %s to %s\n", is.Description(), tag, modifiedTag)
-			tag = modifiedTag
-		}
+	tag, err := c.substituteBuildTag(fmt.Sprintf("%s:%s", c.GetRepo(), c.GetTag()))
+	if err != nil {
+		return client.ImageBuildOptions{}, err
 	}
 
 	if len(buildOptions.Tags) > 0 {
@@ -484,6 +565,14 @@ func (c *ContainerRequest) BuildOptions() (client.ImageBuildOptions, error) {
 		buildOptions.Tags = []string{tag}
 	}
 
+	if c.BuildCache {
+		if buildOptions.Labels == nil {
+			buildOptions.Labels = map[string]string{}
+		}
+		buildOptions.Labels[core.LabelBuildCache] = "true"
+		buildOptions.Labels[core.LabelBuildCacheID] = c.buildCacheID()
+	}
+
 	if !c.ShouldKeepBuiltImage() {
 		dst := GenericLabels()
 		if err = core.MergeCustomLabels(dst, c.Labels); err != nil {
@@ -506,16 +595,41 @@ func (c *ContainerRequest) BuildOptions() (client.ImageBuildOptions, error) {
 	return buildOptions, nil
 }
 
+// substituteBuildTag applies the image substitutors to the tag of the built image.
+func (c *ContainerRequest) substituteBuildTag(tag string) (string, error) {
+	for _, is := range c.ImageSubstitutors {
+		modifiedTag, err := is.Substitute(tag)
+		if err != nil {
+			return "", fmt.Errorf("failed to substitute image %s with %s: %w", tag, is.Description(), err)
+		}
+
+		if modifiedTag != tag {
+			log.Printf("✍🏼 Replacing image with %s. From: %s to %s\n", is.Description(), tag, modifiedTag)
+			tag = modifiedTag
+		}
+	}
+
+	return tag, nil
+}
+
 func (c *ContainerRequest) validateContextAndImage() error {
 	if c.Context != "" && c.Image != "" {
 		return errors.New("you cannot specify both an Image and Context in a ContainerRequest")
 	}
 
+	if c.Inline != nil && c.Image != "" {
+		return errors.New("you cannot specify both an Image and an Inline context in a ContainerRequest")
+	}
+
+	if c.Inline != nil && (c.Context != "" || c.ContextArchive != nil) {
+		return errors.New("you cannot specify both an Inline context and a Context or ContextArchive in a ContainerRequest")
+	}
+
 	return nil
 }
 
 func (c *ContainerRequest) validateContextOrImageIsSpecified() error {
-	if c.Context == "" && c.ContextArchive == nil && c.Image == "" {
+	if c.Context == "" && c.ContextArchive == nil && c.Inline == nil && c.Image == "" {
 		return errors.New("you must specify either a build context or an image")
 	}
 
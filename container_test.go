@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"testing"
 	"time"
 
@@ -51,6 +52,34 @@ func Test_ContainerValidation(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "can set inline context without image",
+			ContainerRequest: testcontainers.ContainerRequest{
+				FromDockerfile: testcontainers.FromDockerfile{
+					Inline: &testcontainers.InlineContext{Dockerfile: "FROM alpine"},
+				},
+			},
+		},
+		{
+			Name:          "cannot set both inline context and image",
+			ExpectedError: "you cannot specify both an Image and an Inline context in a ContainerRequest",
+			ContainerRequest: testcontainers.ContainerRequest{
+				FromDockerfile: testcontainers.FromDockerfile{
+					Inline: &testcontainers.InlineContext{Dockerfile: "FROM alpine"},
+				},
+				Image: "redis:latest",
+			},
+		},
+		{
+			Name:          "cannot set both inline context and context",
+			ExpectedError: "you cannot specify both an Inline context and a Context or ContextArchive in a ContainerRequest",
+			ContainerRequest: testcontainers.ContainerRequest{
+				FromDockerfile: testcontainers.FromDockerfile{
+					Context: ".",
+					Inline:  &testcontainers.InlineContext{Dockerfile: "FROM alpine"},
+				},
+			},
+		},
 		{
 			Name: "Can mount same source to multiple targets",
 			ContainerRequest: testcontainers.ContainerRequest{
@@ -310,6 +339,38 @@ func Test_BuildImageWithContexts(t *testing.T) {
 	}
 }
 
+func Test_BuildImageWithInlineContext(t *testing.T) {
+	ctx := context.Background()
+
+	// fromDockerfileWithInlineContext {
+	c, err := testcontainers.Run(
+		ctx, "",
+		testcontainers.WithDockerfile(testcontainers.FromDockerfile{
+			Inline: &testcontainers.InlineContext{
+				Dockerfile: `FROM alpine
+WORKDIR /app
+COPY . .
+CMD ["sh", "./scripts/say_hi.sh"]`,
+				Files: map[string]testcontainers.InlineFile{
+					"scripts/say_hi.sh": {Content: []byte("echo hi from $(cat message.txt)"), FileMode: 0o755},
+					"message.txt":       {Reader: strings.NewReader("the inline context")},
+					"data":              {HostPath: "testdata/data"},
+					".dockerignore":     {Content: []byte("data")},
+				},
+			},
+		}),
+		testcontainers.WithWaitStrategy(wait.ForLog("hi from the inline context").WithStartupTimeout(1*time.Minute)),
+	)
+	// }
+	testcontainers.CleanupContainer(t, c)
+	require.NoError(t, err)
+
+	// the data directory is excluded by the .dockerignore file.
+	code, _, err := c.Exec(ctx, []string{"test", "-e", "/app/data"})
+	require.NoError(t, err)
+	require.Equal(t, 1, code)
+}
+
 func TestCustomLabelsImage(t *testing.T) {
 	const (
 		myLabelName  = "org.my.label"
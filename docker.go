@@ -17,6 +17,7 @@ import (
 	"regexp"
 	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -27,6 +28,7 @@ import (
 	"github.com/moby/moby/api/pkg/authconfig"
 	"github.com/moby/moby/api/pkg/stdcopy"
 	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/image"
 	"github.com/moby/moby/api/types/network"
 	"github.com/moby/moby/client"
 	"github.com/moby/moby/client/pkg/jsonmessage"
@@ -95,6 +97,9 @@ type DockerContainer struct {
 	logger               log.Logger
 	lifecycleHooks       []ContainerLifecycleHooks
 
+	// preferIPv6 makes the container report its IPv6 host bindings and loopback address.
+	preferIPv6 bool
+
 	healthStatus container.HealthStatus // container health status, will default to healthStatusNone if no healthcheck is present
 }
 
@@ -163,13 +168,29 @@ func (c *DockerContainer) PortEndpoint(ctx context.Context, port string, proto s
 }
 
 // Host gets host (ip or name) of the docker daemon where the container port is exposed
-// Warning: this is based on your Docker host setting. Will fail if using an SSH tunnel
+// Warning: this is based on your Docker host setting. With an SSH Docker host it is the
+// local address of the SSH tunnels to the mapped ports, see [DockerContainer.MappedPort].
 // You can use the "TESTCONTAINERS_HOST_OVERRIDE" env variable to set this yourself
+//
+// If the container prefers IPv6, see [WithPreferIPv6], and the daemon is reachable on
+// localhost, it returns the IPv6 loopback address when the container has IPv6 port bindings.
 func (c *DockerContainer) Host(ctx context.Context) (string, error) {
 	host, err := c.provider.DaemonHost(ctx)
 	if err != nil {
 		return "", err
 	}
+
+	if c.preferIPv6 && host == "localhost" {
+		ipv6, err := c.hasIPv6Bindings(ctx)
+		if err != nil {
+			return "", fmt.Errorf("port bindings: %w", err)
+		}
+
+		if ipv6 {
+			return net.IPv6loopback.String(), nil
+		}
+	}
+
 	return host, nil
 }
 
@@ -204,10 +225,17 @@ func (c *DockerContainer) MappedPort(ctx context.Context, port string) (network.
 	}
 
 	if inspect.HostConfig.NetworkMode == "host" {
-		return nwPort, nil
+		return c.provider.localPort(ctx, nwPort)
 	}
 
 	ports := inspect.NetworkSettings.Ports
+	if inspect.HostConfig.NetworkMode.IsContainer() {
+		// The ports are published by the container whose network namespace is shared,
+		// such as the infra container of a pod.
+		if ports, err = c.sharedNetworkPorts(ctx, inspect.HostConfig.NetworkMode); err != nil {
+			return network.Port{}, err
+		}
+	}
 
 	for k, p := range ports {
 		if k.Num() != nwPort.Num() {
@@ -219,14 +247,56 @@ func (c *DockerContainer) MappedPort(ctx context.Context, port string) (network.
 		if len(p) == 0 {
 			continue
 		}
-		pNum, _ := strconv.ParseUint(p[0].HostPort, 10, 16)
+		pNum, _ := strconv.ParseUint(preferredBinding(p, c.preferIPv6).HostPort, 10, 16)
 		hPort, _ := network.PortFrom(uint16(pNum), k.Proto())
-		return hPort, nil
+		return c.provider.localPort(ctx, hPort)
 	}
 
 	return network.Port{}, errdefs.ErrNotFound.WithMessage(fmt.Sprintf("port %q not found", nwPort))
 }
 
+// sharedNetworkPorts returns the ports published by the container whose network namespace is
+// shared by the container, with the "container:<name|id>" network mode.
+func (c *DockerContainer) sharedNetworkPorts(ctx context.Context, mode container.NetworkMode) (network.PortMap, error) {
+	inspect, err := c.provider.client.ContainerInspect(ctx, mode.ConnectedContainer(), client.ContainerInspectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("inspect network container: %w", err)
+	}
+
+	return inspect.Container.NetworkSettings.Ports, nil
+}
+
+// preferredBinding returns the first host binding of the preferred IP family,
+// falling back to the first binding if there is none of that family.
+// The bindings must not be empty.
+func preferredBinding(bindings []network.PortBinding, preferIPv6 bool) network.PortBinding {
+	for _, b := range bindings {
+		if b.HostIP.IsValid() && b.HostIP.Unmap().Is6() == preferIPv6 {
+			return b
+		}
+	}
+
+	return bindings[0]
+}
+
+// hasIPv6Bindings returns true if any port of the container is bound to an IPv6 host address.
+func (c *DockerContainer) hasIPv6Bindings(ctx context.Context) (bool, error) {
+	inspect, err := c.Inspect(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, bindings := range inspect.NetworkSettings.Ports {
+		for _, b := range bindings {
+			if b.HostIP.IsValid() && b.HostIP.Unmap().Is6() {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
 // Deprecated: use c.Inspect(ctx).NetworkSettings.Ports instead.
 // Ports gets the exposed ports for the container.
 func (c *DockerContainer) Ports(ctx context.Context) (network.PortMap, error) {
@@ -354,6 +424,7 @@ func (c *DockerContainer) Terminate(ctx context.Context, opts ...TerminateOption
 	})
 	errs = append(errs, err)
 	errs = append(errs, c.terminatedHook(ctx))
+	core.ContainerTerminated(c.GetContainerID())
 
 	if c.imageWasBuilt && !c.keepBuiltImage {
 		_, err := c.provider.client.ImageRemove(ctx, c.Image, client.ImageRemoveOptions{
@@ -524,8 +595,24 @@ func (c *DockerContainer) ContainerIP(ctx context.Context) (string, error) {
 	return ip, nil
 }
 
-// ContainerIPs gets the IP addresses of all the networks within the container.
+// ContainerIPs gets the IPv4 addresses of all the networks within the container.
+// Use [DockerContainer.ContainerIPv6s] to get the IPv6 addresses.
 func (c *DockerContainer) ContainerIPs(ctx context.Context) ([]string, error) {
+	return c.ContainerIPv4s(ctx)
+}
+
+// ContainerIPv4s gets the IPv4 addresses of all the networks within the container.
+func (c *DockerContainer) ContainerIPv4s(ctx context.Context) ([]string, error) {
+	return c.containerIPsByFamily(ctx, false)
+}
+
+// ContainerIPv6s gets the global IPv6 addresses of all the IPv6-enabled networks within the container.
+func (c *DockerContainer) ContainerIPv6s(ctx context.Context) ([]string, error) {
+	return c.containerIPsByFamily(ctx, true)
+}
+
+// containerIPsByFamily gets the IP addresses of the given family of all the networks within the container.
+func (c *DockerContainer) containerIPsByFamily(ctx context.Context, ipv6 bool) ([]string, error) {
 	inspect, err := c.Inspect(ctx)
 	if err != nil {
 		return nil, err
@@ -534,8 +621,13 @@ func (c *DockerContainer) ContainerIPs(ctx context.Context) ([]string, error) {
 	networks := inspect.NetworkSettings.Networks
 	ips := make([]string, 0, len(networks))
 	for _, nw := range networks {
-		if nw.IPAddress.IsValid() {
-			ips = append(ips, nw.IPAddress.String())
+		ip := nw.IPAddress
+		if ipv6 {
+			ip = nw.GlobalIPv6Address
+		}
+
+		if ip.IsValid() {
+			ips = append(ips, ip.String())
 		}
 	}
 
@@ -543,6 +635,8 @@ func (c *DockerContainer) ContainerIPs(ctx context.Context) ([]string, error) {
 }
 
 // NetworkAliases gets the aliases of the container for the networks it is attached to.
+// The aliases are read from the daemon on every call, so they reflect the networks
+// connected or disconnected after the container was created.
 func (c *DockerContainer) NetworkAliases(ctx context.Context) (map[string][]string, error) {
 	inspect, err := c.Inspect(ctx)
 	if err != nil {
@@ -1010,7 +1104,7 @@ func (c *DockerContainer) connectReaper(ctx context.Context) error {
 		return nil
 	}
 
-	reaper, err := spawner.reaper(context.WithValue(ctx, core.DockerHostContextKey, c.provider.host), c.provider.config.SessionID, c.provider)
+	reaper, err := c.provider.reaperSpawner().reaper(context.WithValue(ctx, core.DockerHostContextKey, c.provider.host), c.provider.config.SessionID, c.provider)
 	if err != nil {
 		return fmt.Errorf("reaper: %w", err)
 	}
@@ -1056,6 +1150,68 @@ func (n *DockerNetwork) SetTerminationSignal(signal chan bool) {
 	n.terminationSignal = signal
 }
 
+// Connect attaches an already created container to the network, setting the given
+// network-scoped aliases, which are not supported on the "bridge" network.
+func (n *DockerNetwork) Connect(ctx context.Context, ctr Container, aliases ...string) error {
+	if n.Name == Bridge && len(aliases) > 0 {
+		return errors.New("network-scoped aliases are supported only for containers in user defined networks")
+	}
+
+	cli, created, err := n.dockerClient(ctx)
+	if err != nil {
+		return err
+	}
+	if created {
+		defer cli.Close()
+	}
+
+	_, err = cli.NetworkConnect(ctx, n.ID, client.NetworkConnectOptions{
+		Container:      ctr.GetContainerID(),
+		EndpointConfig: &network.EndpointSettings{Aliases: aliases},
+	})
+	if err != nil {
+		return fmt.Errorf("network connect: %w", err)
+	}
+
+	return nil
+}
+
+// Disconnect detaches a container from the network.
+func (n *DockerNetwork) Disconnect(ctx context.Context, ctr Container) error {
+	cli, created, err := n.dockerClient(ctx)
+	if err != nil {
+		return err
+	}
+	if created {
+		defer cli.Close()
+	}
+
+	_, err = cli.NetworkDisconnect(ctx, n.ID, client.NetworkDisconnectOptions{
+		Container: ctr.GetContainerID(),
+	})
+	if err != nil {
+		return fmt.Errorf("network disconnect: %w", err)
+	}
+
+	return nil
+}
+
+// dockerClient returns the client of the provider that created the network, which
+// the provider owns, or a new one if the network was not created by a provider, in
+// which case created is true and the caller must close it.
+func (n *DockerNetwork) dockerClient(ctx context.Context) (cli client.APIClient, created bool, err error) {
+	if n.provider != nil {
+		return n.provider.client, false, nil
+	}
+
+	cli, err = NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("docker client: %w", err)
+	}
+
+	return cli, true, nil
+}
+
 // DockerProvider implements the ContainerProvider interface
 type DockerProvider struct {
 	*DockerProviderOptions
@@ -1064,6 +1220,24 @@ type DockerProvider struct {
 	hostCache string
 	config    config.Config
 	mtx       sync.Mutex
+
+	// spawner and dockerSocket are the reaper spawner, and the path of the docker socket
+	// the reaper mounts, of the providers of other docker hosts than the one extracted
+	// from the different alternatives, such as the members of a ProviderPool.
+	spawner      *reaperSpawner
+	dockerSocket string
+
+	// podman is true for the providers of a Podman service, see PodmanProvider.
+	podman bool
+}
+
+// reaperSpawner returns the spawner of the reaper of the provider's docker host.
+func (p *DockerProvider) reaperSpawner() *reaperSpawner {
+	if p.spawner != nil {
+		return p.spawner
+	}
+
+	return spawner
 }
 
 // Client gets the docker client used by the provider
@@ -1088,7 +1262,18 @@ func (p *DockerProvider) SetClient(c client.APIClient) {
 var _ ContainerProvider = (*DockerProvider)(nil)
 
 // BuildImage will build and image from context and Dockerfile, then return the tag
-func (p *DockerProvider) BuildImage(ctx context.Context, img ImageBuildInfo) (string, error) {
+func (p *DockerProvider) BuildImage(ctx context.Context, img ImageBuildInfo) (tag string, err error) {
+	// BuildKit builds need a session serving the secrets and the SSH agents to the daemon.
+	var bk *buildKitBuild
+	if info, ok := img.(buildKitBuildInfo); ok && info.usesBuildKit() {
+		if bk, err = p.startBuildKitBuild(ctx, info, img.BuildLogWriter()); err != nil {
+			return "", err
+		}
+		defer func() {
+			err = errors.Join(err, bk.close(context.WithoutCancel(ctx), tag))
+		}()
+	}
+
 	var buildOptions client.ImageBuildOptions
 	resp, err := backoff.RetryNotifyWithData(
 		func() (client.ImageBuildResult, error) {
@@ -1099,6 +1284,8 @@ func (p *DockerProvider) BuildImage(ctx context.Context, img ImageBuildInfo) (st
 			}
 			defer tryClose(buildOptions.Context) // release resources in any case
 
+			bk.apply(&buildOptions)
+
 			resp, err := p.client.ImageBuild(ctx, buildOptions.Context, buildOptions)
 			if err != nil {
 				if isPermanentClientError(err) {
@@ -1123,12 +1310,18 @@ func (p *DockerProvider) BuildImage(ctx context.Context, img ImageBuildInfo) (st
 	// Always process the output, even if it is not printed
 	// to ensure that errors during the build process are
 	// correctly handled.
-	if err = jsonmessage.DisplayStream(resp.Body, img.BuildLogWriter()); err != nil {
+	if err = jsonmessage.DisplayStream(resp.Body, img.BuildLogWriter(), jsonmessage.WithAuxCallback(bk.auxCallback)); err != nil {
 		return "", fmt.Errorf("build image: %w", err)
 	}
 
 	// the first tag is the one we want
-	return buildOptions.Tags[0], nil
+	tag = buildOptions.Tags[0]
+
+	if err = bk.exportCache(ctx, tag); err != nil {
+		return "", fmt.Errorf("export build cache: %w", err)
+	}
+
+	return tag, nil
 }
 
 // CreateContainer fulfils a request for a container without starting it
@@ -1168,8 +1361,21 @@ func (p *DockerProvider) CreateContainer(ctx context.Context, req ContainerReque
 		return nil, err
 	}
 
-	// always append the hub substitutor after the user-defined ones
-	req.ImageSubstitutors = append(req.ImageSubstitutors, newPrependHubRegistry(p.config.HubImageNamePrefix))
+	// the image lock is applied to the images as defined by the user, before the substitutors of the configuration
+	imageLock, err := p.imageLock(&req)
+	if err != nil {
+		return nil, err
+	}
+	if imageLock != nil && !slices.Contains(req.ImageSubstitutors, ImageSubstitutor(imageLock)) {
+		req.ImageSubstitutors = append(req.ImageSubstitutors, imageLock)
+	}
+
+	// always append the substitutors of the configuration, and the hub one, after the user-defined ones
+	configSubstitutors, err := p.configImageSubstitutors()
+	if err != nil {
+		return nil, err
+	}
+	req.ImageSubstitutors = append(req.ImageSubstitutors, configSubstitutors...)
 
 	var platform *specs.Platform
 
@@ -1187,7 +1393,11 @@ func (p *DockerProvider) CreateContainer(ctx context.Context, req ContainerReque
 			return nil, err
 		}
 
-		imageName, err = p.BuildImage(ctx, &req)
+		if req.BuildCache {
+			imageName, err = p.buildCachedImage(ctx, &req)
+		} else {
+			imageName, err = p.BuildImage(ctx, &req)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -1197,7 +1407,13 @@ func (p *DockerProvider) CreateContainer(ctx context.Context, req ContainerReque
 			return nil, err
 		}
 	} else {
+		// lockedImage is the image as seen by the image lock, which keys the lockfile.
+		var lockedImage string
 		for _, is := range req.ImageSubstitutors {
+			if imageLock != nil && is == ImageSubstitutor(imageLock) {
+				lockedImage = imageName
+			}
+
 			modifiedTag, err := is.Substitute(imageName)
 			if err != nil {
 				return nil, fmt.Errorf("failed to substitute image %s with %s: %w", imageName, is.Description(), err)
@@ -1217,23 +1433,34 @@ func (p *DockerProvider) CreateContainer(ctx context.Context, req ContainerReque
 			platform = &p
 		}
 
-		var shouldPullImage bool
+		pullPolicy, err := p.pullPolicy(&req)
+		if err != nil {
+			return nil, err
+		}
 
-		if req.AlwaysPullImage {
-			shouldPullImage = true // If requested always attempt to pull image
-		} else {
-			img, err := p.client.ImageInspect(ctx, imageName)
+		local, err := p.localImage(ctx, imageName, platform)
+		if err != nil {
+			return nil, err
+		}
+
+		if local == nil {
+			loaded, err := p.loadImageFromArchive(ctx, imageName)
 			if err != nil {
-				if !errdefs.IsNotFound(err) {
+				return nil, fmt.Errorf("load image from archive: %w", err)
+			}
+
+			if loaded {
+				if local, err = p.localImage(ctx, imageName, platform); err != nil {
 					return nil, err
 				}
-				shouldPullImage = true
-			}
-			if platform != nil && (img.Architecture != platform.Architecture || img.Os != platform.OS) {
-				shouldPullImage = true
 			}
 		}
 
+		shouldPullImage, err := pullPolicy.ShouldPull(ctx, imageName, local)
+		if err != nil {
+			return nil, fmt.Errorf("pull policy: %w", err)
+		}
+
 		if shouldPullImage {
 			pullOpt := client.ImagePullOptions{}
 			if req.ImagePlatform != "" {
@@ -1241,10 +1468,16 @@ func (p *DockerProvider) CreateContainer(ctx context.Context, req ContainerReque
 					pullOpt.Platforms = append(pullOpt.Platforms, pf)
 				}
 			}
-			if err := p.attemptToPullImage(ctx, imageName, pullOpt); err != nil {
+			if err := p.attemptToPullImage(ctx, imageName, pullOpt, req.PullProgressConsumer); err != nil {
 				return nil, err
 			}
 		}
+
+		if lockedImage != "" {
+			if err := imageLock.check(ctx, p.client, lockedImage, imageName); err != nil {
+				return nil, fmt.Errorf("image lock: %w", err)
+			}
+		}
 	}
 
 	if !isReaperImage(imageName) {
@@ -1277,7 +1510,18 @@ func (p *DockerProvider) CreateContainer(ctx context.Context, req ContainerReque
 
 	// in the case the container needs to access a local port
 	// we need to forward the local port to the container
+	var hostGateway bool
 	if len(req.HostAccessPorts) > 0 {
+		if hostGateway, err = p.useHostGateway(ctx); err != nil {
+			return nil, fmt.Errorf("host access mode: %w", err)
+		}
+	}
+
+	if hostGateway {
+		// the host ports are directly reachable through the host-gateway of the daemon,
+		// so there is no need for the SSHD server.
+		withHostGateway(&req)
+	} else if len(req.HostAccessPorts) > 0 {
 		// a container lifecycle hook will be added, which will expose the host ports to the container
 		// using a SSHD server running in a container. The SSHD server will be started and will
 		// forward the host ports to the container ports.
@@ -1301,6 +1545,29 @@ func (p *DockerProvider) CreateContainer(ctx context.Context, req ContainerReque
 		defaultHooks = append(defaultHooks, sshdForwardPortsHook)
 	}
 
+	// in the case the container needs to access host ports exposed at runtime,
+	// it's attached to the session-wide SSHD container.
+	if req.SharedHostAccess {
+		if err := attachSharedHostAccess(ctx, &req); err != nil {
+			return nil, fmt.Errorf("shared host access: %w", err)
+		}
+
+		defer func() {
+			if err != nil && con == nil {
+				// The container was not created, so it no longer uses the sshd container.
+				err = errors.Join(err, releaseSharedHostAccess())
+			}
+		}()
+
+		defaultHooks = append(defaultHooks, ContainerLifecycleHooks{
+			PostTerminates: []ContainerHook{
+				func(_ context.Context, _ Container) error {
+					return releaseSharedHostAccess()
+				},
+			},
+		})
+	}
+
 	// Combine with the original LifecycleHooks to avoid duplicate logging hooks.
 	req.LifecycleHooks = []ContainerLifecycleHooks{
 		combineContainerHooks(defaultHooks, origLifecycleHooks),
@@ -1355,6 +1622,7 @@ func (p *DockerProvider) CreateContainer(ctx context.Context, req ContainerReque
 		provider:       p,
 		logger:         p.Logger,
 		lifecycleHooks: req.LifecycleHooks,
+		preferIPv6:     req.PreferIPv6,
 	}
 
 	if err = ctr.connectReaper(ctx); err != nil {
@@ -1444,7 +1712,7 @@ func (p *DockerProvider) ReuseOrCreateContainer(ctx context.Context, req Contain
 
 	var termSignal chan bool
 	if !p.config.RyukDisabled {
-		r, err := spawner.reaper(context.WithValue(ctx, core.DockerHostContextKey, p.host), sessionID, p)
+		r, err := p.reaperSpawner().reaper(context.WithValue(ctx, core.DockerHostContextKey, p.host), sessionID, p)
 		if err != nil {
 			return nil, fmt.Errorf("reaper: %w", err)
 		}
@@ -1479,6 +1747,7 @@ func (p *DockerProvider) ReuseOrCreateContainer(ctx context.Context, req Contain
 		terminationSignal: termSignal,
 		logger:            p.Logger,
 		lifecycleHooks:    []ContainerLifecycleHooks{combineContainerHooks(defaultHooks, req.LifecycleHooks)},
+		preferIPv6:        req.PreferIPv6,
 	}
 
 	// Workaround for https://github.com/moby/moby/issues/50133.
@@ -1521,9 +1790,61 @@ func (p *DockerProvider) ReuseOrCreateContainer(ctx context.Context, req Contain
 	return dc, nil
 }
 
+// localImage returns the image found in the Docker daemon, or nil if the image does not
+// exist or it does not match the platform, when not nil.
+func (p *DockerProvider) localImage(ctx context.Context, imageName string, platform *specs.Platform) (*image.InspectResponse, error) {
+	img, err := p.client.ImageInspect(ctx, imageName)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if platform != nil && (img.Architecture != platform.Architecture || img.Os != platform.OS) {
+		return nil, nil
+	}
+
+	return &img.InspectResponse, nil
+}
+
 // attemptToPullImage tries to pull the image while respecting the ctx cancellations.
 // Besides, if the image cannot be pulled due to ErrorNotFound then no need to retry but terminate immediately.
-func (p *DockerProvider) attemptToPullImage(ctx context.Context, tag string, pullOpt client.ImagePullOptions) error {
+// Concurrent pulls of the same image from the same Docker daemon are deduplicated: the callers wait for
+// the pull in progress, and progress receives its events, if not nil.
+func (p *DockerProvider) attemptToPullImage(ctx context.Context, tag string, pullOpt client.ImagePullOptions, progress PullProgressConsumer) error {
+	for {
+		call, leader := joinPull(p.host, tag, pullOpt.Platforms, progress)
+		if leader {
+			call.err = p.pullImage(ctx, tag, pullOpt, call)
+			call.finish()
+
+			return call.err
+		}
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		// the pull failed because the context of the caller doing it was done,
+		// so the callers still waiting for the image pull it again.
+		if isContextError(call.err) && ctx.Err() == nil {
+			continue
+		}
+
+		return call.err
+	}
+}
+
+// isContextError returns true if the error is caused by a canceled or expired context.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// pullImage pulls the image, sending the progress events of the pull to call.
+func (p *DockerProvider) pullImage(ctx context.Context, tag string, pullOpt client.ImagePullOptions, call *pullCall) error {
 	registry, imageAuth, err := DockerImageAuth(ctx, tag)
 	if err != nil {
 		p.Logger.Printf("No image auth found for %s. Setting empty credentials for the image: %s. This is expected for public images. Details: %s", registry, tag, err)
@@ -1536,7 +1857,7 @@ func (p *DockerProvider) attemptToPullImage(ctx context.Context, tag string, pul
 		}
 	}
 
-	var pull io.ReadCloser
+	var pull client.ImagePullResponse
 	err = backoff.RetryNotify(
 		func() error {
 			pull, err = p.client.ImagePull(ctx, tag, pullOpt)
@@ -1561,8 +1882,7 @@ func (p *DockerProvider) attemptToPullImage(ctx context.Context, tag string, pul
 	defer pull.Close()
 
 	// download of docker image finishes at EOF of the pull request
-	_, err = io.Copy(io.Discard, pull)
-	return err
+	return readPullProgress(pull, tag, call.accept)
 }
 
 // Health measure the healthiness of the provider. Right now we leverage the
@@ -1602,7 +1922,8 @@ func (p *DockerProvider) Config() TestcontainersConfig {
 }
 
 // DaemonHost gets the host or ip of the Docker daemon where ports are exposed on
-// Warning: this is based on your Docker host setting. Will fail if using an SSH tunnel
+// Warning: this is based on your Docker host setting. For the Docker hosts reached through SSH,
+// it is the local host, where the mapped ports are tunnelled to.
 // You can use the "TESTCONTAINERS_HOST_OVERRIDE" env variable to set this yourself
 func (p *DockerProvider) DaemonHost(ctx context.Context) (string, error) {
 	p.mtx.Lock()
@@ -1611,6 +1932,26 @@ func (p *DockerProvider) DaemonHost(ctx context.Context) (string, error) {
 	return p.daemonHostLocked(ctx)
 }
 
+// sshTunnelHost is the host of the local ports tunnelled to the ports of the Docker hosts reached through SSH.
+const sshTunnelHost = "127.0.0.1"
+
+// localPort returns the port of the Docker host reachable from the local host: the port itself, or the
+// local port tunnelled to it through the SSH connection, for the Docker hosts reached through SSH, unless
+// the "TESTCONTAINERS_HOST_OVERRIDE" env variable sets the host to reach them directly.
+func (p *DockerProvider) localPort(ctx context.Context, port network.Port) (network.Port, error) {
+	if _, exists := os.LookupEnv("TESTCONTAINERS_HOST_OVERRIDE"); exists || !core.IsSSHHost(p.host) || port.Proto() != network.TCP {
+		return port, nil
+	}
+
+	localPort, err := core.SSHTunnel(ctx, p.host, port.Num())
+	if err != nil {
+		return network.Port{}, fmt.Errorf("ssh tunnel to port %d: %w", port.Num(), err)
+	}
+
+	tunnelled, _ := network.PortFrom(localPort, network.TCP)
+	return tunnelled, nil
+}
+
 func (p *DockerProvider) daemonHostLocked(ctx context.Context) (string, error) {
 	if p.hostCache != "" {
 		return p.hostCache, nil
@@ -1618,6 +1959,10 @@ func (p *DockerProvider) daemonHostLocked(ctx context.Context) (string, error) {
 
 	host, exists := os.LookupEnv("TESTCONTAINERS_HOST_OVERRIDE")
 	if exists {
+		// IPv6 addresses may be bracketed, but the callers join the host with the port themselves.
+		if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+			host = host[1 : len(host)-1]
+		}
 		p.hostCache = host
 		return p.hostCache, nil
 	}
@@ -1632,7 +1977,22 @@ func (p *DockerProvider) daemonHostLocked(ctx context.Context) (string, error) {
 	switch daemonURL.Scheme {
 	case "http", "https", "tcp":
 		p.hostCache = daemonURL.Hostname()
+	case "ssh":
+		// the mapped ports are tunnelled to the local host through the SSH connection.
+		p.hostCache = sshTunnelHost
 	case "unix", "npipe":
+		if p.podman {
+			// a Docker compatible daemon without the libpod API, or a Podman service older
+			// than 4.0, has no Podman information, so its host is inferred as for Docker.
+			host, ok, err := p.podmanHostLocked(ctx, daemonURL)
+			if err != nil {
+				p.Logger.Printf("⚠️ Could not get the Podman information of %s, inferring the host as for Docker: %v", p.host, err)
+			} else if ok {
+				p.hostCache = host
+				return p.hostCache, nil
+			}
+		}
+
 		if core.InAContainer() {
 			defaultNetwork, err := p.ensureDefaultNetworkLocked(ctx)
 			if err != nil {
@@ -1683,7 +2043,7 @@ func (p *DockerProvider) CreateNetwork(ctx context.Context, req NetworkRequest)
 
 	var termSignal chan bool
 	if !p.config.RyukDisabled {
-		r, err := spawner.reaper(context.WithValue(ctx, core.DockerHostContextKey, p.host), sessionID, p)
+		r, err := p.reaperSpawner().reaper(context.WithValue(ctx, core.DockerHostContextKey, p.host), sessionID, p)
 		if err != nil {
 			return nil, fmt.Errorf("reaper: %w", err)
 		}
@@ -1932,7 +2292,7 @@ func SaveDockerImageWithPlatforms(platforms ...specs.Platform) SaveImageOption {
 
 // PullImage pulls image from registry
 func (p *DockerProvider) PullImage(ctx context.Context, img string) error {
-	return p.attemptToPullImage(ctx, img, client.ImagePullOptions{})
+	return p.attemptToPullImage(ctx, img, client.ImagePullOptions{}, nil)
 }
 
 // PullImageWithOpts pulls image from registry, passing options to the provider.
@@ -1945,7 +2305,24 @@ func (p *DockerProvider) PullImageWithOpts(ctx context.Context, img string, opts
 		}
 	}
 
-	return p.attemptToPullImage(ctx, img, pullOpts.dockerPullOpts)
+	if pullOpts.lock == nil {
+		return p.attemptToPullImage(ctx, img, pullOpts.dockerPullOpts, pullOpts.progress)
+	}
+
+	name, err := pullOpts.lock.Substitute(img)
+	if err != nil {
+		return fmt.Errorf("image lock: %w", err)
+	}
+
+	if err := p.attemptToPullImage(ctx, name, pullOpts.dockerPullOpts, pullOpts.progress); err != nil {
+		return err
+	}
+
+	if err := pullOpts.lock.check(ctx, p.client, img, name); err != nil {
+		return fmt.Errorf("image lock: %w", err)
+	}
+
+	return nil
 }
 
 func PullDockerImageWithPlatform(platform specs.Platform) PullImageOption {
@@ -1956,6 +2333,40 @@ func PullDockerImageWithPlatform(platform specs.Platform) PullImageOption {
 	}
 }
 
+// PullDockerImageWithProgress sends the progress events of the pull to the consumer.
+func PullDockerImageWithProgress(consumer PullProgressConsumer) PullImageOption {
+	return func(opts *pullImageOptions) error {
+		opts.progress = consumer
+
+		return nil
+	}
+}
+
+// PullDockerImageWithLock applies the image lock to the pulled images: in the [ImageLockRewrite]
+// mode, the locked images are pulled by digest, and the digests of the images not locked yet are
+// recorded into the lockfile.
+func PullDockerImageWithLock(lock *ImageLock) PullImageOption {
+	return func(opts *pullImageOptions) error {
+		opts.lock = lock
+
+		return nil
+	}
+}
+
+// PullDockerImagesWithParallelism sets the maximum number of images pulled concurrently
+// by [PrePullImagesWithOpts]. It defaults to 4.
+func PullDockerImagesWithParallelism(n int) PullImageOption {
+	return func(opts *pullImageOptions) error {
+		if n < 1 {
+			return fmt.Errorf("parallelism must be at least 1, got %d", n)
+		}
+
+		opts.parallelism = n
+
+		return nil
+	}
+}
+
 var permanentClientErrors = []func(error) bool{
 	errdefs.IsNotFound,
 	errdefs.IsInvalidArgument,
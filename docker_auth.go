@@ -27,20 +27,16 @@ var defaultRegistryFn = defaultRegistry
 var getRegistryCredentials = dockercfg.GetRegistryCredentials
 
 // DockerImageAuth returns the auth config for the given Docker image, extracting first its Docker registry.
-// Finally, it will use the credential helpers to extract the information from the docker config file
-// for that registry, if it exists.
+// It consults first the registered [RegistryAuthProvider]s, in order. Finally, it will use the credential
+// helpers to extract the information from the docker config file for that registry, if it exists.
 func DockerImageAuth(ctx context.Context, image string) (string, registry.AuthConfig, error) {
-	configs, err := getDockerAuthConfigs()
-	if err != nil {
-		reg := core.ExtractRegistry(image, defaultRegistryFn(ctx))
-		return reg, registry.AuthConfig{}, err
-	}
-
-	return dockerImageAuth(ctx, image, configs)
+	return dockerImageAuth(ctx, image, getDockerAuthConfigs)
 }
 
-// dockerImageAuth returns the auth config for the given Docker image.
-func dockerImageAuth(ctx context.Context, image string, configs map[string]registry.AuthConfig) (string, registry.AuthConfig, error) {
+// dockerImageAuth returns the auth config for the given Docker image, from the registered
+// providers, or from the configs returned by getConfigs, which is only called if none of
+// the providers has credentials for the registry.
+func dockerImageAuth(ctx context.Context, image string, getConfigs func() (map[string]registry.AuthConfig, error)) (string, registry.AuthConfig, error) {
 	defaultRegistry := defaultRegistryFn(ctx)
 	reg := core.ExtractRegistry(image, defaultRegistry)
 
@@ -51,6 +47,19 @@ func dockerImageAuth(ctx context.Context, image string, configs map[string]regis
 		reg = defaultRegistry // This is https://index.docker.io/v1/
 	}
 
+	cfg, ok, err := providedRegistryAuth(ctx, reg)
+	if err != nil {
+		return reg, registry.AuthConfig{}, err
+	}
+	if ok {
+		return reg, cfg, nil
+	}
+
+	configs, err := getConfigs()
+	if err != nil {
+		return reg, registry.AuthConfig{}, err
+	}
+
 	if cfg, ok := getRegistryAuth(reg, configs); ok {
 		return reg, cfg, nil
 	}
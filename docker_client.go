@@ -21,12 +21,15 @@ type DockerClient struct {
 	*client.Client // client is embedded into our own client
 
 	config config.Config
+
+	// dockerSocket is the path of the docker socket in the docker host, for the clients
+	// of other docker hosts than the one extracted from the different alternatives.
+	dockerSocket string
 }
 
 var (
-	// dockerInfo stores the docker info to be reused in the Info method
-	dockerInfo     client.SystemInfoResult
-	dockerInfoSet  bool
+	// dockerInfos stores the docker info of each docker host to be reused in the Info method
+	dockerInfos    = map[string]client.SystemInfoResult{}
 	dockerInfoLock sync.Mutex
 )
 
@@ -44,16 +47,15 @@ func (c *DockerClient) Events(ctx context.Context, options client.EventsListOpti
 func (c *DockerClient) Info(ctx context.Context, options client.InfoOptions) (client.SystemInfoResult, error) {
 	dockerInfoLock.Lock()
 	defer dockerInfoLock.Unlock()
-	if dockerInfoSet {
+	if dockerInfo, ok := dockerInfos[c.DaemonHost()]; ok {
 		return dockerInfo, nil
 	}
 
-	res, err := c.Client.Info(ctx, options)
+	dockerInfo, err := c.Client.Info(ctx, options)
 	if err != nil {
-		return res, fmt.Errorf("failed to retrieve docker info: %w", err)
+		return dockerInfo, fmt.Errorf("failed to retrieve docker info: %w", err)
 	}
-	dockerInfo = res
-	dockerInfoSet = true
+	dockerInfos[c.DaemonHost()] = dockerInfo
 
 	infoMessage := `%v - Connected to docker: 
   Server Version: %v
@@ -77,9 +79,12 @@ func (c *DockerClient) Info(ctx context.Context, options client.InfoOptions) (cl
 		infoLabels += infoLabelsSb72.String()
 	}
 
-	host, err := core.ExtractDockerHost(ctx)
-	if err != nil {
-		return dockerInfo, err
+	host, dockerSocket := c.DaemonHost(), c.dockerSocket
+	if dockerSocket == "" {
+		if host, err = core.ExtractDockerHost(ctx); err != nil {
+			return dockerInfo, err
+		}
+		dockerSocket = core.MustExtractDockerSocket(ctx)
 	}
 	log.Printf(infoMessage, packagePath,
 		dockerInfo.Info.ServerVersion,
@@ -88,7 +93,7 @@ func (c *DockerClient) Info(ctx context.Context, options client.InfoOptions) (cl
 		infoLabels,
 		internal.Version,
 		host,
-		core.MustExtractDockerSocket(ctx),
+		dockerSocket,
 		c.config.SessionID,
 		bootstrap.ProcessID(),
 	)
@@ -0,0 +1,104 @@
+package testcontainers
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/internal/config"
+	"github.com/testcontainers/testcontainers-go/internal/core"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestDockerProvider_sshHost(t *testing.T) {
+	ctx := context.Background()
+
+	cli, err := core.NewClientWithHost(ctx, "ssh://docker@remote.corp:2222")
+	require.NoError(t, err)
+
+	p := &DockerProvider{client: cli, host: "ssh://docker@remote.corp:2222"}
+
+	host, err := p.DaemonHost(ctx)
+	require.NoError(t, err)
+	require.Equal(t, sshTunnelHost, host)
+
+	// the udp ports cannot be tunnelled.
+	udp := network.MustParsePort("5353/udp")
+	port, err := p.localPort(ctx, udp)
+	require.NoError(t, err)
+	require.Equal(t, udp, port)
+
+	// the host override reaches the docker host directly.
+	t.Setenv("TESTCONTAINERS_HOST_OVERRIDE", "remote.corp")
+	tcp := network.MustParsePort("8080/tcp")
+	port, err = p.localPort(ctx, tcp)
+	require.NoError(t, err)
+	require.Equal(t, tcp, port)
+}
+
+// TestSSHDockerHost reaches the local docker daemon through an sshd container mounting
+// its socket, as a remote docker host reached through SSH. The sshd container uses the
+// host network, as the SSH tunnels reach the mapped ports on the loopback of the docker host.
+func TestSSHDockerHost(t *testing.T) {
+	ctx := context.Background()
+	SkipIfDockerDesktop(t, ctx)
+
+	t.Setenv("TESTCONTAINERS_SSH_INSECURE_IGNORE_HOST_KEY", "true")
+	config.Reset()
+	t.Cleanup(config.Reset)
+
+	// the sshd listens on a free port of the host, instead of the port of the host sshd.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	sshdPort := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+	require.NoError(t, listener.Close())
+
+	sshd, err := Run(ctx, sshdImage,
+		WithEnv(map[string]string{"PASSWORD": sshPassword}),
+		WithEntrypoint("/bin/sh", "-c"),
+		WithCmd(`echo "`+user+`:$PASSWORD" | chpasswd && exec /usr/sbin/sshd -D -e -p `+sshdPort+
+			` -o PermitRootLogin=yes -o AllowTcpForwarding=yes -o AllowStreamLocalForwarding=yes`),
+		WithHostConfigModifier(func(hc *container.HostConfig) {
+			hc.NetworkMode = "host"
+			hc.Binds = append(hc.Binds, core.MustExtractDockerSocket(ctx)+":/var/run/docker.sock")
+		}),
+		WithWaitStrategy(wait.ForLog("Server listening on")),
+	)
+	CleanupContainer(t, sshd)
+	require.NoError(t, err)
+
+	pool, err := NewProviderPool(ctx, []PoolHost{
+		{Host: "ssh://" + user + ":" + sshPassword + "@127.0.0.1:" + sshdPort},
+	})
+	require.NoError(t, err)
+	defer pool.Close()
+
+	nginx, err := Run(ctx, nginxAlpineImage,
+		WithProviderPool(pool),
+		WithExposedPorts(nginxDefaultPort),
+	)
+	CleanupContainer(t, nginx)
+	require.NoError(t, err)
+
+	host, err := nginx.Host(ctx)
+	require.NoError(t, err)
+	require.Equal(t, sshTunnelHost, host)
+
+	endpoint, err := nginx.PortEndpoint(ctx, nginxDefaultPort, "http")
+	require.NoError(t, err)
+
+	resp, err := http.Get(endpoint)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	_, err = io.Copy(io.Discard, resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -9,6 +9,7 @@ import (
 	"math/rand"
 	"net"
 	"net/http"
+	"net/netip"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -21,6 +22,7 @@ import (
 	"github.com/containerd/errdefs"
 	"github.com/containerd/platforms"
 	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
 	"github.com/moby/moby/client"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/require"
@@ -1885,7 +1887,7 @@ func TestDockerProvider_attemptToPullImage_retries(t *testing.T) {
 			// give a chance to retry
 			ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 			defer cancel()
-			_ = p.attemptToPullImage(ctx, "someTag", client.ImagePullOptions{})
+			_ = p.attemptToPullImage(ctx, "someTag", client.ImagePullOptions{}, nil)
 
 			require.Positive(t, m.imagePullCount)
 			require.Equal(t, tt.shouldRetry, m.imagePullCount > 1)
@@ -2012,3 +2014,35 @@ func TestMappedPortEmptyString(t *testing.T) {
 	require.True(t, port.IsZero(), "expected zero port for empty string input")
 	require.ErrorIs(t, err, errdefs.ErrNotFound)
 }
+
+func TestPreferredBinding(t *testing.T) {
+	v4 := network.PortBinding{HostIP: netip.MustParseAddr("0.0.0.0"), HostPort: "32768"}
+	v6 := network.PortBinding{HostIP: netip.MustParseAddr("::"), HostPort: "32769"}
+
+	t.Run("ipv4", func(t *testing.T) {
+		require.Equal(t, v4, preferredBinding([]network.PortBinding{v6, v4}, false))
+	})
+
+	t.Run("ipv6", func(t *testing.T) {
+		require.Equal(t, v6, preferredBinding([]network.PortBinding{v4, v6}, true))
+	})
+
+	t.Run("ipv6/fallback", func(t *testing.T) {
+		require.Equal(t, v4, preferredBinding([]network.PortBinding{v4}, true))
+	})
+
+	t.Run("no-host-ip", func(t *testing.T) {
+		b := network.PortBinding{HostPort: "32770"}
+		require.Equal(t, b, preferredBinding([]network.PortBinding{b}, false))
+	})
+}
+
+func TestDaemonHost_bracketedOverride(t *testing.T) {
+	t.Setenv("TESTCONTAINERS_HOST_OVERRIDE", "[::1]")
+
+	p := &DockerProvider{}
+
+	host, err := p.DaemonHost(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "::1", host)
+}
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/moby/moby/api/types/build"
 	"github.com/moby/moby/client"
 	"github.com/stretchr/testify/require"
 
@@ -210,3 +211,187 @@ func TestBuildImageFromDockerfile_TargetDoesNotExist(t *testing.T) {
 	CleanupContainer(t, ctr)
 	require.Error(t, err)
 }
+
+func TestBuildImageFromDockerfile_BuildKitSecret(t *testing.T) {
+	ctx := context.Background()
+
+	t.Setenv("TOKEN", "s3cr3t")
+
+	// buildKitSecrets {
+	ctr, err := Run(ctx, "",
+		WithDockerfile(FromDockerfile{
+			Context:    "testdata",
+			Dockerfile: "secret.Dockerfile",
+			Secrets: []BuildSecret{
+				{ID: "token", Env: "TOKEN"},
+			},
+		}),
+	)
+	// }
+	CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	r, err := ctr.Logs(ctx)
+	require.NoError(t, err)
+
+	logs, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "secret found\n", string(logs))
+}
+
+func TestBuildImageFromDockerfile_BuildKitSecretNotFound(t *testing.T) {
+	ctr, err := Run(context.Background(), "",
+		WithDockerfile(FromDockerfile{
+			Context:    "testdata",
+			Dockerfile: "secret.Dockerfile",
+			Secrets: []BuildSecret{
+				{ID: "token", Env: "TESTCONTAINERS_UNDEFINED_TOKEN"},
+			},
+		}),
+	)
+	CleanupContainer(t, ctr)
+	require.Error(t, err)
+}
+
+func TestBuildImageFromDockerfile_BuildKitArchiveCache(t *testing.T) {
+	provider, err := NewDockerProvider()
+	require.NoError(t, err)
+	defer provider.Close()
+
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+
+	build := func() string {
+		t.Helper()
+
+		// buildKitArchiveCache {
+		tag, err := provider.BuildImage(ctx, &ContainerRequest{
+			FromDockerfile: FromDockerfile{
+				Context:    "testdata",
+				Dockerfile: "target.Dockerfile",
+				Target:     "target1",
+				CacheFrom:  []string{"type=archive,src=" + cacheDir},
+				CacheTo:    []string{"type=archive,dest=" + cacheDir},
+			},
+		})
+		// }
+		require.NoError(t, err)
+
+		t.Cleanup(func() {
+			_, err := provider.Client().ImageRemove(ctx, tag, client.ImageRemoveOptions{
+				Force:         true,
+				PruneChildren: true,
+			})
+			require.NoError(t, err)
+		})
+
+		return tag
+	}
+
+	build()
+	require.FileExists(t, filepath.Join(cacheDir, buildCacheArchive))
+
+	// the second build imports the cache exported by the first one.
+	build()
+	require.FileExists(t, filepath.Join(cacheDir, buildCacheArchive))
+}
+
+func TestBuildCaches(t *testing.T) {
+	t.Run("registry-and-archive", func(t *testing.T) {
+		req := ContainerRequest{
+			FromDockerfile: FromDockerfile{
+				CacheFrom: []string{"myregistry/cache:latest", "type=registry,ref=other/cache", "type=archive,src=/tmp/cache"},
+				CacheTo:   []string{"type=archive,dest=/tmp/cache"},
+			},
+		}
+
+		caches, err := req.buildCaches()
+		require.NoError(t, err)
+		require.Equal(t, []string{"myregistry/cache:latest", "other/cache"}, caches.fromImages)
+		require.Equal(t, []string{"/tmp/cache"}, caches.fromDirs)
+		require.Equal(t, []string{"/tmp/cache"}, caches.toDirs)
+		require.True(t, caches.inline)
+		require.True(t, req.usesBuildKit())
+	})
+
+	t.Run("inline", func(t *testing.T) {
+		req := ContainerRequest{
+			FromDockerfile: FromDockerfile{
+				CacheTo: []string{"type=inline"},
+			},
+		}
+
+		caches, err := req.buildCaches()
+		require.NoError(t, err)
+		require.True(t, caches.inline)
+		require.Empty(t, caches.toDirs)
+	})
+
+	t.Run("images-only", func(t *testing.T) {
+		req := ContainerRequest{
+			FromDockerfile: FromDockerfile{
+				CacheFrom: []string{"myregistry/cache:latest"},
+			},
+		}
+
+		require.False(t, req.usesBuildKit())
+	})
+
+	t.Run("unsupported-export", func(t *testing.T) {
+		req := ContainerRequest{
+			FromDockerfile: FromDockerfile{
+				CacheTo: []string{"type=registry,ref=myregistry/cache"},
+			},
+		}
+
+		_, err := req.buildCaches()
+		require.ErrorContains(t, err, "unsupported cache destination type")
+	})
+
+	t.Run("missing-src", func(t *testing.T) {
+		req := ContainerRequest{
+			FromDockerfile: FromDockerfile{
+				CacheFrom: []string{"type=archive"},
+			},
+		}
+
+		_, err := req.buildCaches()
+		require.ErrorContains(t, err, "missing src")
+	})
+
+	t.Run("buildx-local", func(t *testing.T) {
+		for _, req := range []ContainerRequest{
+			{FromDockerfile: FromDockerfile{CacheFrom: []string{"type=local,src=/tmp/cache"}}},
+			{FromDockerfile: FromDockerfile{CacheTo: []string{"type=local,dest=/tmp/cache"}}},
+		} {
+			_, err := req.buildCaches()
+			require.ErrorIs(t, err, errLocalBuildCache)
+		}
+	})
+}
+
+func TestBuildOptions_buildKit(t *testing.T) {
+	req := ContainerRequest{
+		FromDockerfile: FromDockerfile{
+			Context:   "testdata",
+			Target:    "target1",
+			Secrets:   []BuildSecret{{ID: "token"}},
+			CacheFrom: []string{"myregistry/cache:latest"},
+			CacheTo:   []string{"type=inline"},
+			BuildArgs: map[string]*string{"FOO": nil},
+		},
+	}
+
+	opts, err := req.BuildOptions()
+	require.NoError(t, err)
+	defer tryClose(opts.Context)
+
+	require.Equal(t, build.BuilderBuildKit, opts.Version)
+	require.Equal(t, "target1", opts.Target)
+	require.Equal(t, []string{"myregistry/cache:latest"}, opts.CacheFrom)
+	require.Contains(t, opts.BuildArgs, "FOO")
+	require.Equal(t, "1", *opts.BuildArgs[buildKitInlineCache])
+
+	// the build args of the request are not modified.
+	require.NotContains(t, req.BuildArgs, buildKitInlineCache)
+}
@@ -19,11 +19,12 @@ var (
 
 // GenericContainerRequest represents parameters to a generic container
 type GenericContainerRequest struct {
-	ContainerRequest              // embedded request for provider
-	Started          bool         // whether to auto-start the container
-	ProviderType     ProviderType // which provider to use, Docker if empty
-	Logger           log.Logger   // provide a container specific Logging - use default global logger if empty
-	Reuse            bool         // reuse an existing container if it exists or create a new one. a container name mustn't be empty
+	ContainerRequest               // embedded request for provider
+	Started          bool          // whether to auto-start the container
+	ProviderType     ProviderType  // which provider to use, Docker if empty
+	Logger           log.Logger    // provide a container specific Logging - use default global logger if empty
+	Reuse            bool          // reuse an existing container if it exists or create a new one. a container name mustn't be empty
+	Pool             *ProviderPool // spread the container across the Docker hosts of the pool, instead of using ProviderType
 }
 
 // Deprecated: will be removed in the future.
@@ -59,13 +60,19 @@ func GenericContainer(ctx context.Context, req GenericContainerRequest) (Contain
 		// Ensure there is always a non-nil logger by default
 		logger = log.Default()
 	}
-	provider, err := req.ProviderType.GetProvider(WithLogger(logger))
-	if err != nil {
-		return nil, fmt.Errorf("get provider: %w", err)
+	var provider ContainerProvider = req.Pool
+	if req.Pool == nil {
+		p, err := req.ProviderType.GetProvider(WithLogger(logger))
+		if err != nil {
+			return nil, fmt.Errorf("get provider: %w", err)
+		}
+		defer p.Close()
+
+		provider = p
 	}
-	defer provider.Close()
 
 	var c Container
+	var err error
 	if req.Reuse {
 		// we must protect the reusability of the container in the case it's invoked
 		// in a parallel execution, via ParallelContainers or t.Parallel()
@@ -0,0 +1,237 @@
+package testcontainers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/testcontainers/testcontainers-go/internal/core/network"
+)
+
+// sharedHostAccess is the session-wide SSHD container shared by all the containers
+// created with [WithSharedHostAccess], exposing host ports on demand while they run.
+// It is terminated once no port is exposed and no attached container is left.
+var sharedHostAccess = struct {
+	mtx  sync.Mutex
+	sshd *sshdContainer
+	// defaultNetwork is the network the SSHD container was created on,
+	// used by the containers that do not define any network.
+	defaultNetwork string
+	forwarders     map[int]*portForwarder
+	// containers is the number of attached containers not terminated yet.
+	containers int
+}{
+	forwarders: map[int]*portForwarder{},
+}
+
+// ExposeHostPort exposes the given host port to the containers created with [WithSharedHostAccess],
+// including the ones already running, through a session-wide SSHD container which is started on
+// first use. It returns the address to reach the host port from those containers, which uses the
+// [HostInternal] hostname. Exposing an already exposed port is a no-op.
+func ExposeHostPort(ctx context.Context, port int) (string, error) {
+	sharedHostAccess.mtx.Lock()
+	defer sharedHostAccess.mtx.Unlock()
+
+	// the SSHD container is checked first, so the ports exposed through a lost
+	// SSHD container are exposed again through the new one.
+	sshd, err := ensureSharedHostAccessLocked(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	address := net.JoinHostPort(HostInternal, strconv.Itoa(port))
+	if _, ok := sharedHostAccess.forwarders[port]; ok {
+		return address, nil
+	}
+
+	pf, err := newPortForwarder(ctx, "localhost:"+sshd.port, sshd.sshConfig, port)
+	if err != nil {
+		return "", fmt.Errorf("new port forwarder: %w", err)
+	}
+
+	sharedHostAccess.forwarders[port] = pf
+
+	return address, nil
+}
+
+// UnexposeHostPort stops exposing the given host port, previously exposed with [ExposeHostPort],
+// to the containers. Unexposing a port that is not exposed is a no-op. Unexposing the last port
+// terminates the session-wide SSHD container when no container created with [WithSharedHostAccess]
+// is still running.
+func UnexposeHostPort(port int) error {
+	sharedHostAccess.mtx.Lock()
+	defer sharedHostAccess.mtx.Unlock()
+
+	pf, ok := sharedHostAccess.forwarders[port]
+	if !ok {
+		return nil
+	}
+
+	delete(sharedHostAccess.forwarders, port)
+
+	return errors.Join(pf.Close(), terminateSharedHostAccessLocked())
+}
+
+// ExposedHostPorts returns the host ports currently exposed with [ExposeHostPort].
+func ExposedHostPorts() []int {
+	sharedHostAccess.mtx.Lock()
+	defer sharedHostAccess.mtx.Unlock()
+
+	ports := make([]int, 0, len(sharedHostAccess.forwarders))
+	for port := range sharedHostAccess.forwarders {
+		ports = append(ports, port)
+	}
+
+	return ports
+}
+
+// ensureSharedHostAccessLocked starts the session-wide SSHD container if it's not running yet,
+// exposing the host ports of a previous SSHD container again, or returning an error naming the
+// ports it could not expose again, which are no longer exposed.
+// The container is labelled for the session, so the reaper removes it at the end of the session
+// if it is still running, see [terminateSharedHostAccessLocked].
+// It must be called with the sharedHostAccess mutex held.
+func ensureSharedHostAccessLocked(ctx context.Context) (*sshdContainer, error) {
+	if sharedHostAccess.sshd != nil && sharedHostAccess.sshd.IsRunning() {
+		return sharedHostAccess.sshd, nil
+	}
+
+	// The forwarders of a previous SSHD container are no longer usable.
+	ports := slices.Sorted(maps.Keys(sharedHostAccess.forwarders))
+	for _, port := range ports {
+		_ = sharedHostAccess.forwarders[port].Close()
+		delete(sharedHostAccess.forwarders, port)
+	}
+
+	sshd, err := newSshdContainer(ctx)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("new sshd container: %w", err), TerminateContainer(sshd))
+	}
+
+	inspect, err := sshd.Inspect(ctx)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("inspect sshd container: %w", err), TerminateContainer(sshd))
+	}
+
+	for name := range inspect.NetworkSettings.Networks {
+		sharedHostAccess.defaultNetwork = name
+	}
+
+	sharedHostAccess.sshd = sshd
+
+	var errs []error
+	for _, port := range ports {
+		pf, err := newPortForwarder(ctx, "localhost:"+sshd.port, sshd.sshConfig, port)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("host port %d is no longer exposed: %w", port, err))
+			continue
+		}
+
+		sharedHostAccess.forwarders[port] = pf
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("expose host ports on new sshd container: %w", errors.Join(errs...))
+	}
+
+	return sshd, nil
+}
+
+// terminateSharedHostAccessLocked terminates the session-wide SSHD container when no host port
+// is exposed and no attached container is left, so it does not outlive its users when the reaper
+// is disabled. It must be called with the sharedHostAccess mutex held.
+func terminateSharedHostAccessLocked() error {
+	if sharedHostAccess.sshd == nil || len(sharedHostAccess.forwarders) > 0 || sharedHostAccess.containers > 0 {
+		return nil
+	}
+
+	sshd := sharedHostAccess.sshd
+	sharedHostAccess.sshd = nil
+	sharedHostAccess.defaultNetwork = ""
+
+	if err := TerminateContainer(sshd); err != nil {
+		return fmt.Errorf("terminate sshd container: %w", err)
+	}
+
+	return nil
+}
+
+// releaseSharedHostAccess detaches a container attached with [attachSharedHostAccess],
+// once terminated, terminating the session-wide SSHD container if it was the last user.
+func releaseSharedHostAccess() error {
+	sharedHostAccess.mtx.Lock()
+	defer sharedHostAccess.mtx.Unlock()
+
+	if sharedHostAccess.containers > 0 {
+		sharedHostAccess.containers--
+	}
+
+	return terminateSharedHostAccessLocked()
+}
+
+// attachSharedHostAccess makes the session-wide SSHD container reachable from the container
+// in the request through the [HostInternal] hostname, connecting the SSHD container to the
+// first network of the request if needed. On success, the container is counted as a user of
+// the SSHD container until [releaseSharedHostAccess] is called.
+func attachSharedHostAccess(ctx context.Context, req *ContainerRequest) (err error) {
+	sharedHostAccess.mtx.Lock()
+	defer sharedHostAccess.mtx.Unlock()
+
+	sshd, err := ensureSharedHostAccessLocked(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, terminateSharedHostAccessLocked())
+		}
+	}()
+
+	networkName := hostAccessNetwork(req)
+
+	inspect, err := sshd.Inspect(ctx)
+	if err != nil {
+		return fmt.Errorf("inspect sshd container: %w", err)
+	}
+
+	if _, attached := inspect.NetworkSettings.Networks[networkName]; networkName != "" && !attached {
+		nw, err := network.GetByName(ctx, networkName)
+		if err != nil {
+			return fmt.Errorf("get network %q: %w", networkName, err)
+		}
+
+		var aliases []string
+		if nw.Name != Bridge {
+			aliases = []string{HostInternal}
+		}
+
+		dockerNw := &DockerNetwork{ID: nw.ID, Name: nw.Name}
+		if err := dockerNw.Connect(ctx, sshd, aliases...); err != nil {
+			return fmt.Errorf("connect sshd container: %w", err)
+		}
+
+		if inspect, err = sshd.Inspect(ctx); err != nil {
+			return fmt.Errorf("inspect sshd container: %w", err)
+		}
+	}
+
+	sshdNetwork := networkName
+	if sshdNetwork == "" {
+		sshdNetwork = sharedHostAccess.defaultNetwork
+	}
+
+	sshdIP := networkIP(inspect, sshdNetwork)
+	if sshdIP == "" {
+		return errors.New("sshd container IP not found")
+	}
+
+	withHostInternal(req, networkName, sshdIP)
+	sharedHostAccess.containers++
+
+	return nil
+}
@@ -23,6 +23,9 @@ type SaveImageOption func(*saveImageOptions) error
 
 type pullImageOptions struct {
 	dockerPullOpts client.ImagePullOptions
+	progress       PullProgressConsumer
+	parallelism    int
+	lock           *ImageLock
 }
 
 type PullImageOption func(*pullImageOptions) error
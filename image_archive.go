@@ -0,0 +1,316 @@
+package testcontainers
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/jsonstream"
+)
+
+// The annotations of the OCI image layouts with the name of the images.
+const (
+	ociImageNameAnnotation = "io.containerd.image.name"
+	ociRefNameAnnotation   = "org.opencontainers.image.ref.name"
+)
+
+// imageArchiveExtensions are the extensions of the image archives in an archive directory.
+var imageArchiveExtensions = []string{".tar", ".tar.gz", ".tgz"}
+
+// LoadImages loads the images of the tarball at tarPath, as written by [DockerProvider.SaveImages],
+// "docker save", or any tool exporting an OCI image layout as a tarball. Gzip compressed
+// tarballs are supported.
+func LoadImages(ctx context.Context, tarPath string) error {
+	p, err := NewDockerProvider()
+	if err != nil {
+		return fmt.Errorf("new docker provider: %w", err)
+	}
+	defer p.Close()
+
+	return p.LoadImages(ctx, tarPath)
+}
+
+// EnsureImagesFromArchive loads the image tarballs of dir, with the ".tar", ".tar.gz" or ".tgz"
+// extension, which contain images not present locally, so the containers using them start
+// without pulling them. It is meant to be called from TestMain, before running the tests,
+// in environments without access to the registries.
+func EnsureImagesFromArchive(ctx context.Context, dir string) error {
+	p, err := NewDockerProvider()
+	if err != nil {
+		return fmt.Errorf("new docker provider: %w", err)
+	}
+	defer p.Close()
+
+	return p.EnsureImagesFromArchive(ctx, dir)
+}
+
+// LoadImages loads the images of the tarball at tarPath, as the [LoadImages] function does.
+func (p *DockerProvider) LoadImages(ctx context.Context, tarPath string) error {
+	f, err := os.Open(tarPath)
+	if err != nil {
+		return fmt.Errorf("open image archive: %w", err)
+	}
+	defer f.Close()
+
+	resp, err := p.client.ImageLoad(ctx, f)
+	if err != nil {
+		return fmt.Errorf("load images from %s: %w", tarPath, err)
+	}
+	defer resp.Close()
+
+	dec := json.NewDecoder(resp)
+	for {
+		var msg jsonstream.Message
+		if err := dec.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("read load output: %w", err)
+		}
+
+		if msg.Error != nil {
+			return fmt.Errorf("load images from %s: %w", tarPath, msg.Error)
+		}
+	}
+}
+
+// EnsureImagesFromArchive loads the image tarballs of dir containing images not present
+// locally, as the [EnsureImagesFromArchive] function does.
+func (p *DockerProvider) EnsureImagesFromArchive(ctx context.Context, dir string) error {
+	archives, err := readImageArchiveDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, archive := range archives {
+		missing, err := p.missingImages(ctx, archive.images)
+		if err != nil {
+			return err
+		}
+
+		if len(missing) == 0 && len(archive.images) > 0 {
+			continue
+		}
+
+		p.Logger.Printf("📦 Loading images from %s", archive.path)
+		if err := p.LoadImages(ctx, archive.path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// missingImages returns the images not present locally.
+func (p *DockerProvider) missingImages(ctx context.Context, images []string) ([]string, error) {
+	var missing []string
+	for _, img := range images {
+		if _, err := p.client.ImageInspect(ctx, img); err != nil {
+			if !errdefs.IsNotFound(err) {
+				return nil, fmt.Errorf("inspect image: %w", err)
+			}
+			missing = append(missing, img)
+		}
+	}
+
+	return missing, nil
+}
+
+// loadImageFromArchive loads the image from the image archive directory of the configuration,
+// if any of its tarballs contains it. It returns false if the image was not loaded.
+func (p *DockerProvider) loadImageFromArchive(ctx context.Context, image string) (bool, error) {
+	if p.config.ImageArchiveDir == "" {
+		return false, nil
+	}
+
+	key, ok := taggedImageName(image)
+	if !ok {
+		return false, nil
+	}
+
+	archives, err := cachedImageArchiveDir(p.config.ImageArchiveDir)
+	if err != nil {
+		return false, fmt.Errorf("image archive from configuration: %w", err)
+	}
+
+	for _, archive := range archives {
+		if !slices.Contains(archive.images, key) {
+			continue
+		}
+
+		p.Logger.Printf("📦 Loading image %s from %s", image, archive.path)
+		if err := p.LoadImages(ctx, archive.path); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// imageArchive is an image tarball, with the names of the images it contains.
+type imageArchive struct {
+	path   string
+	images []string
+}
+
+// imageArchiveDirs are the image archives of the directories, by directory, read once
+// as the image archive directory of the configuration is shared by all the containers.
+var imageArchiveDirs sync.Map
+
+// cachedImageArchiveDir returns the image archives of the directory, which is read once it is
+// read successfully: a failed read, such as for a directory not created yet, is retried on the
+// next call.
+func cachedImageArchiveDir(dir string) ([]imageArchive, error) {
+	if v, ok := imageArchiveDirs.Load(dir); ok {
+		return v.([]imageArchive), nil
+	}
+
+	archives, err := readImageArchiveDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	v, _ := imageArchiveDirs.LoadOrStore(dir, archives)
+	return v.([]imageArchive), nil
+}
+
+// readImageArchiveDir returns the image archives of the directory, sorted by name.
+func readImageArchiveDir(dir string) ([]imageArchive, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read image archive directory: %w", err)
+	}
+
+	var archives []imageArchive
+	for _, entry := range entries {
+		if entry.IsDir() || !slices.ContainsFunc(imageArchiveExtensions, func(ext string) bool {
+			return strings.HasSuffix(entry.Name(), ext)
+		}) {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		images, err := imageArchiveImages(path)
+		if err != nil {
+			return nil, err
+		}
+
+		archives = append(archives, imageArchive{path: path, images: images})
+	}
+
+	return archives, nil
+}
+
+// dockerArchiveManifest is an entry of the manifest.json file of the tarballs written by "docker save".
+type dockerArchiveManifest struct {
+	RepoTags []string `json:"RepoTags"`
+}
+
+// ociIndex is the index.json file of an OCI image layout.
+type ociIndex struct {
+	Manifests []struct {
+		Annotations map[string]string `json:"annotations"`
+	} `json:"manifests"`
+}
+
+// imageArchiveImages returns the names of the images of the tarball, in the form of
+// [taggedImageName], reading the manifest.json file written by "docker save", or the
+// index.json file of an OCI image layout otherwise.
+func imageArchiveImages(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open image archive: %w", err)
+	}
+	defer f.Close()
+
+	// The uncompressed tarballs are read from the file, so the content of the layers is skipped
+	// with seeks instead of reads.
+	magic := make([]byte, 2)
+	_, readErr := io.ReadFull(f, magic)
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("read image archive %s: %w", path, err)
+	}
+
+	var r io.Reader = f
+	if readErr == nil && bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("decompress image archive %s: %w", path, err)
+		}
+		defer gr.Close()
+		r = gr
+	}
+
+	var manifest, index []byte
+	tr := tar.NewReader(r)
+	for manifest == nil {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("read image archive %s: %w", path, err)
+		}
+
+		switch filepath.Clean(hdr.Name) {
+		case "manifest.json":
+			if manifest, err = io.ReadAll(tr); err != nil {
+				return nil, fmt.Errorf("read manifest of image archive %s: %w", path, err)
+			}
+		case "index.json":
+			if index, err = io.ReadAll(tr); err != nil {
+				return nil, fmt.Errorf("read index of image archive %s: %w", path, err)
+			}
+		}
+	}
+
+	var names []string
+	switch {
+	case manifest != nil:
+		var entries []dockerArchiveManifest
+		if err := json.Unmarshal(manifest, &entries); err != nil {
+			return nil, fmt.Errorf("decode manifest of image archive %s: %w", path, err)
+		}
+
+		for _, entry := range entries {
+			names = append(names, entry.RepoTags...)
+		}
+	case index != nil:
+		var idx ociIndex
+		if err := json.Unmarshal(index, &idx); err != nil {
+			return nil, fmt.Errorf("decode index of image archive %s: %w", path, err)
+		}
+
+		for _, m := range idx.Manifests {
+			name := m.Annotations[ociImageNameAnnotation]
+			if name == "" {
+				name = m.Annotations[ociRefNameAnnotation]
+			}
+			names = append(names, name)
+		}
+	default:
+		return nil, fmt.Errorf("image archive %s has neither a manifest.json nor an index.json file", path)
+	}
+
+	var images []string
+	for _, name := range names {
+		if key, ok := taggedImageName(name); ok && !slices.Contains(images, key) {
+			images = append(images, key)
+		}
+	}
+
+	return images, nil
+}
@@ -0,0 +1,239 @@
+package testcontainers
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/internal/config"
+	"github.com/testcontainers/testcontainers-go/log"
+)
+
+// archiveMockCli is a mock implementation of client.APIClient, adding the images of the
+// loaded archives to the local ones.
+type archiveMockCli struct {
+	client.APIClient
+
+	local  map[string]bool
+	loads  []string
+	output string
+}
+
+func (m *archiveMockCli) ImageInspect(_ context.Context, ref string, _ ...client.ImageInspectOption) (client.ImageInspectResult, error) {
+	if m.local[ref] {
+		return client.ImageInspectResult{}, nil
+	}
+
+	return client.ImageInspectResult{}, errdefs.ErrNotFound.WithMessage("no such image: " + ref)
+}
+
+func (m *archiveMockCli) ImageLoad(_ context.Context, input io.Reader, _ ...client.ImageLoadOption) (client.ImageLoadResult, error) {
+	f := input.(*os.File)
+	m.loads = append(m.loads, filepath.Base(f.Name()))
+
+	images, err := imageArchiveImages(f.Name())
+	if err != nil {
+		return nil, err
+	}
+	for _, img := range images {
+		m.local[img] = true
+	}
+
+	output := m.output
+	if output == "" {
+		output = `{"stream":"Loaded image: ` + strings.Join(images, ", ") + `\n"}`
+	}
+
+	return io.NopCloser(strings.NewReader(output)), nil
+}
+
+// writeImageArchive writes a tarball with the given files, compressed with gzip if the
+// path ends with ".gz".
+func writeImageArchive(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var w io.Writer = f
+	if strings.HasSuffix(path, ".gz") {
+		gw := gzip.NewWriter(f)
+		defer gw.Close()
+		w = gw
+	}
+
+	tw := tar.NewWriter(w)
+	defer tw.Close()
+
+	// a layer before the manifest, as written by "docker save".
+	files["blobs/sha256/"+strings.Repeat("a", 64)] = strings.Repeat("layer", 1024)
+	for _, name := range []string{"blobs/sha256/" + strings.Repeat("a", 64), "index.json", "manifest.json"} {
+		content, ok := files[name]
+		if !ok {
+			continue
+		}
+
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+}
+
+func TestImageArchiveImages(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("docker-save", func(t *testing.T) {
+		path := filepath.Join(dir, "redis.tar")
+		writeImageArchive(t, path, map[string]string{
+			"manifest.json": `[{"RepoTags":["redis:7","docker.io/library/redis:latest"]},{"RepoTags":["ghcr.io/org/app:1.0"]}]`,
+			"index.json":    `{"manifests":[{"annotations":{"io.containerd.image.name":"docker.io/library/ignored:1"}}]}`,
+		})
+
+		images, err := imageArchiveImages(path)
+		require.NoError(t, err)
+		require.Equal(t, []string{"redis:7", "redis:latest", "ghcr.io/org/app:1.0"}, images)
+	})
+
+	t.Run("oci-layout/gzip", func(t *testing.T) {
+		path := filepath.Join(dir, "nginx.tar.gz")
+		writeImageArchive(t, path, map[string]string{
+			"index.json": `{"manifests":[
+				{"annotations":{"io.containerd.image.name":"docker.io/library/nginx:1.27","org.opencontainers.image.ref.name":"1.27"}},
+				{"annotations":{"org.opencontainers.image.ref.name":"quay.io/org/app:2"}},
+				{"annotations":{}}
+			]}`,
+		})
+
+		images, err := imageArchiveImages(path)
+		require.NoError(t, err)
+		require.Equal(t, []string{"nginx:1.27", "quay.io/org/app:2"}, images)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		path := filepath.Join(dir, "empty.tar")
+		writeImageArchive(t, path, map[string]string{})
+
+		_, err := imageArchiveImages(path)
+		require.ErrorContains(t, err, "has neither a manifest.json nor an index.json file")
+	})
+}
+
+func newArchiveMockProvider(t *testing.T, cfg config.Config, local ...string) (*DockerProvider, *archiveMockCli) {
+	t.Helper()
+
+	m := &archiveMockCli{local: map[string]bool{}}
+	for _, img := range local {
+		m.local[img] = true
+	}
+
+	p := &DockerProvider{
+		DockerProviderOptions: &DockerProviderOptions{
+			GenericProviderOptions: &GenericProviderOptions{Logger: log.TestLogger(t)},
+		},
+		client: m,
+		config: cfg,
+	}
+
+	return p, m
+}
+
+func TestEnsureImagesFromArchive(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	writeImageArchive(t, filepath.Join(dir, "nginx.tgz"), map[string]string{
+		"manifest.json": `[{"RepoTags":["nginx:1.27"]}]`,
+	})
+	writeImageArchive(t, filepath.Join(dir, "redis.tar"), map[string]string{
+		"manifest.json": `[{"RepoTags":["redis:7"]}]`,
+	})
+	// not an image archive.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("images"), 0o644))
+
+	p, m := newArchiveMockProvider(t, config.Config{}, "redis:7")
+
+	require.NoError(t, p.EnsureImagesFromArchive(ctx, dir))
+	require.Equal(t, []string{"nginx.tgz"}, m.loads)
+
+	// the images are present now.
+	require.NoError(t, p.EnsureImagesFromArchive(ctx, dir))
+	require.Equal(t, []string{"nginx.tgz"}, m.loads)
+
+	t.Run("load-error", func(t *testing.T) {
+		p, m := newArchiveMockProvider(t, config.Config{})
+		m.output = `{"errorDetail":{"message":"invalid tar header"},"error":"invalid tar header"}`
+
+		err := p.EnsureImagesFromArchive(ctx, dir)
+		require.ErrorContains(t, err, "load images from "+filepath.Join(dir, "nginx.tgz")+": invalid tar header")
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		p, _ := newArchiveMockProvider(t, config.Config{})
+
+		err := p.EnsureImagesFromArchive(ctx, filepath.Join(dir, "not-found"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestDockerProvider_loadImageFromArchive(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	writeImageArchive(t, filepath.Join(dir, "redis.tar"), map[string]string{
+		"manifest.json": `[{"RepoTags":["redis:7"]}]`,
+	})
+
+	p, m := newArchiveMockProvider(t, config.Config{ImageArchiveDir: dir})
+
+	loaded, err := p.loadImageFromArchive(ctx, "docker.io/library/redis:7")
+	require.NoError(t, err)
+	require.True(t, loaded)
+	require.Equal(t, []string{"redis.tar"}, m.loads)
+
+	loaded, err = p.loadImageFromArchive(ctx, "redis:8")
+	require.NoError(t, err)
+	require.False(t, loaded)
+
+	p, m = newArchiveMockProvider(t, config.Config{})
+	loaded, err = p.loadImageFromArchive(ctx, "redis:7")
+	require.NoError(t, err)
+	require.False(t, loaded)
+	require.Empty(t, m.loads)
+}
+
+func TestCachedImageArchiveDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "images")
+	t.Cleanup(func() { imageArchiveDirs.Delete(dir) })
+
+	// the directory does not exist yet, the failed read is not cached.
+	_, err := cachedImageArchiveDir(dir)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, os.Mkdir(dir, 0o755))
+	writeImageArchive(t, filepath.Join(dir, "redis.tar"), map[string]string{
+		"manifest.json": `[{"RepoTags":["redis:7"]}]`,
+	})
+
+	archives, err := cachedImageArchiveDir(dir)
+	require.NoError(t, err)
+	require.Len(t, archives, 1)
+
+	// the successful read is cached.
+	writeImageArchive(t, filepath.Join(dir, "nginx.tar"), map[string]string{
+		"manifest.json": `[{"RepoTags":["nginx:1"]}]`,
+	})
+
+	archives, err = cachedImageArchiveDir(dir)
+	require.NoError(t, err)
+	require.Len(t, archives, 1)
+}
@@ -0,0 +1,309 @@
+package testcontainers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/distribution/reference"
+	"github.com/moby/moby/client"
+	"github.com/opencontainers/go-digest"
+)
+
+// ImageLockMode defines how an [ImageLock] uses the digests of its lockfile.
+type ImageLockMode string
+
+const (
+	// ImageLockRewrite rewrites the references of the locked images to their locked digest,
+	// so the same image is used on every run. This is the default mode.
+	ImageLockRewrite ImageLockMode = "rewrite"
+
+	// ImageLockVerify keeps the references of the images, failing with [ErrImageLockMismatch]
+	// when an image resolves to a digest other than the locked one.
+	ImageLockVerify ImageLockMode = "verify"
+
+	// ImageLockUpdate keeps the references of the images, updating the lockfile with the
+	// digests they resolve to.
+	ImageLockUpdate ImageLockMode = "update"
+)
+
+// ErrImageLockMismatch is returned when an image resolves to a digest other than the one locked.
+var ErrImageLockMismatch = errors.New("image does not match the lock")
+
+// imageLockFile is the content of a lockfile.
+type imageLockFile struct {
+	Images map[string]string `json:"images"`
+}
+
+// ImageLock records the digests of the images used by the tests into a lockfile, so later runs
+// use the same images, or fail when a tag has drifted, depending on its [ImageLockMode].
+// New images are recorded in every mode, and the lockfile is saved as soon as an image is
+// recorded. It's safe for concurrent use.
+//
+// ImageLock implements [ImageSubstitutor], to rewrite the references to their locked digest,
+// and is applied to the containers with [WithImageLock], to the pulls with [PullDockerImageWithLock],
+// or to all the containers with the image.lock.file property.
+type ImageLock struct {
+	path string
+	mode ImageLockMode
+
+	mtx    sync.Mutex
+	images map[string]string
+}
+
+// NewImageLock returns the image lock of the lockfile at path, which is read if it exists.
+func NewImageLock(path string, mode ImageLockMode) (*ImageLock, error) {
+	switch mode {
+	case "":
+		mode = ImageLockRewrite
+	case ImageLockRewrite, ImageLockVerify, ImageLockUpdate:
+	default:
+		return nil, fmt.Errorf("unknown image lock mode %q", mode)
+	}
+
+	images, err := readImageLockFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ImageLock{path: path, mode: mode, images: images}, nil
+}
+
+// readImageLockFile returns the locked images of the lockfile, which is empty if it does not exist.
+func readImageLockFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("read image lock: %w", err)
+	}
+
+	var lock imageLockFile
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("decode image lock %s: %w", path, err)
+	}
+
+	if lock.Images == nil {
+		lock.Images = map[string]string{}
+	}
+
+	for image, dgst := range lock.Images {
+		if err := digest.Digest(dgst).Validate(); err != nil {
+			return nil, fmt.Errorf("invalid digest of image %s in %s: %w", image, path, err)
+		}
+	}
+
+	return lock.Images, nil
+}
+
+// taggedImageName returns the familiar name of the image with its tag, "latest" by default,
+// which is the key of the image in the lockfile and in the image archives. It returns false
+// if the image is not a valid reference, or if it is already pinned to a digest.
+func taggedImageName(image string) (string, bool) {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", false
+	}
+
+	if _, ok := ref.(reference.Digested); ok {
+		return "", false
+	}
+
+	return reference.FamiliarString(reference.TagNameOnly(ref)), true
+}
+
+// Digest returns the locked digest of the image.
+func (l *ImageLock) Digest(image string) (string, bool) {
+	key, ok := taggedImageName(image)
+	if !ok {
+		return "", false
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	dgst, ok := l.images[key]
+	return dgst, ok
+}
+
+// Description returns the name of the type and the lockfile it uses.
+func (l *ImageLock) Description() string {
+	return fmt.Sprintf("ImageLock (%s, %s)", l.mode, l.path)
+}
+
+// Substitute pins the image to its locked digest in the [ImageLockRewrite] mode, returning
+// the image as is otherwise, or if the image is not locked.
+func (l *ImageLock) Substitute(image string) (string, error) {
+	if l.mode != ImageLockRewrite {
+		return image, nil
+	}
+
+	dgst, ok := l.Digest(image)
+	if !ok {
+		return image, nil
+	}
+
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", fmt.Errorf("parse image: %w", err)
+	}
+
+	pinned, err := reference.WithDigest(reference.TagNameOnly(ref), digest.Digest(dgst))
+	if err != nil {
+		return "", fmt.Errorf("pin image to %s: %w", dgst, err)
+	}
+
+	return reference.FamiliarString(pinned), nil
+}
+
+// check verifies the digest of resolved, the local image the image resolved to, against the
+// digest locked for the image, recording it if the image is not locked yet.
+func (l *ImageLock) check(ctx context.Context, cli client.APIClient, image, resolved string) error {
+	key, ok := taggedImageName(image)
+	if !ok {
+		return nil
+	}
+
+	dgst, err := resolvedDigest(ctx, cli, resolved)
+	if err != nil {
+		return err
+	}
+	if dgst == "" {
+		// The image was not pulled from a registry, there is nothing to lock.
+		return nil
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	locked, ok := l.images[key]
+	switch {
+	case ok && locked == dgst:
+		return nil
+	case ok && l.mode != ImageLockUpdate:
+		return fmt.Errorf("%w: %s resolves to %s, locked to %s in %s", ErrImageLockMismatch, image, dgst, locked, l.path)
+	}
+
+	l.images[key] = dgst
+
+	return l.save()
+}
+
+// resolvedDigest returns the digest of the local image in the registry it was pulled from,
+// or an empty string if the image has no registry digest, as it happens with the built images.
+func resolvedDigest(ctx context.Context, cli client.APIClient, image string) (string, error) {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", fmt.Errorf("parse image: %w", err)
+	}
+
+	if digested, ok := ref.(reference.Digested); ok {
+		return digested.Digest().String(), nil
+	}
+
+	img, err := cli.ImageInspect(ctx, image)
+	if err != nil {
+		return "", fmt.Errorf("inspect image: %w", err)
+	}
+
+	for _, repoDigest := range img.RepoDigests {
+		r, err := reference.ParseNormalizedNamed(repoDigest)
+		if err != nil {
+			continue
+		}
+
+		if digested, ok := r.(reference.Digested); ok && r.Name() == ref.Name() {
+			return digested.Digest().String(), nil
+		}
+	}
+
+	return "", nil
+}
+
+// Save writes the lockfile, merging the images recorded by other processes since it was read.
+func (l *ImageLock) Save() error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	return l.save()
+}
+
+// save writes the lockfile, which is replaced atomically. The caller must hold the lock.
+func (l *ImageLock) save() error {
+	images, err := readImageLockFile(l.path)
+	if err != nil {
+		return err
+	}
+
+	maps.Copy(images, l.images)
+	l.images = images
+
+	data, err := json.MarshalIndent(imageLockFile{Images: images}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode image lock: %w", err)
+	}
+
+	dir := filepath.Dir(l.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create image lock directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(l.path)+".*")
+	if err != nil {
+		return fmt.Errorf("create image lock: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		return errors.Join(fmt.Errorf("write image lock: %w", err), tmp.Close())
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close image lock: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), l.path); err != nil {
+		return fmt.Errorf("rename image lock: %w", err)
+	}
+
+	return nil
+}
+
+// configImageLocks are the image locks defined by the configuration, by path.
+var configImageLocks sync.Map
+
+// imageLock returns the image lock of the request, or the one defined in the configuration,
+// which is nil if there is none.
+func (p *DockerProvider) imageLock(req *ContainerRequest) (*ImageLock, error) {
+	if req.ImageLock != nil {
+		return req.ImageLock, nil
+	}
+
+	return p.configImageLock()
+}
+
+// configImageLock returns the image lock defined in the configuration, which is shared by all
+// the containers. It returns nil if there is none.
+func (p *DockerProvider) configImageLock() (*ImageLock, error) {
+	if p.config.ImageLockFile == "" {
+		return nil, nil
+	}
+
+	if lock, ok := configImageLocks.Load(p.config.ImageLockFile); ok {
+		return lock.(*ImageLock), nil
+	}
+
+	lock, err := NewImageLock(p.config.ImageLockFile, ImageLockMode(p.config.ImageLockMode))
+	if err != nil {
+		return nil, fmt.Errorf("image lock from configuration: %w", err)
+	}
+
+	actual, _ := configImageLocks.LoadOrStore(p.config.ImageLockFile, lock)
+	return actual.(*ImageLock), nil
+}
@@ -0,0 +1,133 @@
+package testcontainers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	lockedDigest  = "sha256:1e42bbe2508154c9126d48c2b8a75420c3544343bf86fd041fb7527e017a4b4a"
+	driftedDigest = "sha256:2f42bbe2508154c9126d48c2b8a75420c3544343bf86fd041fb7527e017a4b4b"
+)
+
+// lockMockCli is a mock implementation of client.APIClient, returning the repo digests of the images.
+type lockMockCli struct {
+	client.APIClient
+
+	repoDigests map[string][]string
+}
+
+func (m *lockMockCli) ImageInspect(_ context.Context, ref string, _ ...client.ImageInspectOption) (client.ImageInspectResult, error) {
+	return client.ImageInspectResult{InspectResponse: image.InspectResponse{RepoDigests: m.repoDigests[ref]}}, nil
+}
+
+func TestImageLock(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "testcontainers.lock")
+
+	cli := &lockMockCli{repoDigests: map[string][]string{
+		"redis:7":             {"redis@" + lockedDigest},
+		"mirror.corp/redis:7": {"mirror.corp/redis@" + lockedDigest},
+		"built:latest":        nil,
+	}}
+
+	lock, err := NewImageLock(path, "")
+	require.NoError(t, err)
+
+	// an unknown image is not rewritten, and it's recorded.
+	name, err := lock.Substitute("redis:7")
+	require.NoError(t, err)
+	require.Equal(t, "redis:7", name)
+	require.NoError(t, lock.check(ctx, cli, "redis:7", "redis:7"))
+
+	// the images without a registry digest are not recorded.
+	require.NoError(t, lock.check(ctx, cli, "built", "built:latest"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"images": {"redis:7": "`+lockedDigest+`"}}`, string(data))
+
+	t.Run("rewrite", func(t *testing.T) {
+		lock, err := NewImageLock(path, ImageLockRewrite)
+		require.NoError(t, err)
+
+		name, err := lock.Substitute("docker.io/library/redis:7")
+		require.NoError(t, err)
+		require.Equal(t, "redis:7@"+lockedDigest, name)
+
+		name, err = lock.Substitute("redis:8")
+		require.NoError(t, err)
+		require.Equal(t, "redis:8", name)
+
+		require.NoError(t, lock.check(ctx, cli, "redis:7", name))
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		lock, err := NewImageLock(path, ImageLockVerify)
+		require.NoError(t, err)
+
+		name, err := lock.Substitute("redis:7")
+		require.NoError(t, err)
+		require.Equal(t, "redis:7", name)
+
+		// the mirror of the image resolves to the same digest.
+		require.NoError(t, lock.check(ctx, cli, "redis:7", "mirror.corp/redis:7"))
+
+		drifted := &lockMockCli{repoDigests: map[string][]string{"redis:7": {"redis@" + driftedDigest}}}
+		err = lock.check(ctx, drifted, "redis:7", "redis:7")
+		require.ErrorIs(t, err, ErrImageLockMismatch)
+		require.ErrorContains(t, err, "redis:7 resolves to "+driftedDigest+", locked to "+lockedDigest)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		lock, err := NewImageLock(path, ImageLockUpdate)
+		require.NoError(t, err)
+
+		drifted := &lockMockCli{repoDigests: map[string][]string{"redis:7": {"redis@" + driftedDigest}}}
+		require.NoError(t, lock.check(ctx, drifted, "redis:7", "redis:7"))
+
+		dgst, ok := lock.Digest("redis:7")
+		require.True(t, ok)
+		require.Equal(t, driftedDigest, dgst)
+	})
+}
+
+func TestImageLock_save(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "locks", "testcontainers.lock")
+
+	first, err := NewImageLock(path, ImageLockRewrite)
+	require.NoError(t, err)
+
+	second, err := NewImageLock(path, ImageLockRewrite)
+	require.NoError(t, err)
+
+	first.images["redis:7"] = lockedDigest
+	require.NoError(t, first.Save())
+
+	// the images recorded by other locks of the same file are kept.
+	second.images["postgres:16"] = driftedDigest
+	require.NoError(t, second.Save())
+
+	lock, err := NewImageLock(path, ImageLockVerify)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"redis:7": lockedDigest, "postgres:16": driftedDigest}, lock.images)
+}
+
+func TestNewImageLock_invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := NewImageLock(filepath.Join(dir, "testcontainers.lock"), "sometimes")
+	require.EqualError(t, err, `unknown image lock mode "sometimes"`)
+
+	path := filepath.Join(dir, "invalid.lock")
+	require.NoError(t, os.WriteFile(path, []byte(`{"images": {"redis:7": "latest"}}`), 0o644))
+
+	_, err = NewImageLock(path, ImageLockRewrite)
+	require.ErrorContains(t, err, "invalid digest of image redis:7")
+}
@@ -0,0 +1,303 @@
+package testcontainers
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+)
+
+// ErrImagePolicyViolation is returned when the image of a container does not satisfy its [ImagePolicy].
+var ErrImagePolicyViolation = errors.New("image policy violation")
+
+// ImagePolicy defines the images the containers are allowed to run, which is checked right
+// before creating the containers, without any network call. The images built from a
+// Dockerfile are checked through the base images of the Dockerfile.
+type ImagePolicy struct {
+	// AllowedRegistries are the registries the images can come from, all of them if empty.
+	// Docker Hub is "docker.io", and "*.corp.io" matches all the subdomains of corp.io.
+	AllowedRegistries []string
+
+	// DeniedRegistries are the registries the images cannot come from, with the same
+	// format as AllowedRegistries.
+	DeniedRegistries []string
+
+	// RequireDigest requires the images to be pinned to a digest, e.g. "redis:7@sha256:...".
+	RequireDigest bool
+
+	// Cosign, if set, verifies the cosign signatures of the images.
+	Cosign *CosignVerifier
+}
+
+// Check verifies the image against the policy, where dgst is the digest the image resolved
+// to, if known. The digest of the image reference takes precedence over dgst.
+func (ip *ImagePolicy) Check(image, dgst string) error {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return fmt.Errorf("%w: invalid image %s: %w", ErrImagePolicyViolation, image, err)
+	}
+
+	reg := strings.ToLower(reference.Domain(ref))
+	for _, denied := range ip.DeniedRegistries {
+		if matchRegistry(denied, reg) {
+			return fmt.Errorf("%w: image %s: registry %s is denied", ErrImagePolicyViolation, image, reg)
+		}
+	}
+
+	if len(ip.AllowedRegistries) > 0 && !matchRegistries(ip.AllowedRegistries, reg) {
+		return fmt.Errorf("%w: image %s: registry %s is not allowed", ErrImagePolicyViolation, image, reg)
+	}
+
+	digested, pinned := ref.(reference.Digested)
+	if ip.RequireDigest && !pinned {
+		return fmt.Errorf("%w: image %s is not pinned to a digest", ErrImagePolicyViolation, image)
+	}
+
+	if ip.Cosign == nil {
+		return nil
+	}
+
+	if pinned {
+		dgst = digested.Digest().String()
+	}
+
+	if dgst == "" {
+		return fmt.Errorf("%w: image %s has no digest to verify its signature", ErrImagePolicyViolation, image)
+	}
+
+	return ip.Cosign.Verify(image, dgst)
+}
+
+// matchRegistries returns true if the registry matches any of the patterns.
+func matchRegistries(patterns []string, reg string) bool {
+	for _, pattern := range patterns {
+		if matchRegistry(pattern, reg) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchRegistry returns true if the registry matches the pattern, which is a registry,
+// or a "*." prefixed domain matching all its subdomains.
+func matchRegistry(pattern, reg string) bool {
+	pattern = strings.TrimSpace(pattern)
+	if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+		return strings.HasSuffix(reg, "."+strings.ToLower(suffix))
+	}
+
+	return normalizeRegistry(pattern) == reg
+}
+
+// cosignSignatureType is the type of the payloads signed by cosign.
+const cosignSignatureType = "cosign container image signature"
+
+// cosignPayload is the simple signing payload signed by cosign.
+type cosignPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// CosignVerifier verifies the cosign signatures of the images offline, with a public key
+// and the signatures stored in a local directory.
+type CosignVerifier struct {
+	publicKey     crypto.PublicKey
+	signaturesDir string
+}
+
+// NewCosignVerifier returns a verifier of the cosign signatures with the PEM encoded public key
+// at publicKeyPath, which is an ECDSA, RSA or Ed25519 key, e.g. the one created by
+// "cosign generate-key-pair". The signatures are read from signaturesDir, where the signature
+// of the image with the digest "sha256:<hex>" is the "sha256-<hex>.sig" file, with the base64
+// encoded signature, and the "sha256-<hex>.payload" file, with the signed payload, as written by
+// "cosign sign --output-signature <file> --output-payload <file>".
+func NewCosignVerifier(publicKeyPath, signaturesDir string) (*CosignVerifier, error) {
+	data, err := os.ReadFile(publicKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("read cosign public key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("decode cosign public key %s: no PEM encoded public key", publicKeyPath)
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse cosign public key %s: %w", publicKeyPath, err)
+	}
+
+	switch publicKey.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+	default:
+		return nil, fmt.Errorf("unsupported cosign public key type %T", publicKey)
+	}
+
+	return &CosignVerifier{publicKey: publicKey, signaturesDir: signaturesDir}, nil
+}
+
+// Verify verifies the cosign signature of the image with the given digest.
+func (v *CosignVerifier) Verify(image, dgst string) error {
+	d, err := digest.Parse(dgst)
+	if err != nil {
+		return fmt.Errorf("%w: image %s: invalid digest: %w", ErrImagePolicyViolation, image, err)
+	}
+
+	base := filepath.Join(v.signaturesDir, d.Algorithm().String()+"-"+d.Encoded())
+
+	encoded, err := os.ReadFile(base + ".sig")
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w: image %s: no cosign signature for %s", ErrImagePolicyViolation, image, dgst)
+		}
+		return fmt.Errorf("read cosign signature: %w", err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return fmt.Errorf("%w: image %s: decode cosign signature: %w", ErrImagePolicyViolation, image, err)
+	}
+
+	payload, err := os.ReadFile(base + ".payload")
+	if err != nil {
+		return fmt.Errorf("read cosign payload: %w", err)
+	}
+
+	if !v.verifySignature(payload, signature) {
+		return fmt.Errorf("%w: image %s: invalid cosign signature", ErrImagePolicyViolation, image)
+	}
+
+	var p cosignPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("%w: image %s: decode cosign payload: %w", ErrImagePolicyViolation, image, err)
+	}
+
+	if p.Critical.Type != cosignSignatureType {
+		return fmt.Errorf("%w: image %s: unexpected cosign payload type %q", ErrImagePolicyViolation, image, p.Critical.Type)
+	}
+
+	// The digest is what binds the signature to the image. The reference is not checked, so
+	// the images pulled from mirrors are verified with the signatures of the original images.
+	if p.Critical.Image.DockerManifestDigest != dgst {
+		return fmt.Errorf("%w: image %s: cosign signature is for digest %s, not %s", ErrImagePolicyViolation, image, p.Critical.Image.DockerManifestDigest, dgst)
+	}
+
+	return nil
+}
+
+// verifySignature returns true if the signature of the payload is valid for the public key.
+func (v *CosignVerifier) verifySignature(payload, signature []byte) bool {
+	hash := sha256.Sum256(payload)
+
+	switch key := v.publicKey.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(key, hash[:], signature)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, payload, signature)
+	default:
+		return false
+	}
+}
+
+// imagePolicy returns the image policy of the request, or the one defined in the configuration,
+// which is nil if there is none.
+func (p *DockerProvider) imagePolicy(req *ContainerRequest) (*ImagePolicy, error) {
+	if req.ImagePolicy != nil {
+		return req.ImagePolicy, nil
+	}
+
+	cfg := p.config
+	if cfg.ImagePolicyAllowedRegistries == "" && cfg.ImagePolicyDeniedRegistries == "" &&
+		!cfg.ImagePolicyRequireDigest && cfg.ImagePolicyCosignKey == "" {
+		return nil, nil
+	}
+
+	policy := &ImagePolicy{
+		AllowedRegistries: splitList(cfg.ImagePolicyAllowedRegistries),
+		DeniedRegistries:  splitList(cfg.ImagePolicyDeniedRegistries),
+		RequireDigest:     cfg.ImagePolicyRequireDigest,
+	}
+
+	if cfg.ImagePolicyCosignKey != "" {
+		if cfg.ImagePolicyCosignSignatures == "" {
+			return nil, errors.New("image policy from configuration: the cosign key requires the cosign signatures directory")
+		}
+
+		verifier, err := NewCosignVerifier(cfg.ImagePolicyCosignKey, cfg.ImagePolicyCosignSignatures)
+		if err != nil {
+			return nil, fmt.Errorf("image policy from configuration: %w", err)
+		}
+
+		policy.Cosign = verifier
+	}
+
+	return policy, nil
+}
+
+// splitList returns the non-empty values of the comma separated list.
+func splitList(list string) []string {
+	var values []string
+	for _, v := range strings.Split(list, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+// checkImagePolicy verifies the image of the container against the image policy, if any.
+// The images built from a Dockerfile are verified through the base images of the Dockerfile.
+func (p *DockerProvider) checkImagePolicy(ctx context.Context, req *ContainerRequest, image string) error {
+	policy, err := p.imagePolicy(req)
+	if err != nil || policy == nil {
+		return err
+	}
+
+	if req.ShouldBuildImage() {
+		images, err := req.dockerFileImages()
+		if err != nil {
+			return fmt.Errorf("docker file images: %w", err)
+		}
+
+		var errs []error
+		for _, img := range images {
+			if strings.EqualFold(img, "scratch") {
+				continue
+			}
+			errs = append(errs, policy.Check(img, ""))
+		}
+
+		return errors.Join(errs...)
+	}
+
+	var dgst string
+	if policy.Cosign != nil {
+		if dgst, err = resolvedDigest(ctx, p.client, image); err != nil {
+			return err
+		}
+	}
+
+	return policy.Check(image, dgst)
+}
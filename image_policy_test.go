@@ -0,0 +1,233 @@
+package testcontainers
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/internal/config"
+)
+
+// writeCosignKey writes the PEM encoded public key of the signer into the directory,
+// returning its path.
+func writeCosignKey(t *testing.T, dir string, signer crypto.Signer) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(signer.Public())
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "cosign.pub")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o644))
+
+	return path
+}
+
+// writeCosignSignature signs the cosign payload of the digest, writing the signature and
+// the payload into the directory, as cosign does.
+func writeCosignSignature(t *testing.T, dir string, signer crypto.Signer, image, dgst string) {
+	t.Helper()
+
+	payload := fmt.Sprintf(`{"critical":{"identity":{"docker-reference":%q},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, image, dgst)
+
+	var signature []byte
+	var err error
+	if _, ok := signer.(ed25519.PrivateKey); ok {
+		signature, err = signer.Sign(rand.Reader, []byte(payload), crypto.Hash(0))
+	} else {
+		hash := sha256.Sum256([]byte(payload))
+		signature, err = signer.Sign(rand.Reader, hash[:], crypto.SHA256)
+	}
+	require.NoError(t, err)
+
+	base := filepath.Join(dir, strings.Replace(dgst, ":", "-", 1))
+	require.NoError(t, os.WriteFile(base+".sig", []byte(base64.StdEncoding.EncodeToString(signature)), 0o644))
+	require.NoError(t, os.WriteFile(base+".payload", []byte(payload), 0o644))
+}
+
+func TestImagePolicy_registries(t *testing.T) {
+	policy := &ImagePolicy{
+		AllowedRegistries: []string{"docker.io", "*.corp.io", "localhost:5000"},
+		DeniedRegistries:  []string{"untrusted.corp.io"},
+	}
+
+	for _, image := range []string{"redis:7", "docker.io/library/redis:7", "eu.corp.io/app:1", "localhost:5000/app"} {
+		require.NoError(t, policy.Check(image, ""), image)
+	}
+
+	err := policy.Check("ghcr.io/org/app:1.0", "")
+	require.ErrorIs(t, err, ErrImagePolicyViolation)
+	require.EqualError(t, err, "image policy violation: image ghcr.io/org/app:1.0: registry ghcr.io is not allowed")
+
+	err = policy.Check("untrusted.corp.io/app:1", "")
+	require.ErrorIs(t, err, ErrImagePolicyViolation)
+	require.EqualError(t, err, "image policy violation: image untrusted.corp.io/app:1: registry untrusted.corp.io is denied")
+
+	// the subdomain wildcard does not match the domain itself.
+	require.ErrorIs(t, policy.Check("corp.io/app:1", ""), ErrImagePolicyViolation)
+
+	denyHub := &ImagePolicy{DeniedRegistries: []string{"https://index.docker.io/v1/"}}
+	require.ErrorIs(t, denyHub.Check("redis:7", ""), ErrImagePolicyViolation)
+	require.NoError(t, denyHub.Check("ghcr.io/org/app:1.0", ""))
+}
+
+func TestImagePolicy_requireDigest(t *testing.T) {
+	policy := &ImagePolicy{RequireDigest: true}
+
+	require.NoError(t, policy.Check("redis:7@"+lockedDigest, ""))
+
+	// the resolved digest does not pin the image.
+	err := policy.Check("redis:7", lockedDigest)
+	require.ErrorIs(t, err, ErrImagePolicyViolation)
+	require.EqualError(t, err, "image policy violation: image redis:7 is not pinned to a digest")
+}
+
+func TestImagePolicy_cosign(t *testing.T) {
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	for name, signer := range map[string]crypto.Signer{"ecdsa": ecdsaKey, "ed25519": ed25519Key} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			signatures := filepath.Join(dir, "signatures")
+			require.NoError(t, os.Mkdir(signatures, 0o755))
+
+			verifier, err := NewCosignVerifier(writeCosignKey(t, dir, signer), signatures)
+			require.NoError(t, err)
+
+			policy := &ImagePolicy{Cosign: verifier}
+			writeCosignSignature(t, signatures, signer, "index.docker.io/library/redis", lockedDigest)
+
+			require.NoError(t, policy.Check("redis:7@"+lockedDigest, ""))
+			require.NoError(t, policy.Check("mirror.corp/redis:7", lockedDigest))
+
+			err = policy.Check("redis:7", "")
+			require.EqualError(t, err, "image policy violation: image redis:7 has no digest to verify its signature")
+
+			err = policy.Check("redis:7", driftedDigest)
+			require.ErrorIs(t, err, ErrImagePolicyViolation)
+			require.ErrorContains(t, err, "no cosign signature for "+driftedDigest)
+
+			// a signature of other digest, renamed to the verified one.
+			writeCosignSignature(t, signatures, signer, "index.docker.io/library/redis", driftedDigest)
+			driftedBase := filepath.Join(signatures, strings.Replace(driftedDigest, ":", "-", 1))
+			lockedBase := filepath.Join(signatures, strings.Replace(lockedDigest, ":", "-", 1))
+			require.NoError(t, os.Rename(driftedBase+".sig", lockedBase+".sig"))
+			require.NoError(t, os.Rename(driftedBase+".payload", lockedBase+".payload"))
+
+			err = policy.Check("redis:7", lockedDigest)
+			require.ErrorIs(t, err, ErrImagePolicyViolation)
+			require.ErrorContains(t, err, "cosign signature is for digest "+driftedDigest)
+
+			// a tampered payload.
+			require.NoError(t, os.WriteFile(lockedBase+".payload", []byte(`{}`), 0o644))
+			err = policy.Check("redis:7", lockedDigest)
+			require.ErrorIs(t, err, ErrImagePolicyViolation)
+			require.ErrorContains(t, err, "invalid cosign signature")
+		})
+	}
+}
+
+func TestNewCosignVerifier_invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := NewCosignVerifier(filepath.Join(dir, "not-found.pub"), dir)
+	require.ErrorContains(t, err, "read cosign public key")
+
+	path := filepath.Join(dir, "invalid.pub")
+	require.NoError(t, os.WriteFile(path, []byte("not a key"), 0o644))
+
+	_, err = NewCosignVerifier(path, dir)
+	require.ErrorContains(t, err, "no PEM encoded public key")
+}
+
+func TestDockerProvider_checkImagePolicy(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no-policy", func(t *testing.T) {
+		p := &DockerProvider{}
+		require.NoError(t, p.checkImagePolicy(ctx, &ContainerRequest{}, "ghcr.io/org/app:1.0"))
+	})
+
+	t.Run("config", func(t *testing.T) {
+		p := &DockerProvider{config: config.Config{
+			ImagePolicyAllowedRegistries: " mirror.corp, ,*.corp.io",
+			ImagePolicyRequireDigest:     true,
+		}}
+
+		policy, err := p.imagePolicy(&ContainerRequest{})
+		require.NoError(t, err)
+		require.Equal(t, &ImagePolicy{AllowedRegistries: []string{"mirror.corp", "*.corp.io"}, RequireDigest: true}, policy)
+
+		err = p.checkImagePolicy(ctx, &ContainerRequest{}, "ghcr.io/org/app:1.0")
+		require.ErrorIs(t, err, ErrImagePolicyViolation)
+
+		// the policy of the request takes precedence.
+		req := &ContainerRequest{ImagePolicy: &ImagePolicy{}}
+		require.NoError(t, p.checkImagePolicy(ctx, req, "ghcr.io/org/app:1.0"))
+	})
+
+	t.Run("config/cosign-without-signatures", func(t *testing.T) {
+		p := &DockerProvider{config: config.Config{ImagePolicyCosignKey: "/src/cosign.pub"}}
+
+		_, err := p.imagePolicy(&ContainerRequest{})
+		require.EqualError(t, err, "image policy from configuration: the cosign key requires the cosign signatures directory")
+	})
+
+	t.Run("cosign/resolved-digest", func(t *testing.T) {
+		ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		dir := t.TempDir()
+		verifier, err := NewCosignVerifier(writeCosignKey(t, dir, ecdsaKey), dir)
+		require.NoError(t, err)
+		writeCosignSignature(t, dir, ecdsaKey, "index.docker.io/library/redis", lockedDigest)
+
+		p := &DockerProvider{client: &lockMockCli{repoDigests: map[string][]string{
+			"redis:7": {"redis@" + lockedDigest},
+			"redis:8": {"redis@" + driftedDigest},
+		}}}
+		req := &ContainerRequest{ImagePolicy: &ImagePolicy{Cosign: verifier}}
+
+		require.NoError(t, p.checkImagePolicy(ctx, req, "redis:7"))
+		require.ErrorIs(t, p.checkImagePolicy(ctx, req, "redis:8"), ErrImagePolicyViolation)
+	})
+
+	t.Run("dockerfile", func(t *testing.T) {
+		p := &DockerProvider{}
+		registryHost := "registry.example.com"
+		req := &ContainerRequest{
+			FromDockerfile: FromDockerfile{
+				Context:    "./testdata",
+				Dockerfile: "auth.Dockerfile",
+				BuildArgs: map[string]*string{
+					"REGISTRY_HOST": &registryHost,
+				},
+			},
+			ImagePolicy: &ImagePolicy{AllowedRegistries: []string{"mirror.corp"}},
+		}
+
+		err := p.checkImagePolicy(ctx, req, "testcontainers/built:latest")
+		require.ErrorIs(t, err, ErrImagePolicyViolation)
+		require.ErrorContains(t, err, "image registry.example.com/redis:5.0-alpine: registry registry.example.com is not allowed")
+
+		req.ImagePolicy.AllowedRegistries = []string{registryHost}
+		require.NoError(t, p.checkImagePolicy(ctx, req, "testcontainers/built:latest"))
+	})
+}
@@ -0,0 +1,307 @@
+package testcontainers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/containerd/platforms"
+	"github.com/moby/moby/api/types/jsonstream"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// defaultPrePullParallelism is the default maximum number of images pulled concurrently by [PrePullImages].
+const defaultPrePullParallelism = 4
+
+// pullProgressConsumer {
+
+// PullProgress is a progress event of the pull of an image, as reported by the Docker daemon.
+type PullProgress struct {
+	Image   string // the image being pulled
+	Layer   string // the ID of the layer, empty for the events of the whole image
+	Status  string // the status of the layer, such as "Downloading", "Extracting" or "Pull complete"
+	Current int64  // the bytes of the layer processed so far, zero if unknown
+	Total   int64  // the size of the layer in bytes, zero if unknown
+}
+
+// PullProgressConsumer represents any object that can handle the progress events of image pulls.
+// Accept may be called concurrently when several images are pulled at the same time.
+type PullProgressConsumer interface {
+	Accept(PullProgress)
+}
+
+// }
+
+// pullCall is a pull in progress, shared by all the callers pulling the same image
+// from the same Docker daemon.
+type pullCall struct {
+	key  string
+	done chan struct{}
+	err  error
+
+	mtx       sync.Mutex
+	consumers []PullProgressConsumer
+}
+
+// inflightPulls are the pulls in progress, by key.
+var inflightPulls = struct {
+	sync.Mutex
+	calls map[string]*pullCall
+}{calls: map[string]*pullCall{}}
+
+// joinPull returns the pull in progress of the image, registering progress as one of its
+// consumers. It returns true if there was no pull in progress, so the caller must do the
+// pull and call finish when it completes.
+func joinPull(host, tag string, pullPlatforms []specs.Platform, progress PullProgressConsumer) (*pullCall, bool) {
+	key := host + "|" + tag
+	for _, p := range pullPlatforms {
+		key += "|" + platforms.Format(p)
+	}
+
+	inflightPulls.Lock()
+	defer inflightPulls.Unlock()
+
+	call, ok := inflightPulls.calls[key]
+	if !ok {
+		call = &pullCall{key: key, done: make(chan struct{})}
+		inflightPulls.calls[key] = call
+	}
+
+	if progress != nil {
+		call.mtx.Lock()
+		call.consumers = append(call.consumers, progress)
+		call.mtx.Unlock()
+	}
+
+	return call, !ok
+}
+
+// accept sends the progress event to the consumers of the pull.
+func (c *pullCall) accept(p PullProgress) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for _, consumer := range c.consumers {
+		consumer.Accept(p)
+	}
+}
+
+// finish removes the pull from the pulls in progress and releases the callers waiting for it.
+func (c *pullCall) finish() {
+	inflightPulls.Lock()
+	delete(inflightPulls.calls, c.key)
+	inflightPulls.Unlock()
+
+	close(c.done)
+}
+
+// readPullProgress reads the JSON messages of the pull of the image until the end of the
+// stream, sending the progress events to accept and returning the error reported by the
+// Docker daemon, if any.
+func readPullProgress(r io.Reader, tag string, accept func(PullProgress)) error {
+	dec := json.NewDecoder(r)
+	for {
+		var msg jsonstream.Message
+		if err := dec.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("read pull progress: %w", err)
+		}
+
+		if msg.Error != nil {
+			return fmt.Errorf("pull image %s: %w", tag, msg.Error)
+		}
+
+		if msg.Status == "" {
+			continue
+		}
+
+		event := PullProgress{
+			Image:  tag,
+			Layer:  msg.ID,
+			Status: msg.Status,
+		}
+		if msg.Progress != nil {
+			event.Current = msg.Progress.Current
+			event.Total = msg.Progress.Total
+		}
+
+		accept(event)
+	}
+}
+
+// PrePullImages pulls the images concurrently, so the containers using them start without
+// waiting for their pull. It is meant to be called from TestMain, before running the tests.
+// The images are substituted as configured, with the image substitution rules and the hub
+// image name prefix, and the configured pull policy decides which images are pulled: by
+// default, only the images not present locally. Use [FromDockerfile.Images] to pre-pull the images of a Dockerfile.
+func PrePullImages(ctx context.Context, images ...string) error {
+	return PrePullImagesWithOpts(ctx, images)
+}
+
+// PrePullImagesWithOpts pulls the images concurrently as [PrePullImages] does, passing options to the provider.
+func PrePullImagesWithOpts(ctx context.Context, images []string, opts ...PullImageOption) error {
+	p, err := NewDockerProvider()
+	if err != nil {
+		return fmt.Errorf("new docker provider: %w", err)
+	}
+	defer p.Close()
+
+	return p.PrePullImages(ctx, images, opts...)
+}
+
+// PrePullImages pulls the images concurrently, with at most four pulls at the same time
+// unless [PullDockerImagesWithParallelism] is used. Duplicated images are pulled once,
+// and so are the images being pulled by other goroutines, which are waited for. The image
+// lock of [PullDockerImageWithLock], or the one of the configuration, is applied to the images.
+func (p *DockerProvider) PrePullImages(ctx context.Context, images []string, opts ...PullImageOption) error {
+	pullOpts := pullImageOptions{parallelism: defaultPrePullParallelism}
+	for _, opt := range opts {
+		if err := opt(&pullOpts); err != nil {
+			return fmt.Errorf("applying pull image option: %w", err)
+		}
+	}
+
+	policy, err := p.pullPolicy(&ContainerRequest{})
+	if err != nil {
+		return err
+	}
+
+	var platform *specs.Platform
+	if len(pullOpts.dockerPullOpts.Platforms) == 1 {
+		platform = &pullOpts.dockerPullOpts.Platforms[0]
+	}
+
+	lock := pullOpts.lock
+	if lock == nil {
+		if lock, err = p.configImageLock(); err != nil {
+			return err
+		}
+	}
+
+	substitutors, err := p.configImageSubstitutors()
+	if err != nil {
+		return err
+	}
+	if lock != nil {
+		substitutors = append([]ImageSubstitutor{lock}, substitutors...)
+	}
+
+	// prePull is an image to pull, with the images substituted into it, as seen by the image lock.
+	type prePull struct {
+		name   string
+		locked []string
+	}
+
+	pulls := make([]*prePull, 0, len(images))
+	byName := make(map[string]*prePull, len(images))
+	for _, img := range images {
+		name := img
+		for _, is := range substitutors {
+			modifiedTag, err := is.Substitute(name)
+			if err != nil {
+				return fmt.Errorf("failed to substitute image %s with %s: %w", name, is.Description(), err)
+			}
+
+			if modifiedTag != name {
+				p.Logger.Printf("✍🏼 Replacing image with %s. From: %s to %s\n", is.Description(), name, modifiedTag)
+				name = modifiedTag
+			}
+		}
+
+		pull, ok := byName[name]
+		if !ok {
+			pull = &prePull{name: name}
+			byName[name] = pull
+			pulls = append(pulls, pull)
+		}
+		pull.locked = append(pull.locked, img)
+	}
+
+	sem := make(chan struct{}, pullOpts.parallelism)
+	errs := make([]error, len(pulls))
+
+	var wg sync.WaitGroup
+	for i, pull := range pulls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = fmt.Errorf("pre-pull %s: %w", pull.name, ctx.Err())
+				return
+			}
+
+			if err := p.prePullImage(ctx, pull.name, policy, platform, pullOpts); err != nil {
+				errs[i] = fmt.Errorf("pre-pull %s: %w", pull.name, err)
+				return
+			}
+
+			if lock == nil {
+				return
+			}
+
+			for _, img := range pull.locked {
+				if err := lock.check(ctx, p.client, img, pull.name); err != nil {
+					errs[i] = fmt.Errorf("pre-pull %s: image lock: %w", pull.name, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// prePullImage pulls the image if the pull policy requires it.
+func (p *DockerProvider) prePullImage(ctx context.Context, name string, policy PullPolicy, platform *specs.Platform, pullOpts pullImageOptions) error {
+	local, err := p.localImage(ctx, name, platform)
+	if err != nil {
+		return fmt.Errorf("inspect image: %w", err)
+	}
+
+	shouldPull, err := policy.ShouldPull(ctx, name, local)
+	if err != nil {
+		return fmt.Errorf("pull policy: %w", err)
+	}
+
+	if !shouldPull {
+		return nil
+	}
+
+	p.Logger.Printf("⏬ Pre-pulling image %s", name)
+
+	return p.attemptToPullImage(ctx, name, pullOpts.dockerPullOpts, pullOpts.progress)
+}
+
+// Images returns the images the Dockerfile is built from, which can be pre-pulled with
+// [PrePullImages]. The build stages, the scratch image and the images referencing build
+// args without a value are skipped.
+func (df FromDockerfile) Images() ([]string, error) {
+	req := ContainerRequest{FromDockerfile: df}
+
+	images, err := req.dockerFileImages()
+	if err != nil {
+		return nil, err
+	}
+
+	pullable := make([]string, 0, len(images))
+	for _, img := range images {
+		if img == "scratch" || strings.Contains(img, "$") {
+			continue
+		}
+
+		pullable = append(pullable, img)
+	}
+
+	return pullable, nil
+}
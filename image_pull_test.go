@@ -0,0 +1,262 @@
+package testcontainers
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/log"
+)
+
+const pullStream = `{"status":"Pulling from library/alpine","id":"3"}
+{"status":"Pulling fs layer","progressDetail":{},"id":"f18232174bc9"}
+{"status":"Downloading","progressDetail":{"current":1024,"total":3642},"id":"f18232174bc9"}
+{"status":"Pull complete","progressDetail":{},"id":"f18232174bc9"}
+{"status":"Digest: sha256:1e42bbe2508154c9126d48c2b8a75420c3544343bf86fd041fb7527e017a4b4a"}
+`
+
+// progressRecorder records the progress events it accepts.
+type progressRecorder struct {
+	mtx    sync.Mutex
+	events []PullProgress
+}
+
+func (r *progressRecorder) Accept(p PullProgress) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.events = append(r.events, p)
+}
+
+// pullMockCli is a mock implementation of client.APIClient, which counts the pulls of
+// each image, blocking them until release is closed.
+type pullMockCli struct {
+	client.APIClient
+
+	release chan struct{}
+
+	mtx   sync.Mutex
+	pulls map[string]int
+	local map[string]bool
+}
+
+func (m *pullMockCli) ImagePull(ctx context.Context, ref string, _ client.ImagePullOptions) (client.ImagePullResponse, error) {
+	m.mtx.Lock()
+	m.pulls[ref]++
+	m.mtx.Unlock()
+
+	select {
+	case <-m.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	return fakeStreamResult{ReadCloser: io.NopCloser(strings.NewReader(pullStream))}, nil
+}
+
+// pullCount returns the number of pulls of the given image.
+func (m *pullMockCli) pullCount(ref string) int {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.pulls[ref]
+}
+
+func (m *pullMockCli) ImageInspect(_ context.Context, ref string, _ ...client.ImageInspectOption) (client.ImageInspectResult, error) {
+	if m.local[ref] {
+		return client.ImageInspectResult{}, nil
+	}
+
+	return client.ImageInspectResult{}, errdefs.ErrNotFound.WithMessage("no such image: " + ref)
+}
+
+func (m *pullMockCli) Close() error {
+	return nil
+}
+
+func newPullMockProvider(t *testing.T, local ...string) (*DockerProvider, *pullMockCli) {
+	t.Helper()
+
+	m := &pullMockCli{
+		release: make(chan struct{}),
+		pulls:   map[string]int{},
+		local:   map[string]bool{},
+	}
+	for _, img := range local {
+		m.local[img] = true
+	}
+
+	p := &DockerProvider{
+		DockerProviderOptions: &DockerProviderOptions{
+			GenericProviderOptions: &GenericProviderOptions{Logger: log.TestLogger(t)},
+		},
+		client: m,
+		host:   "unix://" + t.Name(),
+	}
+
+	return p, m
+}
+
+func TestReadPullProgress(t *testing.T) {
+	var events []PullProgress
+	err := readPullProgress(strings.NewReader(pullStream), "alpine:3", func(p PullProgress) {
+		events = append(events, p)
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 5)
+	require.Equal(t, PullProgress{Image: "alpine:3", Layer: "f18232174bc9", Status: "Downloading", Current: 1024, Total: 3642}, events[2])
+	require.Equal(t, PullProgress{Image: "alpine:3", Status: "Digest: sha256:1e42bbe2508154c9126d48c2b8a75420c3544343bf86fd041fb7527e017a4b4a"}, events[4])
+
+	t.Run("error", func(t *testing.T) {
+		stream := `{"status":"Pulling from library/alpine","id":"3"}
+{"errorDetail":{"message":"manifest unknown"},"error":"manifest unknown"}
+`
+		err := readPullProgress(strings.NewReader(stream), "alpine:3", func(PullProgress) {})
+		require.EqualError(t, err, "pull image alpine:3: manifest unknown")
+	})
+}
+
+func TestAttemptToPullImage_deduplicated(t *testing.T) {
+	p, m := newPullMockProvider(t)
+	ctx := context.Background()
+
+	recorders := make([]*progressRecorder, 3)
+	errs := make([]error, len(recorders))
+
+	var wg sync.WaitGroup
+	for i := range recorders {
+		recorders[i] = &progressRecorder{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = p.attemptToPullImage(ctx, "alpine:3", client.ImagePullOptions{}, recorders[i])
+		}()
+	}
+
+	// wait for all the callers to join the pull before releasing it.
+	require.Eventually(t, func() bool {
+		inflightPulls.Lock()
+		defer inflightPulls.Unlock()
+
+		call, ok := inflightPulls.calls[p.host+"|alpine:3"]
+		if !ok {
+			return false
+		}
+
+		call.mtx.Lock()
+		defer call.mtx.Unlock()
+		return len(call.consumers) == len(recorders)
+	}, 5*time.Second, 10*time.Millisecond)
+	close(m.release)
+	wg.Wait()
+
+	require.Equal(t, map[string]int{"alpine:3": 1}, m.pulls)
+	for i, r := range recorders {
+		require.NoError(t, errs[i])
+		require.Len(t, r.events, 5)
+	}
+}
+
+func TestPrePullImages(t *testing.T) {
+	p, m := newPullMockProvider(t, "nginx:alpine")
+	close(m.release)
+
+	recorder := &progressRecorder{}
+	err := p.PrePullImages(context.Background(),
+		[]string{"alpine:3", "redis:7", "alpine:3", "nginx:alpine", "postgres:16"},
+		PullDockerImageWithProgress(recorder),
+		PullDockerImagesWithParallelism(2),
+	)
+	require.NoError(t, err)
+
+	// the duplicated image is pulled once, and the local image is not pulled.
+	require.Equal(t, map[string]int{"alpine:3": 1, "redis:7": 1, "postgres:16": 1}, m.pulls)
+	require.Len(t, recorder.events, 15)
+
+	t.Run("invalid-parallelism", func(t *testing.T) {
+		err := p.PrePullImages(context.Background(), []string{"alpine:3"}, PullDockerImagesWithParallelism(0))
+		require.ErrorContains(t, err, "parallelism must be at least 1")
+	})
+}
+
+func TestFromDockerfile_Images(t *testing.T) {
+	dir := t.TempDir()
+	dockerfile := `ARG VERSION
+FROM --platform=$BUILDPLATFORM golang:1.25 AS build
+FROM build AS test
+FROM alpine:${VERSION}
+FROM scratch
+COPY --from=build /app /app
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte(dockerfile), 0o644))
+
+	images, err := FromDockerfile{Context: dir}.Images()
+	require.NoError(t, err)
+	require.Equal(t, []string{"golang:1.25"}, images)
+
+	version := "3"
+	images, err = FromDockerfile{
+		ContextArchive: buildContextArchive(t, inlineModTime, map[string]string{"Dockerfile": dockerfile}),
+		BuildArgs:      map[string]*string{"VERSION": &version},
+	}.Images()
+	require.NoError(t, err)
+	require.Equal(t, []string{"golang:1.25", "alpine:3"}, images)
+}
+
+func TestAttemptToPullImage_leaderCanceled(t *testing.T) {
+	p, m := newPullMockProvider(t)
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	leaderErr := make(chan error, 1)
+	go func() {
+		leaderErr <- p.attemptToPullImage(leaderCtx, "alpine:3", client.ImagePullOptions{}, nil)
+	}()
+
+	// the leader is pulling the image before the follower joins it.
+	require.Eventually(t, func() bool {
+		return m.pullCount("alpine:3") == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	recorder := &progressRecorder{}
+	followerErr := make(chan error, 1)
+	go func() {
+		followerErr <- p.attemptToPullImage(context.Background(), "alpine:3", client.ImagePullOptions{}, recorder)
+	}()
+
+	require.Eventually(t, func() bool {
+		inflightPulls.Lock()
+		defer inflightPulls.Unlock()
+
+		call, ok := inflightPulls.calls[p.host+"|alpine:3"]
+		if !ok {
+			return false
+		}
+
+		call.mtx.Lock()
+		defer call.mtx.Unlock()
+		return len(call.consumers) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.ErrorIs(t, <-leaderErr, context.Canceled)
+
+	// the follower, whose context is still live, pulls the image itself.
+	require.Eventually(t, func() bool {
+		return m.pullCount("alpine:3") == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	close(m.release)
+
+	require.NoError(t, <-followerErr)
+	require.Len(t, recorder.events, 5)
+}
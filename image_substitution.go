@@ -0,0 +1,143 @@
+package testcontainers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/distribution/reference"
+)
+
+// imageRuleSeparator separates the pattern and the replacement of an image substitution rule.
+const imageRuleSeparator = "->"
+
+// imageRule is an [ImageSubstitutor] rewriting the images matching a pattern, as defined by
+// the image.substitutions property, or the TESTCONTAINERS_IMAGE_SUBSTITUTIONS environment variable.
+type imageRule struct {
+	rule        string
+	pattern     *regexp.Regexp
+	withTag     bool
+	replacement string
+}
+
+// ParseImageSubstitutionRule returns an [ImageSubstitutor] applying the given rule, with the
+// form "<pattern> -> <replacement>". In the pattern, "*" matches any sequence of characters,
+// which replaces the "*" at the same position in the replacement. For example:
+//   - "ghcr.io/* -> mirror.corp/ghcr/*" rewrites the images of a registry, keeping their tag.
+//   - "bitnami/* -> mirror.corp/bitnami/*" rewrites the images of Docker Hub repositories.
+//   - "redis:7 -> redis:7@sha256:..." pins a tag to a digest.
+//
+// A pattern without a tag or digest matches the name of the image, whose tag or digest is
+// appended to the replacement. A pattern with a tag or digest matches the whole reference,
+// which is replaced. Docker Hub images match both their short and their fully qualified
+// names, such as "redis" and "docker.io/library/redis".
+func ParseImageSubstitutionRule(rule string) (ImageSubstitutor, error) {
+	pattern, replacement, ok := strings.Cut(rule, imageRuleSeparator)
+	pattern, replacement = strings.TrimSpace(pattern), strings.TrimSpace(replacement)
+	if !ok || pattern == "" || replacement == "" {
+		return nil, fmt.Errorf("invalid image substitution rule %q: expected <pattern> %s <replacement>", rule, imageRuleSeparator)
+	}
+
+	wildcards := strings.Count(pattern, "*")
+	if strings.Count(replacement, "*") > wildcards {
+		return nil, fmt.Errorf("invalid image substitution rule %q: the replacement has more wildcards than the pattern", rule)
+	}
+
+	parts := strings.Split(pattern, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+
+	return imageRule{
+		rule:        pattern + " " + imageRuleSeparator + " " + replacement,
+		pattern:     regexp.MustCompile("^" + strings.Join(parts, "(.*)") + "$"),
+		withTag:     hasTagOrDigest(pattern),
+		replacement: replacement,
+	}, nil
+}
+
+// parseImageSubstitutionRules returns the substitutors of the rules separated by semicolons.
+func parseImageSubstitutionRules(rules string) ([]ImageSubstitutor, error) {
+	var substitutors []ImageSubstitutor
+	var errs []error
+	for _, rule := range strings.Split(rules, ";") {
+		if strings.TrimSpace(rule) == "" {
+			continue
+		}
+
+		is, err := ParseImageSubstitutionRule(rule)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		substitutors = append(substitutors, is)
+	}
+
+	return substitutors, errors.Join(errs...)
+}
+
+// hasTagOrDigest returns true if the image reference, or pattern, has a tag or a digest.
+func hasTagOrDigest(s string) bool {
+	return strings.Contains(s, "@") || strings.LastIndex(s, ":") > strings.LastIndex(s, "/")
+}
+
+// Description returns the name of the type and the rule it applies.
+func (r imageRule) Description() string {
+	return fmt.Sprintf("ImageSubstitutionRule (%s)", r.rule)
+}
+
+// Substitute rewrites the image if it matches the pattern of the rule, returning the image
+// as is otherwise, or if the image is not a valid reference.
+func (r imageRule) Substitute(image string) (string, error) {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return image, nil
+	}
+
+	candidates := []string{reference.FamiliarName(ref), ref.Name()}
+	var suffix string
+	if r.withTag {
+		ref = reference.TagNameOnly(ref)
+		candidates = []string{reference.FamiliarString(ref), ref.String()}
+	} else {
+		suffix = strings.TrimPrefix(ref.String(), ref.Name())
+	}
+
+	for _, candidate := range candidates {
+		captures := r.pattern.FindStringSubmatch(candidate)
+		if captures == nil {
+			continue
+		}
+
+		substituted := r.replacement
+		for _, capture := range captures[1:] {
+			if !strings.Contains(substituted, "*") {
+				break
+			}
+			substituted = strings.Replace(substituted, "*", capture, 1)
+		}
+		substituted += suffix
+
+		if _, err := reference.ParseNormalizedNamed(substituted); err != nil {
+			return "", fmt.Errorf("invalid substituted image %q: %w", substituted, err)
+		}
+
+		return substituted, nil
+	}
+
+	return image, nil
+}
+
+// configImageSubstitutors returns the image substitutors defined in the configuration, which are
+// applied after the ones of the request: the image substitution rules, followed by the hub image
+// name prefix.
+func (p *DockerProvider) configImageSubstitutors() ([]ImageSubstitutor, error) {
+	substitutors, err := parseImageSubstitutionRules(p.config.ImageSubstitutions)
+	if err != nil {
+		return nil, fmt.Errorf("image substitutions from configuration: %w", err)
+	}
+
+	return append(substitutors, newPrependHubRegistry(p.config.HubImageNamePrefix)), nil
+}
@@ -0,0 +1,87 @@
+package testcontainers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/internal/config"
+)
+
+const redisDigest = "sha256:1e42bbe2508154c9126d48c2b8a75420c3544343bf86fd041fb7527e017a4b4a"
+
+func TestImageSubstitutionRule(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rule     string
+		image    string
+		expected string
+	}{
+		{name: "registry", rule: "ghcr.io/* -> mirror.corp/ghcr/*", image: "ghcr.io/org/app:1.0", expected: "mirror.corp/ghcr/org/app:1.0"},
+		{name: "registry/no-tag", rule: "ghcr.io/* -> mirror.corp/ghcr/*", image: "ghcr.io/org/app", expected: "mirror.corp/ghcr/org/app"},
+		{name: "registry/digest", rule: "ghcr.io/* -> mirror.corp/ghcr/*", image: "ghcr.io/org/app@" + redisDigest, expected: "mirror.corp/ghcr/org/app@" + redisDigest},
+		{name: "registry/no-match", rule: "ghcr.io/* -> mirror.corp/ghcr/*", image: "quay.io/org/app:1.0", expected: "quay.io/org/app:1.0"},
+		{name: "repository-glob", rule: "bitnami/* -> mirror.corp/bitnami/*", image: "bitnami/redis:7", expected: "mirror.corp/bitnami/redis:7"},
+		{name: "repository-glob/no-match", rule: "bitnami/* -> mirror.corp/bitnami/*", image: "redis:7", expected: "redis:7"},
+		{name: "hub/short-name", rule: "redis -> mirror.corp/redis", image: "redis:7", expected: "mirror.corp/redis:7"},
+		{name: "hub/qualified-name", rule: "docker.io/library/* -> mirror.corp/hub/*", image: "redis:7", expected: "mirror.corp/hub/redis:7"},
+		{name: "hub/exact-name", rule: "redis -> mirror.corp/redis", image: "redis-stack:7", expected: "redis-stack:7"},
+		{name: "several-wildcards", rule: "*.corp.io/team-*/* -> mirror.corp/*/*/*", image: "eu.corp.io/team-a/app:1", expected: "mirror.corp/eu/a/app:1"},
+		{name: "pin-digest", rule: "redis:7 -> redis:7@" + redisDigest, image: "redis:7", expected: "redis:7@" + redisDigest},
+		{name: "pin-digest/other-tag", rule: "redis:7 -> redis:7@" + redisDigest, image: "redis:8", expected: "redis:8"},
+		{name: "pin-digest/latest", rule: "redis:latest -> redis@" + redisDigest, image: "redis", expected: "redis@" + redisDigest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is, err := ParseImageSubstitutionRule(tc.rule)
+			require.NoError(t, err)
+
+			image, err := is.Substitute(tc.image)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, image)
+		})
+	}
+
+	t.Run("description", func(t *testing.T) {
+		is, err := ParseImageSubstitutionRule("  ghcr.io/*->mirror.corp/ghcr/* ")
+		require.NoError(t, err)
+		require.Equal(t, "ImageSubstitutionRule (ghcr.io/* -> mirror.corp/ghcr/*)", is.Description())
+	})
+
+	t.Run("invalid-substitution", func(t *testing.T) {
+		is, err := ParseImageSubstitutionRule("ghcr.io/* -> mirror.corp/Ghcr/*")
+		require.NoError(t, err)
+
+		_, err = is.Substitute("ghcr.io/org/app")
+		require.ErrorContains(t, err, `invalid substituted image "mirror.corp/Ghcr/org/app"`)
+	})
+}
+
+func TestParseImageSubstitutionRule_invalid(t *testing.T) {
+	for _, rule := range []string{"", "ghcr.io/*", "-> mirror.corp/*", "ghcr.io/* ->"} {
+		_, err := ParseImageSubstitutionRule(rule)
+		require.ErrorContains(t, err, "expected <pattern> -> <replacement>", rule)
+	}
+
+	_, err := ParseImageSubstitutionRule("ghcr.io/* -> mirror.corp/*/*")
+	require.ErrorContains(t, err, "the replacement has more wildcards than the pattern")
+}
+
+func TestDockerProvider_configImageSubstitutors(t *testing.T) {
+	p := &DockerProvider{config: config.Config{
+		ImageSubstitutions: "ghcr.io/* -> mirror.corp/ghcr/*; ;quay.io/* -> mirror.corp/quay/*",
+		HubImageNamePrefix: "mirror.corp/hub",
+	}}
+
+	substitutors, err := p.configImageSubstitutors()
+	require.NoError(t, err)
+	require.Len(t, substitutors, 3)
+	require.Equal(t, "ImageSubstitutionRule (ghcr.io/* -> mirror.corp/ghcr/*)", substitutors[0].Description())
+	require.Equal(t, "ImageSubstitutionRule (quay.io/* -> mirror.corp/quay/*)", substitutors[1].Description())
+	require.Equal(t, "HubImageSubstitutor (prepends mirror.corp/hub)", substitutors[2].Description())
+
+	p.config.ImageSubstitutions = "ghcr.io/*"
+	_, err = p.configImageSubstitutors()
+	require.ErrorContains(t, err, "image substitutions from configuration")
+}
@@ -0,0 +1,298 @@
+package testcontainers
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moby/patternmatcher"
+	"github.com/moby/patternmatcher/ignorefile"
+)
+
+const dockerIgnoreFile = ".dockerignore"
+
+// InlineContext is a build context assembled in memory from the text of the Dockerfile
+// and a tree of files, so the build context does not need to exist on disk. A ".dockerignore"
+// file in Files excludes files from the build context, as it does for a Context directory.
+// The build context is assembled once, so the readers of the files are only read once.
+type InlineContext struct {
+	// Dockerfile is the content of the Dockerfile, added to the build context with the name
+	// defined in FromDockerfile.Dockerfile. It can be omitted if the Dockerfile is in Files.
+	Dockerfile string
+	// Files are the files of the build context, keyed by their slash-separated path in the context.
+	Files map[string]InlineFile
+
+	once    sync.Once
+	archive []byte
+	err     error
+}
+
+// InlineFile is a file of an InlineContext. Exactly one of Content, Reader or HostPath
+// must be set.
+type InlineFile struct {
+	Content  []byte    // the content of the file
+	Reader   io.Reader // the reader of the content of the file
+	HostPath string    // a file or a directory on the host, copied recursively
+	FileMode int64     // the mode of the file, defaults to 0o644, or to the mode on the host for HostPath
+}
+
+// inlineModTime is the modification time of the files of an inline build context,
+// which is fixed so that assembling the same files produces the same archive.
+var inlineModTime = time.Unix(0, 0)
+
+// validate validates the InlineFile.
+func (f InlineFile) validate() error {
+	set := 0
+	if f.Content != nil {
+		set++
+	}
+	if f.Reader != nil {
+		set++
+	}
+	if f.HostPath != "" {
+		set++
+	}
+
+	if set != 1 {
+		return errors.New("exactly one of Content, Reader or HostPath must be specified")
+	}
+
+	return nil
+}
+
+// read returns the content of a file defined by its Content or its Reader.
+func (f InlineFile) read() ([]byte, error) {
+	if f.Reader != nil {
+		return io.ReadAll(f.Reader)
+	}
+
+	return f.Content, nil
+}
+
+// tarArchive returns the tar archive of the build context, using dockerfile as the name
+// of the Dockerfile.
+func (ic *InlineContext) tarArchive(dockerfile string) ([]byte, error) {
+	ic.once.Do(func() {
+		ic.archive, ic.err = ic.build(dockerfile)
+	})
+
+	return ic.archive, ic.err
+}
+
+// build assembles the tar archive of the build context.
+func (ic *InlineContext) build(dockerfile string) ([]byte, error) {
+	files := make(map[string]InlineFile, len(ic.Files)+1)
+	for name, f := range ic.Files {
+		p, err := inlinePath(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := files[p]; ok {
+			return nil, fmt.Errorf("duplicate file %q in inline context", p)
+		}
+
+		if err := f.validate(); err != nil {
+			return nil, fmt.Errorf("file %q: %w", p, err)
+		}
+
+		files[p] = f
+	}
+
+	dockerfile = path.Clean(filepath.ToSlash(dockerfile))
+	if ic.Dockerfile != "" {
+		if _, ok := files[dockerfile]; ok {
+			return nil, fmt.Errorf("dockerfile %q is defined both as Dockerfile and in Files", dockerfile)
+		}
+		files[dockerfile] = InlineFile{Content: []byte(ic.Dockerfile)}
+	}
+
+	if _, ok := files[dockerfile]; !ok {
+		return nil, fmt.Errorf("dockerfile %q not found in inline context", dockerfile)
+	}
+
+	var excluded []string
+	if f, ok := files[dockerIgnoreFile]; ok {
+		if f.HostPath != "" {
+			content, err := os.ReadFile(f.HostPath)
+			if err != nil {
+				return nil, fmt.Errorf("read %s: %w", dockerIgnoreFile, err)
+			}
+			f = InlineFile{Content: content, FileMode: f.FileMode}
+		}
+
+		content, err := f.read()
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", dockerIgnoreFile, err)
+		}
+		files[dockerIgnoreFile] = InlineFile{Content: content, FileMode: f.FileMode}
+
+		if excluded, err = ignorefile.ReadAll(bytes.NewReader(content)); err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", dockerIgnoreFile, err)
+		}
+	}
+
+	pm, err := patternmatcher.New(excluded)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", dockerIgnoreFile, err)
+	}
+
+	// The Dockerfile and the .dockerignore file are always sent to the daemon,
+	// as it happens with a Context directory.
+	isExcluded := func(p string) (bool, error) {
+		if p == dockerfile || p == dockerIgnoreFile {
+			return false, nil
+		}
+		return pm.MatchesOrParentMatches(filepath.FromSlash(p))
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	for _, p := range slices.Sorted(maps.Keys(files)) {
+		f := files[p]
+
+		if f.HostPath != "" {
+			if err := addInlineHostPath(tw, p, f, isExcluded); err != nil {
+				return nil, fmt.Errorf("add %q: %w", p, err)
+			}
+			continue
+		}
+
+		excluded, err := isExcluded(p)
+		if err != nil {
+			return nil, fmt.Errorf("match %q: %w", p, err)
+		}
+		if excluded {
+			continue
+		}
+
+		content, err := f.read()
+		if err != nil {
+			return nil, fmt.Errorf("read %q: %w", p, err)
+		}
+
+		mode := f.FileMode
+		if mode == 0 {
+			mode = 0o644
+		}
+
+		hdr := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     p,
+			Mode:     mode,
+			Size:     int64(len(content)),
+			ModTime:  inlineModTime,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, fmt.Errorf("write header %q: %w", p, err)
+		}
+
+		if _, err := tw.Write(content); err != nil {
+			return nil, fmt.Errorf("write %q: %w", p, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("close archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// addInlineHostPath adds the file or the directory on the host to the archive with the
+// name p, skipping the excluded files.
+func addInlineHostPath(tw *tar.Writer, p string, f InlineFile, isExcluded func(string) (bool, error)) error {
+	root := filepath.Clean(f.HostPath)
+
+	return filepath.WalkDir(root, func(hostPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, hostPath)
+		if err != nil {
+			return err
+		}
+
+		name := path.Join(p, filepath.ToSlash(rel))
+
+		excluded, err := isExcluded(name)
+		if err != nil {
+			return fmt.Errorf("match %q: %w", name, err)
+		}
+		if excluded {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(hostPath); err != nil {
+				return err
+			}
+		}
+
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+
+		hdr.Name = name
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		if f.FileMode != 0 && info.Mode().IsRegular() {
+			hdr.Mode = f.FileMode
+		}
+		hdr.ModTime = inlineModTime
+		hdr.Uname, hdr.Gname = "", ""
+		hdr.Uid, hdr.Gid = 0, 0
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("write header %q: %w", name, err)
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(hostPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		if _, err := io.Copy(tw, file); err != nil {
+			return fmt.Errorf("write %q: %w", name, err)
+		}
+
+		return nil
+	})
+}
+
+// inlinePath returns the clean slash-separated path of a file of an inline context,
+// which must be relative and inside the context.
+func inlinePath(name string) (string, error) {
+	p := path.Clean(filepath.ToSlash(name))
+	if p == "." || path.IsAbs(p) || p == ".." || strings.HasPrefix(p, "../") {
+		return "", fmt.Errorf("invalid path %q in inline context: it must be relative and inside the context", name)
+	}
+
+	return p, nil
+}
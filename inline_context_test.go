@@ -0,0 +1,171 @@
+package testcontainers
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// inlineArchiveFiles returns the regular files of the tar archive and their content.
+func inlineArchiveFiles(t *testing.T, archive []byte) map[string]string {
+	t.Helper()
+
+	files := map[string]string{}
+	tr := tar.NewReader(bytes.NewReader(archive))
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = string(content)
+	}
+
+	return files
+}
+
+func TestInlineContext(t *testing.T) {
+	hostDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(hostDir, "vendor"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(hostDir, "main.go"), []byte("package main"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(hostDir, "vendor", "dep.go"), []byte("package dep"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(hostDir, "notes.md"), []byte("notes"), 0o644))
+
+	req := ContainerRequest{
+		FromDockerfile: FromDockerfile{
+			Inline: &InlineContext{
+				Dockerfile: "FROM alpine",
+				Files: map[string]InlineFile{
+					"config/app.yaml":   {Content: []byte("key: value")},
+					"./config/env":      {Reader: strings.NewReader("FOO=bar")},
+					"src":               {HostPath: hostDir},
+					"ignored.txt":       {Content: []byte("ignored")},
+					dockerIgnoreFile:    {Content: []byte("ignored.txt\nsrc/vendor\n*.md\n!src/notes.md\nDockerfile")},
+					"config/.gitignore": {Content: []byte("*")},
+				},
+			},
+		},
+	}
+
+	buildContext, err := req.GetContext()
+	require.NoError(t, err)
+
+	archive, err := io.ReadAll(buildContext)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]string{
+		"Dockerfile":        "FROM alpine",
+		dockerIgnoreFile:    "ignored.txt\nsrc/vendor\n*.md\n!src/notes.md\nDockerfile",
+		"config/app.yaml":   "key: value",
+		"config/env":        "FOO=bar",
+		"config/.gitignore": "*",
+		"src/main.go":       "package main",
+		"src/notes.md":      "notes",
+	}, inlineArchiveFiles(t, archive))
+
+	// The archive is assembled once, so the readers can be consumed again.
+	buildContext, err = req.GetContext()
+	require.NoError(t, err)
+
+	again, err := io.ReadAll(buildContext)
+	require.NoError(t, err)
+	require.Equal(t, archive, again)
+
+	images, err := req.dockerFileImages()
+	require.NoError(t, err)
+	require.Equal(t, []string{"alpine"}, images)
+}
+
+func TestInlineContext_dockerfileInFiles(t *testing.T) {
+	req := ContainerRequest{
+		FromDockerfile: FromDockerfile{
+			Dockerfile: "build/app.Dockerfile",
+			Inline: &InlineContext{
+				Files: map[string]InlineFile{
+					"build/app.Dockerfile": {Content: []byte("FROM nginx:alpine")},
+				},
+			},
+		},
+	}
+
+	images, err := req.dockerFileImages()
+	require.NoError(t, err)
+	require.Equal(t, []string{"nginx:alpine"}, images)
+}
+
+func TestInlineContext_invalid(t *testing.T) {
+	testCases := []struct {
+		name        string
+		inline      *InlineContext
+		expectedErr string
+	}{
+		{
+			name:        "missing-dockerfile",
+			inline:      &InlineContext{Files: map[string]InlineFile{"a.txt": {Content: []byte("a")}}},
+			expectedErr: `dockerfile "Dockerfile" not found in inline context`,
+		},
+		{
+			name: "duplicate-dockerfile",
+			inline: &InlineContext{
+				Dockerfile: "FROM alpine",
+				Files:      map[string]InlineFile{"Dockerfile": {Content: []byte("FROM nginx")}},
+			},
+			expectedErr: `dockerfile "Dockerfile" is defined both as Dockerfile and in Files`,
+		},
+		{
+			name: "path-outside-context",
+			inline: &InlineContext{
+				Dockerfile: "FROM alpine",
+				Files:      map[string]InlineFile{"../secret": {Content: []byte("a")}},
+			},
+			expectedErr: "must be relative and inside the context",
+		},
+		{
+			name: "absolute-path",
+			inline: &InlineContext{
+				Dockerfile: "FROM alpine",
+				Files:      map[string]InlineFile{"/etc/passwd": {Content: []byte("a")}},
+			},
+			expectedErr: "must be relative and inside the context",
+		},
+		{
+			name: "no-source",
+			inline: &InlineContext{
+				Dockerfile: "FROM alpine",
+				Files:      map[string]InlineFile{"a.txt": {}},
+			},
+			expectedErr: "exactly one of Content, Reader or HostPath must be specified",
+		},
+		{
+			name: "several-sources",
+			inline: &InlineContext{
+				Dockerfile: "FROM alpine",
+				Files:      map[string]InlineFile{"a.txt": {Content: []byte("a"), HostPath: "a.txt"}},
+			},
+			expectedErr: "exactly one of Content, Reader or HostPath must be specified",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := ContainerRequest{FromDockerfile: FromDockerfile{Inline: tc.inline}}
+
+			_, err := req.GetContext()
+			require.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
@@ -3,10 +3,13 @@ package config
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,6 +20,22 @@ import (
 
 const ReaperDefaultImage = "testcontainers/ryuk:0.14.0"
 
+// The supported values of the host access mode.
+const (
+	HostAccessModeAuto        = "auto"
+	HostAccessModeHostGateway = "host-gateway"
+	HostAccessModeSSHD        = "sshd"
+)
+
+// The supported values of the pull policy. The max age policy is followed by a colon
+// and a duration, such as "max-age:24h".
+const (
+	PullPolicyIfNotPresent = "if-not-present"
+	PullPolicyAlways       = "always"
+	PullPolicyNever        = "never"
+	PullPolicyMaxAge       = "max-age"
+)
+
 // reaperNamePrefix is the prefix of the name of the reaper container, which is built
 // from the session ID. It is duplicated here, instead of imported, because the package
 // building that name depends on this one.
@@ -25,10 +44,26 @@ const reaperNamePrefix = "reaper_"
 // maxContainerNameLen is the maximum length of a container name.
 const maxContainerNameLen = 128
 
+// propertiesFile is the name of the properties files, in the home directory and in the project.
+const propertiesFile = ".testcontainers.properties"
+
+// sessionIDProperty is the property of the session ID.
+const sessionIDProperty = "session.id"
+
+// ErrAlreadyRead is returned when the configuration is set from code after it is read.
+var ErrAlreadyRead = errors.New("configuration already read")
+
 var (
 	tcConfig     Config
+	tcReport     Report
 	tcConfigOnce = new(sync.Once)
 
+	// overrides are the values set from code, by property, and tcConfigRead
+	// reports whether the configuration was read with them.
+	overrides    = map[string]string{}
+	tcConfigRead bool
+	overridesMx  sync.Mutex
+
 	// containerNameRegex matches the names accepted by the container runtime.
 	containerNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
 )
@@ -60,8 +95,10 @@ func validateSessionID(sessionID string) error {
 // testcontainersConfig {
 
 // Config represents the configuration for Testcontainers.
-// User values are read from ~/.testcontainers.properties file which can be overridden
-// using the specified environment variables. For more information, see [Custom Configuration].
+// User values are read from the .testcontainers.properties file of the project, found from
+// the working directory upward, and from the ~/.testcontainers.properties file, which takes
+// precedence. They can be overridden using the specified environment variables.
+// For more information, see [Custom Configuration].
 //
 // The Ryuk prefixed fields controls the [Garbage Collector] feature, which ensures that
 // resources are cleaned up after the test execution.
@@ -89,14 +126,14 @@ type Config struct {
 	// This is useful when running tests in environments with restricted internet access.
 	//
 	// Environment variable: TESTCONTAINERS_HUB_IMAGE_NAME_PREFIX
-	HubImageNamePrefix string `properties:"hub.image.name.prefix,default="`
+	HubImageNamePrefix string `properties:"hub.image.name.prefix,default=" env:"TESTCONTAINERS_HUB_IMAGE_NAME_PREFIX"`
 
 	// SessionID is the ID of the testing session.
 	// Setting this value will preclude runs from creating more than one reaper. Therefore,
 	// changes to ryuk settings past its creation will be ignored.
 	//
 	// Environment variable: TESTCONTAINERS_SESSION_ID
-	SessionID string `properties:"session.id,default="`
+	SessionID string `properties:"session.id,default=" env:"TESTCONTAINERS_SESSION_ID"`
 
 	// RyukDisabled is a flag to enable or disable the Garbage Collector.
 	// Setting this to true will prevent testcontainers from automatically cleaning up
@@ -104,28 +141,120 @@ type Config struct {
 	// don't run test clean up.
 	//
 	// Environment variable: TESTCONTAINERS_RYUK_DISABLED
-	RyukDisabled bool `properties:"ryuk.disabled,default=false"`
+	RyukDisabled bool `properties:"ryuk.disabled,default=false" env:"TESTCONTAINERS_RYUK_DISABLED"`
 
 	// RyukPrivileged is a flag to enable or disable the privileged mode for the Garbage Collector container.
 	// Setting this to true will run the Garbage Collector container in privileged mode.
 	//
 	// Environment variable: TESTCONTAINERS_RYUK_CONTAINER_PRIVILEGED
-	RyukPrivileged bool `properties:"ryuk.container.privileged,default=false"`
+	RyukPrivileged bool `properties:"ryuk.container.privileged,default=false" env:"TESTCONTAINERS_RYUK_CONTAINER_PRIVILEGED"`
 
 	// RyukReconnectionTimeout is the time to wait before attempting to reconnect to the Garbage Collector container.
 	//
 	// Environment variable: RYUK_RECONNECTION_TIMEOUT
-	RyukReconnectionTimeout time.Duration `properties:"ryuk.reconnection.timeout,default=10s"`
+	RyukReconnectionTimeout time.Duration `properties:"ryuk.reconnection.timeout,default=10s" env:"RYUK_RECONNECTION_TIMEOUT"`
 
 	// RyukConnectionTimeout is the time to wait before timing out when connecting to the Garbage Collector container.
 	//
 	// Environment variable: RYUK_CONNECTION_TIMEOUT
-	RyukConnectionTimeout time.Duration `properties:"ryuk.connection.timeout,default=1m"`
+	RyukConnectionTimeout time.Duration `properties:"ryuk.connection.timeout,default=1m" env:"RYUK_CONNECTION_TIMEOUT"`
 
 	// RyukVerbose is a flag to enable or disable verbose logging for the Garbage Collector.
 	//
 	// Environment variable: RYUK_VERBOSE
-	RyukVerbose bool `properties:"ryuk.verbose,default=false"`
+	RyukVerbose bool `properties:"ryuk.verbose,default=false" env:"RYUK_VERBOSE"`
+
+	// HostAccessMode selects how the containers reach the host ports exposed with the
+	// WithHostPortAccess option: "auto" (the default) uses "host-gateway" if the daemon supports
+	// it, falling back to "sshd" otherwise, "host-gateway" resolves the host internal hostname to
+	// the host-gateway of the daemon, and "sshd" forwards them through an SSHD container.
+	//
+	// Environment variable: TESTCONTAINERS_HOST_ACCESS_MODE
+	HostAccessMode string `properties:"host.access.mode,default=" env:"TESTCONTAINERS_HOST_ACCESS_MODE"`
+
+	// ImageSubstitutions are the rules rewriting the images of the containers, separated by semicolons,
+	// with the form "<pattern> -> <replacement>", such as "ghcr.io/* -> mirror.corp/ghcr/*".
+	// They are applied in order, after the image substitutors of the request.
+	//
+	// Environment variable: TESTCONTAINERS_IMAGE_SUBSTITUTIONS
+	ImageSubstitutions string `properties:"image.substitutions,default=" env:"TESTCONTAINERS_IMAGE_SUBSTITUTIONS"`
+
+	// ImageLockFile is the path of the lockfile recording the digests of the images of the containers.
+	// It should be an absolute path, as the tests of each package run in the directory of the package.
+	//
+	// Environment variable: TESTCONTAINERS_IMAGE_LOCK_FILE
+	ImageLockFile string `properties:"image.lock.file,default=" env:"TESTCONTAINERS_IMAGE_LOCK_FILE"`
+
+	// ImageLockMode defines how the digests of the lockfile are used: "rewrite" (default), "verify" or "update".
+	//
+	// Environment variable: TESTCONTAINERS_IMAGE_LOCK_MODE
+	ImageLockMode string `properties:"image.lock.mode,default=" env:"TESTCONTAINERS_IMAGE_LOCK_MODE"`
+
+	// ImageArchiveDir is the path of the directory with the image tarballs, with the ".tar", ".tar.gz"
+	// or ".tgz" extension, the images not present locally are loaded from before pulling them.
+	//
+	// Environment variable: TESTCONTAINERS_IMAGE_ARCHIVE_DIR
+	ImageArchiveDir string `properties:"image.archive.dir,default=" env:"TESTCONTAINERS_IMAGE_ARCHIVE_DIR"`
+
+	// ImagePolicyAllowedRegistries are the registries, separated by commas, the images of the containers
+	// can come from, such as "mirror.corp,*.corp.io". All registries are allowed if empty.
+	//
+	// Environment variable: TESTCONTAINERS_IMAGE_POLICY_ALLOWED_REGISTRIES
+	ImagePolicyAllowedRegistries string `properties:"image.policy.allowed.registries,default=" env:"TESTCONTAINERS_IMAGE_POLICY_ALLOWED_REGISTRIES"`
+
+	// ImagePolicyDeniedRegistries are the registries, separated by commas, the images of the containers
+	// cannot come from, such as "docker.io".
+	//
+	// Environment variable: TESTCONTAINERS_IMAGE_POLICY_DENIED_REGISTRIES
+	ImagePolicyDeniedRegistries string `properties:"image.policy.denied.registries,default=" env:"TESTCONTAINERS_IMAGE_POLICY_DENIED_REGISTRIES"`
+
+	// ImagePolicyRequireDigest is a flag to require the images of the containers to be pinned to a digest.
+	//
+	// Environment variable: TESTCONTAINERS_IMAGE_POLICY_REQUIRE_DIGEST
+	ImagePolicyRequireDigest bool `properties:"image.policy.require.digest,default=false" env:"TESTCONTAINERS_IMAGE_POLICY_REQUIRE_DIGEST"`
+
+	// ImagePolicyCosignKey is the path of the PEM encoded public key verifying the cosign
+	// signatures of the images of the containers.
+	//
+	// Environment variable: TESTCONTAINERS_IMAGE_POLICY_COSIGN_KEY
+	ImagePolicyCosignKey string `properties:"image.policy.cosign.key,default=" env:"TESTCONTAINERS_IMAGE_POLICY_COSIGN_KEY"`
+
+	// ImagePolicyCosignSignatures is the path of the directory with the cosign signatures of the images,
+	// as "sha256-<hex>.sig" and "sha256-<hex>.payload" files. It is required by ImagePolicyCosignKey.
+	//
+	// Environment variable: TESTCONTAINERS_IMAGE_POLICY_COSIGN_SIGNATURES
+	ImagePolicyCosignSignatures string `properties:"image.policy.cosign.signatures,default=" env:"TESTCONTAINERS_IMAGE_POLICY_COSIGN_SIGNATURES"`
+
+	// PullPolicy is the policy used to pull the images of the containers which do not define
+	// their own: "if-not-present" (default), "always", "never" or "max-age:<duration>".
+	//
+	// Environment variable: TESTCONTAINERS_PULL_POLICY
+	PullPolicy string `properties:"pull.policy,default=" env:"TESTCONTAINERS_PULL_POLICY"`
+
+	// SSHIdentityFile is the path of the private key authenticating to the Docker hosts of the form
+	// "ssh://user@host". By default, the keys of the SSH agent and the default keys of ~/.ssh are used.
+	//
+	// Environment variable: TESTCONTAINERS_SSH_IDENTITY_FILE
+	SSHIdentityFile string `properties:"ssh.identity.file,default=" env:"TESTCONTAINERS_SSH_IDENTITY_FILE"`
+
+	// SSHKnownHostsFile is the path of the known_hosts file verifying the host keys of the Docker hosts
+	// of the form "ssh://user@host". Defaults to ~/.ssh/known_hosts.
+	//
+	// Environment variable: TESTCONTAINERS_SSH_KNOWN_HOSTS_FILE
+	SSHKnownHostsFile string `properties:"ssh.known.hosts.file,default=" env:"TESTCONTAINERS_SSH_KNOWN_HOSTS_FILE"`
+
+	// SSHInsecureIgnoreHostKey is a flag to skip the verification of the host keys of the Docker hosts
+	// of the form "ssh://user@host". Only use it with throwaway hosts, such as a local sshd container.
+	//
+	// Environment variable: TESTCONTAINERS_SSH_INSECURE_IGNORE_HOST_KEY
+	SSHInsecureIgnoreHostKey bool `properties:"ssh.insecure.ignore.host.key,default=false" env:"TESTCONTAINERS_SSH_INSECURE_IGNORE_HOST_KEY"`
+
+	// OCIRuntime is the default OCI runtime of the containers, including the reaper and the sshd
+	// ones, such as "runsc" for gVisor, "kata" or "crun". The containers fall back to the default
+	// runtime of the Docker daemon when it does not have this one.
+	//
+	// Environment variable: TESTCONTAINERS_OCI_RUNTIME
+	OCIRuntime string `properties:"oci.runtime,default=" env:"TESTCONTAINERS_OCI_RUNTIME"`
 
 	// TestcontainersHost is the address of the Testcontainers host.
 	//
@@ -135,94 +264,216 @@ type Config struct {
 
 // }
 
-// Read reads from testcontainers properties file, if it exists
-// it is possible that certain values get overridden when set as environment variables
+// Read reads from testcontainers properties file, if it exists, and applies the values
+// set from code with [Configure]. It is possible that certain values get overridden
+// when set as environment variables. The configuration is read once.
 func Read() Config {
 	tcConfigOnce.Do(func() {
-		tcConfig = read()
+		overridesMx.Lock()
+		defer overridesMx.Unlock()
+
+		tcConfig, tcReport = readReport(overrides)
+		tcConfigRead = true
 	})
 
 	return tcConfig
 }
 
-// Reset resets the singleton instance of the Config struct,
-// allowing to read the configuration again.
+// ReadReport reads the configuration, as [Read] does, and returns the report
+// of its effective values and their sources.
+func ReadReport() Report {
+	Read()
+
+	return tcReport
+}
+
+// Configure sets configuration values from code. They override the values of the
+// properties file, and are overridden by the environment variables. It must be called
+// before the configuration is read, which happens on the first use of the library,
+// or it returns [ErrAlreadyRead]. No value is set if any of the options is invalid.
+func Configure(opts ...Option) error {
+	overridesMx.Lock()
+	defer overridesMx.Unlock()
+
+	if tcConfigRead {
+		return ErrAlreadyRead
+	}
+
+	values := make(map[string]string, len(opts))
+	var errs []error
+	for _, opt := range opts {
+		if err := validateProperty(opt.Key, opt.Value); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if opt.Key == sessionIDProperty {
+			if err := validateSessionID(opt.Value); err != nil {
+				errs = append(errs, fmt.Errorf("invalid session.id value %q: %w", opt.Value, err))
+				continue
+			}
+		}
+
+		values[opt.Key] = opt.Value
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("configure: %w", errors.Join(errs...))
+	}
+
+	maps.Copy(overrides, values)
+
+	return nil
+}
+
+// Reset resets the singleton instance of the Config struct, and the values set
+// with [Configure], allowing to read the configuration again.
 // Handy for testing, so do not use it in production code
 // This function is not thread-safe
 func Reset() {
 	tcConfigOnce = new(sync.Once)
+
+	overridesMx.Lock()
+	defer overridesMx.Unlock()
+
+	overrides = map[string]string{}
+	tcConfigRead = false
 }
 
 func read() Config {
+	overridesMx.Lock()
+	defer overridesMx.Unlock()
+
+	config, _ := readReport(overrides)
+
+	return config
+}
+
+// readReport reads the configuration from the project properties file, the home properties
+// file, the given values set from code and the environment variables, in order of precedence,
+// returning it with its report.
+// The unknown properties and the properties with invalid values are skipped, and reported.
+func readReport(overrides map[string]string) (Config, Report) {
 	config := Config{}
+	report := Report{}
 
-	applyEnvironmentConfiguration := func(config Config) Config {
-		ryukDisabledEnv := os.Getenv("TESTCONTAINERS_RYUK_DISABLED")
-		if parseBool(ryukDisabledEnv) {
-			config.RyukDisabled = ryukDisabledEnv == "true"
-		}
+	props := properties.NewProperties()
+	props.DisableExpansion = true
+	sources := map[string]Source{}
 
-		hubImageNamePrefix := os.Getenv("TESTCONTAINERS_HUB_IMAGE_NAME_PREFIX")
-		if hubImageNamePrefix != "" {
-			config.HubImageNamePrefix = hubImageNamePrefix
-		}
+	home, _ := os.UserHomeDir()
 
-		sessionID := os.Getenv("TESTCONTAINERS_SESSION_ID")
-		switch {
-		case sessionID != "":
-			if err := validateSessionID(sessionID); err != nil {
-				panic(fmt.Sprintf("invalid TESTCONTAINERS_SESSION_ID value %q: %s", sessionID, err))
-			}
-			config.SessionID = sessionID
-		case config.SessionID != "":
-			if err := validateSessionID(config.SessionID); err != nil {
-				panic(fmt.Sprintf("invalid session.id property value %q: %s", config.SessionID, err))
-			}
-		default:
-			config.SessionID = bootstrap.SessionID()
+	if projectProp := findProjectProperties(home); projectProp != "" {
+		if loadProperties(projectProp, props, sources, SourceProject, &report) {
+			report.ProjectFile = projectProp
 		}
+	}
 
-		ryukPrivilegedEnv := os.Getenv("TESTCONTAINERS_RYUK_CONTAINER_PRIVILEGED")
-		if parseBool(ryukPrivilegedEnv) {
-			config.RyukPrivileged = ryukPrivilegedEnv == "true"
+	if home != "" {
+		tcProp := filepath.Join(home, propertiesFile)
+		// init from a file
+		if loadProperties(tcProp, props, sources, SourceFile, &report) {
+			report.File = tcProp
 		}
+	}
+
+	for key, value := range overrides {
+		props.MustSet(key, value)
+		sources[key] = SourceCode
+	}
 
-		ryukVerboseEnv := readTestcontainersEnv("RYUK_VERBOSE")
-		if parseBool(ryukVerboseEnv) {
-			config.RyukVerbose = ryukVerboseEnv == "true"
+	// the defaults only apply when there are values to decode.
+	if report.File != "" || report.ProjectFile != "" || len(overrides) > 0 {
+		if err := props.Decode(&config); err != nil {
+			fmt.Printf("invalid testcontainers properties, returning an empty Testcontainers configuration: %v\n", err)
+			config = Config{}
 		}
+	}
+
+	envs := applyEnvironmentConfiguration(&config)
 
-		ryukReconnectionTimeoutEnv := readTestcontainersEnv("RYUK_RECONNECTION_TIMEOUT")
-		if timeout, err := time.ParseDuration(ryukReconnectionTimeoutEnv); err == nil {
-			config.RyukReconnectionTimeout = timeout
+	sessionID := config.SessionID
+	switch {
+	case envs["session.id"] != "":
+		if err := validateSessionID(sessionID); err != nil {
+			panic(fmt.Sprintf("invalid TESTCONTAINERS_SESSION_ID value %q: %s", sessionID, err))
+		}
+	case sessionID != "":
+		if err := validateSessionID(sessionID); err != nil {
+			panic(fmt.Sprintf("invalid session.id property value %q: %s", sessionID, err))
 		}
+	default:
+		config.SessionID = bootstrap.SessionID()
+	}
+
+	report.Settings = settings(config, sources, envs)
+
+	return config, report
+}
+
+// loadProperties sets the valid properties of the given file, if it exists, into the given
+// properties, recording the given source for them. It reports the unknown properties and
+// the invalid values, which are skipped, and returns whether the file was loaded.
+func loadProperties(path string, props *properties.Properties, sources map[string]Source, source Source, report *Report) bool {
+	fileProps, err := properties.LoadFile(path, properties.UTF8)
+	if err != nil {
+		return false
+	}
 
-		ryukConnectionTimeoutEnv := readTestcontainersEnv("RYUK_CONNECTION_TIMEOUT")
-		if timeout, err := time.ParseDuration(ryukConnectionTimeoutEnv); err == nil {
-			config.RyukConnectionTimeout = timeout
+	var invalid []error
+	for _, key := range fileProps.Keys() {
+		value, _ := fileProps.Get(key)
+		if err := validateProperty(key, value); err != nil {
+			report.Problems = append(report.Problems, fmt.Errorf("%s: %w", path, err))
+			if !errors.Is(err, errUnknownProperty) {
+				invalid = append(invalid, err)
+			}
+			continue
 		}
 
-		return config
+		props.MustSet(key, value)
+		sources[key] = source
 	}
 
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return applyEnvironmentConfiguration(config)
+	// the unknown properties are not printed, as the file is shared with the other
+	// Testcontainers languages, which have properties of their own.
+	if len(invalid) > 0 {
+		fmt.Printf("invalid testcontainers properties in %s are ignored: %v\n", path, errors.Join(invalid...))
 	}
 
-	tcProp := filepath.Join(home, ".testcontainers.properties")
-	// init from a file
-	properties, err := properties.LoadFile(tcProp, properties.UTF8)
+	return true
+}
+
+// findProjectProperties returns the project properties file: the first properties file found
+// from the working directory upward, up to the root of the repository, identified by its .git
+// entry, or the root of the file system. The properties file of the given home directory is
+// not a project one, so it returns an empty string when reaching it.
+func findProjectProperties(home string) string {
+	dir, err := os.Getwd()
 	if err != nil {
-		return applyEnvironmentConfiguration(config)
+		return ""
 	}
 
-	if err := properties.Decode(&config); err != nil {
-		fmt.Printf("invalid testcontainers properties file, returning an empty Testcontainers configuration: %v\n", err)
-		return applyEnvironmentConfiguration(config)
-	}
+	for {
+		if home != "" && dir == home {
+			return ""
+		}
+
+		path := filepath.Join(dir, propertiesFile)
+		if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
+			return path
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return ""
+		}
 
-	return applyEnvironmentConfiguration(config)
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
 }
 
 func parseBool(input string) bool {
@@ -230,17 +481,63 @@ func parseBool(input string) bool {
 	return err == nil
 }
 
-// readTestcontainersEnv reads the environment variable with the given name.
+// applyEnvironmentConfiguration overrides the given configuration with the environment
+// variables of its properties, see the env tags of [Config]. The variables which are not
+// set, or set with an invalid value, are skipped. It returns the variables applied, by property.
+func applyEnvironmentConfiguration(config *Config) map[string]string {
+	v := reflect.ValueOf(config).Elem()
+
+	envs := map[string]string{}
+	for _, f := range fields() {
+		if f.env == "" {
+			continue
+		}
+
+		env, value := readTestcontainersEnv(f.env)
+		if value == "" {
+			continue
+		}
+
+		fv := v.Field(f.index)
+		switch {
+		case f.typ == reflect.TypeFor[time.Duration]():
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				continue
+			}
+			fv.SetInt(int64(d))
+		case f.typ.Kind() == reflect.Bool:
+			if !parseBool(value) {
+				continue
+			}
+			fv.SetBool(value == "true")
+		case f.typ.Kind() == reflect.String:
+			fv.SetString(value)
+		default:
+			continue
+		}
+
+		envs[f.key] = env
+	}
+
+	return envs
+}
+
+// readTestcontainersEnv reads the environment variable with the given name, returning
+// the name of the variable read and its value.
 // It checks for the environment variable with the given name first, and then
 // checks for the environment variable with the given name prefixed with "TESTCONTAINERS_".
-func readTestcontainersEnv(envVar string) string {
+func readTestcontainersEnv(envVar string) (string, string) {
 	value := os.Getenv(envVar)
 	if value != "" {
-		return value
+		return envVar, value
 	}
 
 	// TODO: remove this prefix after the next major release
 	const prefix string = "TESTCONTAINERS_"
+	if strings.HasPrefix(envVar, prefix) {
+		return envVar, ""
+	}
 
-	return os.Getenv(prefix + envVar)
+	return prefix + envVar, os.Getenv(prefix + envVar)
 }
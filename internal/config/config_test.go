@@ -25,6 +25,21 @@ func resetTestEnv(t *testing.T) {
 	t.Helper()
 	t.Setenv("TESTCONTAINERS_HUB_IMAGE_NAME_PREFIX", "")
 	t.Setenv("TESTCONTAINERS_SESSION_ID", "")
+	t.Setenv("TESTCONTAINERS_HOST_ACCESS_MODE", "")
+	t.Setenv("TESTCONTAINERS_PULL_POLICY", "")
+	t.Setenv("TESTCONTAINERS_OCI_RUNTIME", "")
+	t.Setenv("TESTCONTAINERS_IMAGE_SUBSTITUTIONS", "")
+	t.Setenv("TESTCONTAINERS_IMAGE_LOCK_FILE", "")
+	t.Setenv("TESTCONTAINERS_IMAGE_LOCK_MODE", "")
+	t.Setenv("TESTCONTAINERS_IMAGE_ARCHIVE_DIR", "")
+	t.Setenv("TESTCONTAINERS_IMAGE_POLICY_ALLOWED_REGISTRIES", "")
+	t.Setenv("TESTCONTAINERS_IMAGE_POLICY_DENIED_REGISTRIES", "")
+	t.Setenv("TESTCONTAINERS_IMAGE_POLICY_REQUIRE_DIGEST", "")
+	t.Setenv("TESTCONTAINERS_IMAGE_POLICY_COSIGN_KEY", "")
+	t.Setenv("TESTCONTAINERS_IMAGE_POLICY_COSIGN_SIGNATURES", "")
+	t.Setenv("TESTCONTAINERS_SSH_IDENTITY_FILE", "")
+	t.Setenv("TESTCONTAINERS_SSH_KNOWN_HOSTS_FILE", "")
+	t.Setenv("TESTCONTAINERS_SSH_INSECURE_IGNORE_HOST_KEY", "")
 	t.Setenv("TESTCONTAINERS_RYUK_DISABLED", "")
 	t.Setenv("TESTCONTAINERS_RYUK_CONTAINER_PRIVILEGED", "")
 	t.Setenv("RYUK_VERBOSE", "")
@@ -500,9 +515,23 @@ func TestReadTCConfig(t *testing.T) {
 					"TESTCONTAINERS_RYUK_CONTAINER_PRIVILEGED": "true",
 				},
 				Config{
-					SessionID:      bootstrap.SessionID(),
-					RyukDisabled:   true,
-					RyukPrivileged: true,
+					SessionID:               bootstrap.SessionID(),
+					RyukDisabled:            true,
+					RyukPrivileged:          true,
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
+				},
+			},
+			{
+				"With an invalid duration, the other properties are kept",
+				`ryuk.connection.timeout=forever
+				docker.host = ` + tcpDockerHost1234,
+				map[string]string{},
+				Config{
+					SessionID:               bootstrap.SessionID(),
+					Host:                    tcpDockerHost1234,
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
 				},
 			},
 			{
@@ -558,6 +587,229 @@ func TestReadTCConfig(t *testing.T) {
 					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
 				},
 			},
+			{
+				"With host access mode set as a property",
+				`host.access.mode=auto`,
+				map[string]string{},
+				Config{
+					SessionID:               bootstrap.SessionID(),
+					HostAccessMode:          HostAccessModeAuto,
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
+				},
+			},
+			{
+				"With host access mode set as env var and properties: Env var wins",
+				`host.access.mode=auto`,
+				map[string]string{
+					"TESTCONTAINERS_HOST_ACCESS_MODE": HostAccessModeHostGateway,
+				},
+				Config{
+					SessionID:               bootstrap.SessionID(),
+					HostAccessMode:          HostAccessModeHostGateway,
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
+				},
+			},
+			{
+				"With image substitutions set as a property",
+				`image.substitutions=ghcr.io/* -> mirror.corp/ghcr/*; \
+    redis:7 -> redis:7@sha256:1e42bbe2508154c9126d48c2b8a75420c3544343bf86fd041fb7527e017a4b4a`,
+				map[string]string{},
+				Config{
+					SessionID:               bootstrap.SessionID(),
+					ImageSubstitutions:      "ghcr.io/* -> mirror.corp/ghcr/*; redis:7 -> redis:7@sha256:1e42bbe2508154c9126d48c2b8a75420c3544343bf86fd041fb7527e017a4b4a",
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
+				},
+			},
+			{
+				"With image substitutions set as env var and properties: Env var wins",
+				`image.substitutions=ghcr.io/* -> mirror.corp/ghcr/*`,
+				map[string]string{
+					"TESTCONTAINERS_IMAGE_SUBSTITUTIONS": "quay.io/* -> mirror.corp/quay/*",
+				},
+				Config{
+					SessionID:               bootstrap.SessionID(),
+					ImageSubstitutions:      "quay.io/* -> mirror.corp/quay/*",
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
+				},
+			},
+			{
+				"With image lock set as a property",
+				`image.lock.file=/src/testcontainers.lock
+	image.lock.mode=verify`,
+				map[string]string{},
+				Config{
+					SessionID:               bootstrap.SessionID(),
+					ImageLockFile:           "/src/testcontainers.lock",
+					ImageLockMode:           "verify",
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
+				},
+			},
+			{
+				"With image lock set as env var and properties: Env var wins",
+				`image.lock.file=/src/testcontainers.lock
+	image.lock.mode=verify`,
+				map[string]string{
+					"TESTCONTAINERS_IMAGE_LOCK_FILE": "/ci/testcontainers.lock",
+					"TESTCONTAINERS_IMAGE_LOCK_MODE": "update",
+				},
+				Config{
+					SessionID:               bootstrap.SessionID(),
+					ImageLockFile:           "/ci/testcontainers.lock",
+					ImageLockMode:           "update",
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
+				},
+			},
+			{
+				"With image archive directory set as a property",
+				`image.archive.dir=/src/images`,
+				map[string]string{},
+				Config{
+					SessionID:               bootstrap.SessionID(),
+					ImageArchiveDir:         "/src/images",
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
+				},
+			},
+			{
+				"With image archive directory set as env var and properties: Env var wins",
+				`image.archive.dir=/src/images`,
+				map[string]string{
+					"TESTCONTAINERS_IMAGE_ARCHIVE_DIR": "/ci/images",
+				},
+				Config{
+					SessionID:               bootstrap.SessionID(),
+					ImageArchiveDir:         "/ci/images",
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
+				},
+			},
+			{
+				"With image policy set as a property",
+				`image.policy.allowed.registries=mirror.corp,*.corp.io
+	image.policy.denied.registries=docker.io
+	image.policy.require.digest=true
+	image.policy.cosign.key=/src/cosign.pub
+	image.policy.cosign.signatures=/src/signatures`,
+				map[string]string{},
+				Config{
+					SessionID:                    bootstrap.SessionID(),
+					ImagePolicyAllowedRegistries: "mirror.corp,*.corp.io",
+					ImagePolicyDeniedRegistries:  "docker.io",
+					ImagePolicyRequireDigest:     true,
+					ImagePolicyCosignKey:         "/src/cosign.pub",
+					ImagePolicyCosignSignatures:  "/src/signatures",
+					RyukConnectionTimeout:        defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout:      defaultRyukReconnectionTimeout,
+				},
+			},
+			{
+				"With image policy set as env var and properties: Env var wins",
+				`image.policy.allowed.registries=mirror.corp
+	image.policy.require.digest=true
+	image.policy.cosign.key=/src/cosign.pub`,
+				map[string]string{
+					"TESTCONTAINERS_IMAGE_POLICY_ALLOWED_REGISTRIES": "ghcr.io",
+					"TESTCONTAINERS_IMAGE_POLICY_DENIED_REGISTRIES":  "quay.io",
+					"TESTCONTAINERS_IMAGE_POLICY_REQUIRE_DIGEST":     "false",
+					"TESTCONTAINERS_IMAGE_POLICY_COSIGN_KEY":         "/ci/cosign.pub",
+					"TESTCONTAINERS_IMAGE_POLICY_COSIGN_SIGNATURES":  "/ci/signatures",
+				},
+				Config{
+					SessionID:                    bootstrap.SessionID(),
+					ImagePolicyAllowedRegistries: "ghcr.io",
+					ImagePolicyDeniedRegistries:  "quay.io",
+					ImagePolicyCosignKey:         "/ci/cosign.pub",
+					ImagePolicyCosignSignatures:  "/ci/signatures",
+					RyukConnectionTimeout:        defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout:      defaultRyukReconnectionTimeout,
+				},
+			},
+			{
+				"With ssh settings set as a property",
+				`ssh.identity.file=/src/id_ed25519
+	ssh.known.hosts.file=/src/known_hosts
+	ssh.insecure.ignore.host.key=true`,
+				map[string]string{},
+				Config{
+					SessionID:                bootstrap.SessionID(),
+					SSHIdentityFile:          "/src/id_ed25519",
+					SSHKnownHostsFile:        "/src/known_hosts",
+					SSHInsecureIgnoreHostKey: true,
+					RyukConnectionTimeout:    defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout:  defaultRyukReconnectionTimeout,
+				},
+			},
+			{
+				"With ssh settings set as env var and properties: Env var wins",
+				`ssh.identity.file=/src/id_ed25519
+	ssh.insecure.ignore.host.key=true`,
+				map[string]string{
+					"TESTCONTAINERS_SSH_IDENTITY_FILE":            "/ci/id_rsa",
+					"TESTCONTAINERS_SSH_KNOWN_HOSTS_FILE":         "/ci/known_hosts",
+					"TESTCONTAINERS_SSH_INSECURE_IGNORE_HOST_KEY": "false",
+				},
+				Config{
+					SessionID:               bootstrap.SessionID(),
+					SSHIdentityFile:         "/ci/id_rsa",
+					SSHKnownHostsFile:       "/ci/known_hosts",
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
+				},
+			},
+			{
+				"With pull policy set as a property",
+				`pull.policy=max-age:24h`,
+				map[string]string{},
+				Config{
+					SessionID:               bootstrap.SessionID(),
+					PullPolicy:              "max-age:24h",
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
+				},
+			},
+			{
+				"With pull policy set as env var and properties: Env var wins",
+				`pull.policy=always`,
+				map[string]string{
+					"TESTCONTAINERS_PULL_POLICY": PullPolicyNever,
+				},
+				Config{
+					SessionID:               bootstrap.SessionID(),
+					PullPolicy:              PullPolicyNever,
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
+				},
+			},
+			{
+				"With OCI runtime set as a property",
+				`oci.runtime=runsc`,
+				map[string]string{},
+				Config{
+					SessionID:               bootstrap.SessionID(),
+					OCIRuntime:              "runsc",
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
+				},
+			},
+			{
+				"With OCI runtime set as env var and properties: Env var wins",
+				`oci.runtime=runsc`,
+				map[string]string{
+					"TESTCONTAINERS_OCI_RUNTIME": "crun",
+				},
+				Config{
+					SessionID:               bootstrap.SessionID(),
+					OCIRuntime:              "crun",
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
+				},
+			},
 			//
 			{
 				"With Session ID set as a property",
@@ -699,3 +951,180 @@ func TestReadConfigSessionIDValidation(t *testing.T) {
 		require.Equal(t, bootstrap.SessionID(), read().SessionID)
 	})
 }
+
+// setting returns the setting of the given property from the report.
+func setting(t *testing.T, report Report, key string) Setting {
+	t.Helper()
+
+	for _, s := range report.Settings {
+		if s.Key == key {
+			return s
+		}
+	}
+
+	t.Fatalf("setting %q not found", key)
+	return Setting{}
+}
+
+func TestReadReport(t *testing.T) {
+	resetTestEnv(t)
+	t.Cleanup(Reset)
+
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("USERPROFILE", tmpDir) // Windows support
+
+	tcProp := filepath.Join(tmpDir, ".testcontainers.properties")
+	content := `foo = bar
+ryuk.reconnection.timeout = soon
+ryuk.disabled = true
+hub.image.name.prefix = registry.mycompany.com/file
+ryuk.verbose = true
+`
+	require.NoError(t, os.WriteFile(tcProp, []byte(content), 0o600))
+
+	t.Setenv("TESTCONTAINERS_RYUK_DISABLED", "false")
+	t.Setenv("TESTCONTAINERS_RYUK_CONNECTION_TIMEOUT", "12s")
+
+	Reset()
+	require.NoError(t, Configure(
+		Option{Key: "hub.image.name.prefix", Value: "registry.mycompany.com/code"},
+		Option{Key: "pull.policy", Value: PullPolicyAlways},
+	))
+
+	report := ReadReport()
+	require.Equal(t, tcProp, report.File)
+
+	require.Equal(t, Setting{Key: "ryuk.disabled", Env: "TESTCONTAINERS_RYUK_DISABLED", Value: "false", Source: SourceEnv}, setting(t, report, "ryuk.disabled"))
+	require.Equal(t, Setting{Key: "ryuk.connection.timeout", Env: "TESTCONTAINERS_RYUK_CONNECTION_TIMEOUT", Value: "12s", Source: SourceEnv}, setting(t, report, "ryuk.connection.timeout"))
+	require.Equal(t, Setting{Key: "ryuk.reconnection.timeout", Env: "RYUK_RECONNECTION_TIMEOUT", Value: "10s", Source: SourceDefault}, setting(t, report, "ryuk.reconnection.timeout"))
+	require.Equal(t, Setting{Key: "ryuk.verbose", Env: "RYUK_VERBOSE", Value: "true", Source: SourceFile}, setting(t, report, "ryuk.verbose"))
+	require.Equal(t, Setting{Key: "hub.image.name.prefix", Env: "TESTCONTAINERS_HUB_IMAGE_NAME_PREFIX", Value: "registry.mycompany.com/code", Source: SourceCode}, setting(t, report, "hub.image.name.prefix"))
+	require.Equal(t, Setting{Key: "pull.policy", Env: "TESTCONTAINERS_PULL_POLICY", Value: PullPolicyAlways, Source: SourceCode}, setting(t, report, "pull.policy"))
+
+	require.Len(t, report.Problems, 2)
+	require.EqualError(t, report.Problems[0], tcProp+`: unknown property "foo"`)
+	require.ErrorContains(t, report.Problems[1], tcProp+`: invalid value "soon" for property "ryuk.reconnection.timeout"`)
+
+	require.Contains(t, report.String(), "ryuk.disabled=false (env TESTCONTAINERS_RYUK_DISABLED)\n")
+	require.Contains(t, report.String(), "pull.policy=always (code)\n")
+
+	cfg := Read()
+	require.False(t, cfg.RyukDisabled)
+	require.True(t, cfg.RyukVerbose)
+	require.Equal(t, "registry.mycompany.com/code", cfg.HubImageNamePrefix)
+	require.Equal(t, 12*time.Second, cfg.RyukConnectionTimeout)
+	require.Equal(t, 10*time.Second, cfg.RyukReconnectionTimeout)
+}
+
+func TestConfigure(t *testing.T) {
+	resetTestEnv(t)
+
+	t.Run("already-read", func(t *testing.T) {
+		t.Cleanup(Reset)
+		t.Setenv("HOME", "")
+		t.Setenv("USERPROFILE", "") // Windows support
+
+		Reset()
+		Read()
+
+		err := Configure(Option{Key: "ryuk.disabled", Value: "true"})
+		require.ErrorIs(t, err, ErrAlreadyRead)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Cleanup(Reset)
+		t.Setenv("HOME", "")
+		t.Setenv("USERPROFILE", "") // Windows support
+
+		Reset()
+		err := Configure(
+			Option{Key: "ryuk.disabled", Value: "true"},
+			Option{Key: "ryuk.connection.timeout", Value: "forever"},
+			Option{Key: "session.id", Value: "team/ci"},
+			Option{Key: "foo", Value: "bar"},
+		)
+		require.ErrorContains(t, err, `invalid value "forever" for property "ryuk.connection.timeout"`)
+		require.ErrorContains(t, err, `invalid session.id value "team/ci"`)
+		require.ErrorContains(t, err, `unknown property "foo"`)
+
+		// no value is set.
+		require.False(t, Read().RyukDisabled)
+	})
+
+	t.Run("without-file", func(t *testing.T) {
+		t.Cleanup(Reset)
+		t.Setenv("HOME", "")
+		t.Setenv("USERPROFILE", "") // Windows support
+
+		Reset()
+		require.NoError(t, Configure(
+			Option{Key: "ryuk.disabled", Value: "true"},
+			Option{Key: "session.id", Value: "ci-pipeline-42"},
+		))
+
+		cfg := Read()
+		require.True(t, cfg.RyukDisabled)
+		require.Equal(t, "ci-pipeline-42", cfg.SessionID)
+		require.Equal(t, time.Minute, cfg.RyukConnectionTimeout)
+	})
+}
+
+func TestReadProjectProperties(t *testing.T) {
+	resetTestEnv(t)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home) // Windows support
+
+	// the project properties file is in the root of the repository, above the working directory.
+	project := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(project, ".git"), 0o755))
+	projectProp := filepath.Join(project, ".testcontainers.properties")
+	require.NoError(t, os.WriteFile(projectProp, []byte(`hub.image.name.prefix = registry.mycompany.com/mirror
+ryuk.connection.timeout = 2m
+pull.policy = always
+`), 0o600))
+
+	wd := filepath.Join(project, "internal", "pkg")
+	require.NoError(t, os.MkdirAll(wd, 0o755))
+	t.Chdir(wd)
+
+	t.Run("project", func(t *testing.T) {
+		config, report := readReport(nil)
+		require.Equal(t, projectProp, report.ProjectFile)
+		require.Empty(t, report.File)
+
+		require.Equal(t, "registry.mycompany.com/mirror", config.HubImageNamePrefix)
+		require.Equal(t, 2*time.Minute, config.RyukConnectionTimeout)
+		require.Equal(t, 10*time.Second, config.RyukReconnectionTimeout)
+		require.Equal(t, PullPolicyAlways, config.PullPolicy)
+		require.Equal(t, SourceProject, setting(t, report, "pull.policy").Source)
+	})
+
+	t.Run("home-and-env-win", func(t *testing.T) {
+		homeProp := filepath.Join(home, ".testcontainers.properties")
+		require.NoError(t, os.WriteFile(homeProp, []byte("pull.policy = never"), 0o600))
+		t.Setenv("RYUK_CONNECTION_TIMEOUT", "12s")
+
+		config, report := readReport(nil)
+		require.Equal(t, projectProp, report.ProjectFile)
+		require.Equal(t, homeProp, report.File)
+
+		require.Equal(t, "registry.mycompany.com/mirror", config.HubImageNamePrefix)
+		require.Equal(t, 12*time.Second, config.RyukConnectionTimeout)
+		require.Equal(t, PullPolicyNever, config.PullPolicy)
+		require.Equal(t, SourceFile, setting(t, report, "pull.policy").Source)
+		require.Equal(t, SourceEnv, setting(t, report, "ryuk.connection.timeout").Source)
+	})
+
+	t.Run("outside-repository", func(t *testing.T) {
+		// the search stops at the root of the repository.
+		nested := filepath.Join(project, "nested")
+		require.NoError(t, os.MkdirAll(filepath.Join(nested, ".git"), 0o755))
+		t.Chdir(nested)
+
+		_, report := readReport(nil)
+		require.Empty(t, report.ProjectFile)
+	})
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errUnknownProperty is returned when a property is not a configuration property.
+var errUnknownProperty = errors.New("unknown property")
+
+// Source is the source of a configuration value.
+type Source string
+
+// The sources of the configuration values, in increasing order of precedence.
+const (
+	SourceDefault Source = "default"
+	SourceProject Source = "project"
+	SourceFile    Source = "file"
+	SourceCode    Source = "code"
+	SourceEnv     Source = "env"
+)
+
+// Option sets a configuration property from code, see [Configure].
+type Option struct {
+	// Key is the property, as in the properties file, e.g. "ryuk.disabled".
+	Key string
+
+	// Value is the value of the property, as in the properties file, e.g. "true".
+	Value string
+}
+
+// Setting is the effective value of a configuration property.
+type Setting struct {
+	// Key is the property, as in the properties file.
+	Key string
+
+	// Env is the environment variable overriding the property, if any.
+	Env string
+
+	// Value is the effective value of the property.
+	Value string
+
+	// Source is where the value comes from.
+	Source Source
+}
+
+// Report is the report of the configuration: its effective values and their sources,
+// and the problems found reading it.
+type Report struct {
+	// File is the properties file of the home directory, empty if there is none.
+	File string
+
+	// ProjectFile is the properties file of the project, found from the working
+	// directory upward, empty if there is none.
+	ProjectFile string
+
+	// Settings are the effective values of the configuration properties.
+	Settings []Setting
+
+	// Problems are the unknown properties and the invalid values of the properties
+	// files, which are ignored.
+	Problems []error
+}
+
+// String returns the report as text, one setting per line, followed by the problems.
+func (r Report) String() string {
+	var sb strings.Builder
+
+	file := r.File
+	if file == "" {
+		file = "none"
+	}
+	fmt.Fprintf(&sb, "properties file: %s\n", file)
+
+	projectFile := r.ProjectFile
+	if projectFile == "" {
+		projectFile = "none"
+	}
+	fmt.Fprintf(&sb, "project properties file: %s\n", projectFile)
+
+	for _, s := range r.Settings {
+		source := string(s.Source)
+		if s.Source == SourceEnv {
+			source += " " + s.Env
+		}
+		fmt.Fprintf(&sb, "%s=%s (%s)\n", s.Key, s.Value, source)
+	}
+
+	for _, err := range r.Problems {
+		fmt.Fprintf(&sb, "problem: %v\n", err)
+	}
+
+	return sb.String()
+}
+
+// field is a property of the configuration.
+type field struct {
+	index int
+	key   string
+	env   string
+	typ   reflect.Type
+}
+
+// fields returns the properties of the configuration, from the tags of its fields.
+var fields = sync.OnceValue(func() []field {
+	t := reflect.TypeFor[Config]()
+	fs := make([]field, 0, t.NumField())
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		key, _, _ := strings.Cut(sf.Tag.Get("properties"), ",")
+		if key == "" {
+			continue
+		}
+
+		fs = append(fs, field{index: i, key: key, env: sf.Tag.Get("env"), typ: sf.Type})
+	}
+
+	return fs
+})
+
+// lookupField returns the configuration field of the given property.
+func lookupField(key string) (field, bool) {
+	for _, f := range fields() {
+		if f.key == key {
+			return f, true
+		}
+	}
+
+	return field{}, false
+}
+
+// validateProperty verifies that the given property is a configuration property,
+// and that the given value can be decoded into it.
+func validateProperty(key, value string) error {
+	f, ok := lookupField(key)
+	if !ok {
+		return fmt.Errorf("%w %q", errUnknownProperty, key)
+	}
+
+	var err error
+	switch {
+	case f.typ == reflect.TypeFor[time.Duration]():
+		_, err = time.ParseDuration(value)
+	case f.typ.Kind() == reflect.Int:
+		_, err = strconv.Atoi(value)
+	case f.typ.Kind() == reflect.Bool:
+		switch strings.ToLower(value) {
+		case "yes", "no", "on", "off":
+		default:
+			_, err = strconv.ParseBool(value)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value %q for property %q: %w", value, key, err)
+	}
+
+	return nil
+}
+
+// settings returns the effective values of the given configuration, with their sources:
+// the given environment variables applied, or the given sources, by property.
+func settings(config Config, sources map[string]Source, envs map[string]string) []Setting {
+	v := reflect.ValueOf(config)
+
+	ss := make([]Setting, 0, len(fields()))
+	for _, f := range fields() {
+		s := Setting{
+			Key:    f.key,
+			Env:    f.env,
+			Value:  fmt.Sprint(v.Field(f.index).Interface()),
+			Source: SourceDefault,
+		}
+		if source, ok := sources[f.key]; ok {
+			s.Source = source
+		}
+		if env, ok := envs[f.key]; ok {
+			s.Env = env
+			s.Source = SourceEnv
+		}
+
+		ss = append(ss, s)
+	}
+
+	return ss
+}
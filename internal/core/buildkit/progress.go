@@ -0,0 +1,151 @@
+package buildkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// TraceID is the ID of the messages of the build output holding the BuildKit build status.
+const TraceID = "moby.buildkit.trace"
+
+// vertex is the state of a build step, as reported by the build status.
+type vertex struct {
+	index     int
+	name      string
+	started   bool
+	completed bool
+}
+
+// Progress prints the build status reported by BuildKit in plain text, similar to
+// the plain progress output of the Docker CLI.
+type Progress struct {
+	w        io.Writer
+	vertexes map[string]*vertex
+}
+
+// NewProgress returns a Progress printing to w.
+func NewProgress(w io.Writer) *Progress {
+	return &Progress{w: w, vertexes: map[string]*vertex{}}
+}
+
+// WriteTrace prints the build status held by the aux payload of a trace message,
+// which is a JSON string with the base64-encoded moby.buildkit.v1.StatusResponse.
+func (p *Progress) WriteTrace(aux json.RawMessage) error {
+	var status []byte
+	if err := json.Unmarshal(aux, &status); err != nil {
+		return fmt.Errorf("decode trace: %w", err)
+	}
+
+	fields, err := protoFields(status)
+	if err != nil {
+		return fmt.Errorf("decode status: %w", err)
+	}
+
+	for _, f := range fields {
+		switch f.num {
+		case 1: // vertexes
+			if err := p.writeVertex(f.value); err != nil {
+				return err
+			}
+		case 3: // logs
+			if err := p.writeLog(f.value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeVertex prints the changes of a moby.buildkit.v1.Vertex.
+func (p *Progress) writeVertex(msg []byte) error {
+	fields, err := protoFields(msg)
+	if err != nil {
+		return fmt.Errorf("decode vertex: %w", err)
+	}
+
+	var digest, name, errMsg string
+	var cached, started, completed bool
+	for _, f := range fields {
+		switch f.num {
+		case 1:
+			digest = string(f.value)
+		case 3:
+			name = string(f.value)
+		case 4:
+			cached = f.varint != 0
+		case 5:
+			started = true
+		case 6:
+			completed = true
+		case 7:
+			errMsg = string(f.value)
+		}
+	}
+
+	v := p.vertex(digest)
+	if name != "" {
+		v.name = name
+	}
+
+	if (started || cached) && !v.started {
+		v.started = true
+		fmt.Fprintf(p.w, "#%d %s\n", v.index, v.name)
+	}
+
+	if (completed || cached) && !v.completed {
+		v.completed = true
+		switch {
+		case errMsg != "":
+			fmt.Fprintf(p.w, "#%d ERROR: %s\n", v.index, errMsg)
+		case cached:
+			fmt.Fprintf(p.w, "#%d CACHED\n", v.index)
+		default:
+			fmt.Fprintf(p.w, "#%d DONE\n", v.index)
+		}
+	}
+
+	return nil
+}
+
+// writeLog prints the output of a moby.buildkit.v1.VertexLog.
+func (p *Progress) writeLog(msg []byte) error {
+	fields, err := protoFields(msg)
+	if err != nil {
+		return fmt.Errorf("decode log: %w", err)
+	}
+
+	var digest string
+	var data []byte
+	for _, f := range fields {
+		switch f.num {
+		case 1:
+			digest = string(f.value)
+		case 4:
+			data = f.value
+		}
+	}
+
+	v := p.vertex(digest)
+	for line := range bytes.Lines(data) {
+		fmt.Fprintf(p.w, "#%d %s", v.index, line)
+		if !bytes.HasSuffix(line, []byte("\n")) {
+			fmt.Fprintln(p.w)
+		}
+	}
+
+	return nil
+}
+
+// vertex returns the state of the vertex with the given digest.
+func (p *Progress) vertex(digest string) *vertex {
+	v, ok := p.vertexes[digest]
+	if !ok {
+		v = &vertex{index: len(p.vertexes) + 1}
+		p.vertexes[digest] = v
+	}
+
+	return v
+}
@@ -0,0 +1,70 @@
+package buildkit
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// traceAux returns the aux payload of a trace message holding the status.
+func traceAux(t *testing.T, status []byte) json.RawMessage {
+	t.Helper()
+
+	aux, err := json.Marshal(status)
+	require.NoError(t, err)
+
+	return aux
+}
+
+// vertexMessage returns an encoded moby.buildkit.v1.Vertex.
+func vertexMessage(digest, name string, started, completed bool, errMsg string) []byte {
+	msg := appendProtoBytes(nil, 1, []byte(digest))
+	msg = appendProtoBytes(msg, 3, []byte(name))
+	if started {
+		msg = appendProtoBytes(msg, 5, []byte{0x08, 0x01})
+	}
+	if completed {
+		msg = appendProtoBytes(msg, 6, []byte{0x08, 0x02})
+	}
+	if errMsg != "" {
+		msg = appendProtoBytes(msg, 7, []byte(errMsg))
+	}
+	return msg
+}
+
+func TestProgress_WriteTrace(t *testing.T) {
+	var out strings.Builder
+	p := NewProgress(&out)
+
+	started := appendProtoBytes(nil, 1, vertexMessage("sha256:a", "[1/2] FROM alpine", true, false, ""))
+	started = appendProtoBytes(started, 1, vertexMessage("sha256:b", "[2/2] RUN echo hello", true, false, ""))
+	require.NoError(t, p.WriteTrace(traceAux(t, started)))
+
+	logMsg := appendProtoBytes(nil, 1, []byte("sha256:b"))
+	logMsg = appendProtoBytes(logMsg, 4, []byte("hello\nworld"))
+	require.NoError(t, p.WriteTrace(traceAux(t, appendProtoBytes(nil, 3, logMsg))))
+
+	completed := appendProtoBytes(nil, 1, vertexMessage("sha256:a", "", true, true, ""))
+	completed = appendProtoBytes(completed, 1, vertexMessage("sha256:b", "", true, true, "exit code: 1"))
+	require.NoError(t, p.WriteTrace(traceAux(t, completed)))
+
+	// repeated updates are not printed twice.
+	require.NoError(t, p.WriteTrace(traceAux(t, completed)))
+
+	require.Equal(t, `#1 [1/2] FROM alpine
+#2 [2/2] RUN echo hello
+#2 hello
+#2 world
+#1 DONE
+#2 ERROR: exit code: 1
+`, out.String())
+}
+
+func TestProgress_WriteTrace_invalid(t *testing.T) {
+	p := NewProgress(&strings.Builder{})
+
+	require.Error(t, p.WriteTrace(json.RawMessage(`{}`)))
+	require.Error(t, p.WriteTrace(traceAux(t, []byte{0x0a, 0x10})))
+}
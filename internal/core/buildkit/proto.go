@@ -0,0 +1,128 @@
+package buildkit
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// The session messages are small protobuf messages with a handful of scalar fields,
+// so they are encoded and decoded by hand instead of depending on generated code.
+
+const (
+	wireVarint = 0
+	wireI64    = 1
+	wireBytes  = 2
+	wireI32    = 5
+
+	// maxMessageSize is the maximum size of a gRPC message accepted by the session.
+	maxMessageSize = 4 << 20
+)
+
+// protoField is a field of a protobuf message. Fields encoded with the varint wire type
+// hold their value in varint, and fields encoded with the bytes wire type in value.
+type protoField struct {
+	num    int
+	wire   int
+	varint uint64
+	value  []byte
+}
+
+// protoFields decodes the top-level fields of the protobuf message.
+func protoFields(msg []byte) ([]protoField, error) {
+	var fields []protoField
+	for len(msg) > 0 {
+		key, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return nil, errors.New("invalid field key")
+		}
+		msg = msg[n:]
+
+		f := protoField{num: int(key >> 3), wire: int(key & 7)}
+		switch f.wire {
+		case wireVarint:
+			if f.varint, n = binary.Uvarint(msg); n <= 0 {
+				return nil, errors.New("invalid varint")
+			}
+		case wireI64:
+			n = 8
+		case wireI32:
+			n = 4
+		case wireBytes:
+			size, m := binary.Uvarint(msg)
+			if m <= 0 || size > uint64(len(msg)-m) {
+				return nil, errors.New("invalid length")
+			}
+			f.value = msg[m : m+int(size)]
+			n = m + int(size)
+		default:
+			return nil, fmt.Errorf("unsupported wire type %d", f.wire)
+		}
+
+		if n > len(msg) {
+			return nil, errors.New("truncated message")
+		}
+		msg = msg[n:]
+
+		fields = append(fields, f)
+	}
+
+	return fields, nil
+}
+
+// protoBytes returns the last value of the bytes field with the given number.
+func protoBytes(msg []byte, num int) ([]byte, error) {
+	fields, err := protoFields(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var value []byte
+	for _, f := range fields {
+		if f.num == num && f.wire == wireBytes {
+			value = f.value
+		}
+	}
+
+	return value, nil
+}
+
+// appendProtoBytes appends the bytes field with the given number to the message.
+func appendProtoBytes(msg []byte, num int, value []byte) []byte {
+	msg = binary.AppendUvarint(msg, uint64(num<<3|wireBytes))
+	msg = binary.AppendUvarint(msg, uint64(len(value)))
+	return append(msg, value...)
+}
+
+// readMessage reads a length-prefixed gRPC message.
+func readMessage(r io.Reader) ([]byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+
+	if header[0] != 0 {
+		return nil, errors.New("compressed messages are not supported")
+	}
+
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > maxMessageSize {
+		return nil, fmt.Errorf("message of %d bytes exceeds the maximum size", size)
+	}
+
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, fmt.Errorf("read message: %w", err)
+	}
+
+	return msg, nil
+}
+
+// writeMessage writes a length-prefixed gRPC message.
+func writeMessage(w io.Writer, msg []byte) error {
+	buf := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(buf[1:], uint32(len(msg)))
+	_, err := w.Write(append(buf, msg...))
+	return err
+}
@@ -0,0 +1,428 @@
+// Package buildkit implements the client side of a BuildKit session, which the Docker
+// daemon uses to request secrets and SSH agent connections while it builds an image.
+package buildkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/net/http2"
+)
+
+const (
+	headerSessionID        = "X-Docker-Expose-Session-Uuid"
+	headerSessionName      = "X-Docker-Expose-Session-Name"
+	headerSessionSharedKey = "X-Docker-Expose-Session-Sharedkey"
+	headerSessionMethod    = "X-Docker-Expose-Session-Grpc-Method"
+
+	// headerSSHID is the gRPC metadata holding the ID of the forwarded SSH agent.
+	headerSSHID = "Buildkit.ssh.id"
+
+	methodHealthCheck  = "/grpc.health.v1.Health/Check"
+	methodGetSecret    = "/moby.buildkit.secrets.v1.Secrets/GetSecret"
+	methodCheckAgent   = "/moby.sshforward.v1.SSH/CheckAgent"
+	methodForwardAgent = "/moby.sshforward.v1.SSH/ForwardAgent"
+
+	// DefaultSSHID is the ID of the SSH agent used by RUN --mount=type=ssh without an id.
+	DefaultSSHID = "default"
+)
+
+// gRPC status codes returned by the session.
+const (
+	codeOK            = 0
+	codeInvalid       = 3
+	codeNotFound      = 5
+	codeUnimplemented = 12
+	codeInternal      = 13
+)
+
+// healthServing is the encoded grpc.health.v1.HealthCheckResponse with the SERVING status.
+var healthServing = []byte{0x08, 0x01}
+
+// Secret is a secret exposed to the build, read from a file or an environment variable.
+// When neither File nor Env are set, the secret is read from the environment variable
+// named after its ID.
+type Secret struct {
+	ID   string
+	File string
+	Env  string
+}
+
+// SSH is an SSH agent exposed to the build. Paths is either a single agent socket or a
+// list of private key files. When Paths is empty, the agent socket in SSH_AUTH_SOCK is used.
+type SSH struct {
+	ID    string
+	Paths []string
+}
+
+// Dialer opens the hijacked connection to the session endpoint of the Docker daemon.
+type Dialer func(ctx context.Context, proto string, meta map[string][]string) (net.Conn, error)
+
+// statusError is an error returned to the daemon with a gRPC status code.
+type statusError struct {
+	code int
+	msg  string
+}
+
+func (e *statusError) Error() string {
+	return e.msg
+}
+
+// Session is a BuildKit session exposing secrets and SSH agents to an image build.
+type Session struct {
+	id      string
+	secrets map[string]Secret
+	agents  map[string]func() (net.Conn, error)
+
+	mtx  sync.Mutex
+	conn net.Conn
+	done chan struct{}
+}
+
+// NewSession returns a session exposing the given secrets and SSH agents.
+func NewSession(secrets []Secret, agents []SSH) (*Session, error) {
+	s := &Session{
+		id:      uuid.NewString(),
+		secrets: make(map[string]Secret, len(secrets)),
+		agents:  make(map[string]func() (net.Conn, error), len(agents)),
+	}
+
+	for _, secret := range secrets {
+		if secret.ID == "" {
+			return nil, errors.New("secret ID is required")
+		}
+
+		if secret.File != "" && secret.Env != "" {
+			return nil, fmt.Errorf("secret %q: file and env are mutually exclusive", secret.ID)
+		}
+
+		if secret.File == "" && secret.Env == "" {
+			secret.Env = secret.ID
+		}
+
+		s.secrets[secret.ID] = secret
+	}
+
+	for _, a := range agents {
+		id := a.ID
+		if id == "" {
+			id = DefaultSSHID
+		}
+
+		dial, err := agentDialer(a.Paths)
+		if err != nil {
+			return nil, fmt.Errorf("ssh %q: %w", id, err)
+		}
+
+		s.agents[id] = dial
+	}
+
+	return s, nil
+}
+
+// ID returns the ID of the session, to be set in the build options.
+func (s *Session) ID() string {
+	return s.id
+}
+
+// Start dials the session endpoint of the daemon and serves the session in the background
+// until the session is closed or the context is done.
+func (s *Session) Start(ctx context.Context, dial Dialer) error {
+	methods := []string{methodHealthCheck}
+	if len(s.secrets) > 0 {
+		methods = append(methods, methodGetSecret)
+	}
+	if len(s.agents) > 0 {
+		methods = append(methods, methodCheckAgent, methodForwardAgent)
+	}
+
+	meta := map[string][]string{
+		headerSessionID:        {s.id},
+		headerSessionName:      {"testcontainers"},
+		headerSessionSharedKey: {s.id},
+		headerSessionMethod:    methods,
+	}
+
+	conn, err := dial(ctx, "h2c", meta)
+	if err != nil {
+		return fmt.Errorf("dial session: %w", err)
+	}
+
+	s.mtx.Lock()
+	s.conn = conn
+	s.done = make(chan struct{})
+	s.mtx.Unlock()
+
+	go func() {
+		defer close(s.done)
+		(&http2.Server{}).ServeConn(conn, &http2.ServeConnOpts{Context: ctx, Handler: s})
+	}()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-s.done:
+		}
+	}()
+
+	return nil
+}
+
+// Close closes the session, waiting for it to stop serving.
+func (s *Session) Close() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+
+	err := s.conn.Close()
+	<-s.done
+	s.conn = nil
+
+	if errors.Is(err, net.ErrClosed) {
+		// The daemon already closed the session.
+		return nil
+	}
+
+	return err
+}
+
+// ServeHTTP serves the gRPC requests of the daemon.
+func (s *Session) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/grpc")
+	w.WriteHeader(http.StatusOK)
+
+	var err error
+	switch r.URL.Path {
+	case methodHealthCheck:
+		err = unary(w, r, func([]byte) ([]byte, error) {
+			return healthServing, nil
+		})
+	case methodGetSecret:
+		err = unary(w, r, s.getSecret)
+	case methodCheckAgent:
+		err = unary(w, r, s.checkAgent)
+	case methodForwardAgent:
+		err = s.forwardAgent(w, r)
+	default:
+		err = &statusError{code: codeUnimplemented, msg: "unknown method " + r.URL.Path}
+	}
+
+	code, msg := codeOK, ""
+	if err != nil {
+		code, msg = codeInternal, err.Error()
+
+		var se *statusError
+		if errors.As(err, &se) {
+			code = se.code
+		}
+	}
+
+	w.Header().Set(http.TrailerPrefix+"Grpc-Status", strconv.Itoa(code))
+	if msg != "" {
+		w.Header().Set(http.TrailerPrefix+"Grpc-Message", url.PathEscape(msg))
+	}
+}
+
+// unary reads the request message, calls fn and writes its response message.
+func unary(w http.ResponseWriter, r *http.Request, fn func([]byte) ([]byte, error)) error {
+	req, err := readMessage(r.Body)
+	if err != nil {
+		return &statusError{code: codeInvalid, msg: fmt.Sprintf("read request: %s", err)}
+	}
+
+	resp, err := fn(req)
+	if err != nil {
+		return err
+	}
+
+	return writeMessage(w, resp)
+}
+
+// getSecret handles the GetSecret method, returning the value of the requested secret.
+func (s *Session) getSecret(req []byte) ([]byte, error) {
+	id, err := protoBytes(req, 1)
+	if err != nil {
+		return nil, &statusError{code: codeInvalid, msg: fmt.Sprintf("decode request: %s", err)}
+	}
+
+	secret, ok := s.secrets[string(id)]
+	if !ok {
+		return nil, &statusError{code: codeNotFound, msg: fmt.Sprintf("secret %s not found", id)}
+	}
+
+	var data []byte
+	if secret.File != "" {
+		data, err = os.ReadFile(secret.File)
+		if err != nil {
+			return nil, fmt.Errorf("read secret %s: %w", id, err)
+		}
+	} else {
+		value, ok := os.LookupEnv(secret.Env)
+		if !ok {
+			return nil, &statusError{code: codeNotFound, msg: fmt.Sprintf("secret %s not found: environment variable %s is not set", id, secret.Env)}
+		}
+		data = []byte(value)
+	}
+
+	return appendProtoBytes(nil, 1, data), nil
+}
+
+// checkAgent handles the CheckAgent method, verifying the requested SSH agent exists.
+func (s *Session) checkAgent(req []byte) ([]byte, error) {
+	id, err := protoBytes(req, 1)
+	if err != nil {
+		return nil, &statusError{code: codeInvalid, msg: fmt.Sprintf("decode request: %s", err)}
+	}
+
+	if _, err := s.agent(string(id)); err != nil {
+		return nil, err
+	}
+
+	return []byte{}, nil
+}
+
+// forwardAgent handles the ForwardAgent method, proxying the stream of messages to and
+// from a connection to the requested SSH agent.
+func (s *Session) forwardAgent(w http.ResponseWriter, r *http.Request) error {
+	dial, err := s.agent(r.Header.Get(headerSSHID))
+	if err != nil {
+		return err
+	}
+
+	conn, err := dial()
+	if err != nil {
+		return fmt.Errorf("dial ssh agent: %w", err)
+	}
+	defer conn.Close()
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return errors.New("streaming is not supported")
+	}
+	flusher.Flush()
+
+	go func() {
+		for {
+			req, err := readMessage(r.Body)
+			if err != nil {
+				if cw, ok := conn.(interface{ CloseWrite() error }); ok && errors.Is(err, io.EOF) {
+					_ = cw.CloseWrite()
+				} else {
+					conn.Close()
+				}
+				return
+			}
+
+			data, err := protoBytes(req, 1)
+			if err != nil {
+				conn.Close()
+				return
+			}
+
+			if _, err := conn.Write(data); err != nil {
+				return
+			}
+		}
+	}()
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := conn.Read(buf)
+		if n > 0 {
+			if werr := writeMessage(w, appendProtoBytes(nil, 1, buf[:n])); werr != nil {
+				return werr
+			}
+			flusher.Flush()
+		}
+
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) || errors.Is(err, io.ErrClosedPipe) {
+				return nil
+			}
+			return fmt.Errorf("read ssh agent: %w", err)
+		}
+	}
+}
+
+// agent returns the dialer of the SSH agent with the given ID.
+func (s *Session) agent(id string) (func() (net.Conn, error), error) {
+	if id == "" {
+		id = DefaultSSHID
+	}
+
+	dial, ok := s.agents[id]
+	if !ok {
+		return nil, &statusError{code: codeNotFound, msg: fmt.Sprintf("ssh agent %s not found", id)}
+	}
+
+	return dial, nil
+}
+
+// agentDialer returns a function connecting to the SSH agent described by paths,
+// which is either a single agent socket or a list of private key files served
+// by an in-memory agent.
+func agentDialer(paths []string) (func() (net.Conn, error), error) {
+	if len(paths) == 0 {
+		sock := os.Getenv("SSH_AUTH_SOCK")
+		if sock == "" {
+			return nil, errors.New("no paths given and SSH_AUTH_SOCK is not set")
+		}
+		paths = []string{sock}
+	}
+
+	keyring := agent.NewKeyring()
+	for _, p := range paths {
+		fi, err := os.Stat(p)
+		if err != nil {
+			return nil, fmt.Errorf("stat %s: %w", p, err)
+		}
+
+		if fi.Mode()&os.ModeSocket != 0 {
+			if len(paths) > 1 {
+				return nil, errors.New("an agent socket cannot be combined with other paths")
+			}
+
+			return func() (net.Conn, error) {
+				return net.Dial("unix", p)
+			}, nil
+		}
+
+		pem, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", p, err)
+		}
+
+		key, err := ssh.ParseRawPrivateKey(pem)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", p, err)
+		}
+
+		if err := keyring.Add(agent.AddedKey{PrivateKey: key}); err != nil {
+			return nil, fmt.Errorf("add %s: %w", p, err)
+		}
+	}
+
+	return func() (net.Conn, error) {
+		client, server := net.Pipe()
+		go func() {
+			_ = agent.ServeAgent(keyring, server)
+			server.Close()
+		}()
+		return client, nil
+	}, nil
+}
@@ -0,0 +1,227 @@
+package buildkit
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/net/http2"
+)
+
+// startSession starts the session over a loopback connection, returning the
+// HTTP/2 client used by the daemon to call the session.
+func startSession(t *testing.T, s *Session) *http.Client {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	var meta map[string][]string
+	err = s.Start(context.Background(), func(_ context.Context, proto string, m map[string][]string) (net.Conn, error) {
+		require.Equal(t, "h2c", proto)
+		meta = m
+		return net.Dial("tcp", ln.Addr().String())
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, s.Close()) })
+
+	require.Equal(t, []string{s.ID()}, meta[headerSessionID])
+	require.Contains(t, meta[headerSessionMethod], methodHealthCheck)
+
+	conn, err := ln.Accept()
+	require.NoError(t, err)
+
+	return &http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(context.Context, string, string, *tls.Config) (net.Conn, error) {
+				return conn, nil
+			},
+		},
+	}
+}
+
+// call calls the unary method, returning the response message and the gRPC status.
+func call(t *testing.T, c *http.Client, method string, req []byte) ([]byte, string) {
+	t.Helper()
+
+	var body bytes.Buffer
+	require.NoError(t, writeMessage(&body, req))
+
+	httpReq, err := http.NewRequest(http.MethodPost, "http://session"+method, &body)
+	require.NoError(t, err)
+	httpReq.Header.Set("Content-Type", "application/grpc")
+
+	resp, err := c.Do(httpReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var msg []byte
+	if len(data) > 0 {
+		msg, err = readMessage(bytes.NewReader(data))
+		require.NoError(t, err)
+	}
+
+	return msg, resp.Trailer.Get("Grpc-Status")
+}
+
+func TestSession_healthCheck(t *testing.T) {
+	s, err := NewSession(nil, nil)
+	require.NoError(t, err)
+
+	c := startSession(t, s)
+
+	msg, status := call(t, c, methodHealthCheck, nil)
+	require.Equal(t, "0", status)
+	require.Equal(t, healthServing, msg)
+}
+
+func TestSession_getSecret(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(file, []byte("from-file"), 0o600))
+
+	t.Setenv("TC_TEST_SECRET", "from-env")
+	t.Setenv("implicit", "from-implicit-env")
+
+	s, err := NewSession([]Secret{
+		{ID: "file", File: file},
+		{ID: "env", Env: "TC_TEST_SECRET"},
+		{ID: "implicit"},
+		{ID: "unset", Env: "TC_TEST_UNSET_SECRET"},
+	}, nil)
+	require.NoError(t, err)
+
+	c := startSession(t, s)
+
+	for id, expected := range map[string]string{
+		"file":     "from-file",
+		"env":      "from-env",
+		"implicit": "from-implicit-env",
+	} {
+		msg, status := call(t, c, methodGetSecret, appendProtoBytes(nil, 1, []byte(id)))
+		require.Equal(t, "0", status)
+
+		data, err := protoBytes(msg, 1)
+		require.NoError(t, err)
+		require.Equal(t, expected, string(data))
+	}
+
+	_, status := call(t, c, methodGetSecret, appendProtoBytes(nil, 1, []byte("unset")))
+	require.Equal(t, "5", status)
+
+	_, status = call(t, c, methodGetSecret, appendProtoBytes(nil, 1, []byte("unknown")))
+	require.Equal(t, "5", status)
+}
+
+func TestSession_unknownMethod(t *testing.T) {
+	s, err := NewSession(nil, nil)
+	require.NoError(t, err)
+
+	c := startSession(t, s)
+
+	_, status := call(t, c, "/moby.filesync.v1.FileSync/DiffCopy", nil)
+	require.Equal(t, "12", status)
+}
+
+func TestSession_forwardAgent(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	require.NoError(t, err)
+
+	key := filepath.Join(t.TempDir(), "id_ed25519")
+	require.NoError(t, os.WriteFile(key, pem.EncodeToMemory(block), 0o600))
+
+	s, err := NewSession(nil, []SSH{{Paths: []string{key}}})
+	require.NoError(t, err)
+
+	c := startSession(t, s)
+
+	_, status := call(t, c, methodCheckAgent, appendProtoBytes(nil, 1, []byte(DefaultSSHID)))
+	require.Equal(t, "0", status)
+
+	_, status = call(t, c, methodCheckAgent, appendProtoBytes(nil, 1, []byte("other")))
+	require.Equal(t, "5", status)
+
+	// the agent protocol is streamed through the session as BytesMessage messages.
+	reqReader, reqWriter := io.Pipe()
+	httpReq, err := http.NewRequest(http.MethodPost, "http://session"+methodForwardAgent, reqReader)
+	require.NoError(t, err)
+	httpReq.Header.Set("Content-Type", "application/grpc")
+	httpReq.Header.Set(headerSSHID, DefaultSSHID)
+
+	resp, err := c.Do(httpReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	local, remote := net.Pipe()
+	go func() {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := remote.Read(buf)
+			if err != nil {
+				reqWriter.Close()
+				return
+			}
+			if err := writeMessage(reqWriter, appendProtoBytes(nil, 1, buf[:n])); err != nil {
+				return
+			}
+		}
+	}()
+	go func() {
+		for {
+			msg, err := readMessage(resp.Body)
+			if err != nil {
+				remote.Close()
+				return
+			}
+			data, err := protoBytes(msg, 1)
+			if err != nil {
+				return
+			}
+			if _, err := remote.Write(data); err != nil {
+				return
+			}
+		}
+	}()
+
+	keys, err := agent.NewClient(local).List()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+
+	local.Close()
+}
+
+func TestNewSession_invalid(t *testing.T) {
+	t.Run("missing-secret-id", func(t *testing.T) {
+		_, err := NewSession([]Secret{{Env: "FOO"}}, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("secret-file-and-env", func(t *testing.T) {
+		_, err := NewSession([]Secret{{ID: "foo", File: "foo", Env: "FOO"}}, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("missing-ssh-key", func(t *testing.T) {
+		_, err := NewSession(nil, []SSH{{Paths: []string{filepath.Join(t.TempDir(), "missing")}}})
+		require.Error(t, err)
+	})
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"fmt"
 	"path/filepath"
 
 	"github.com/moby/moby/client"
@@ -20,18 +21,55 @@ func NewClient(ctx context.Context, ops ...client.Opt) (*client.Client, error) {
 
 	tcConfig := config.Read()
 
-	opts := []client.Opt{client.FromEnv}
+	var tlsOpts []client.Opt
 	if dockerHost != "" {
-		opts = append(opts, client.WithHost(dockerHost))
-
 		// for further information, read https://docs.docker.com/engine/security/protect-access/
 		if tcConfig.TLSVerify == 1 {
 			cacertPath := filepath.Join(tcConfig.CertPath, "ca.pem")
 			certPath := filepath.Join(tcConfig.CertPath, "cert.pem")
 			keyPath := filepath.Join(tcConfig.CertPath, "key.pem")
 
-			opts = append(opts, client.WithTLSClientConfig(cacertPath, certPath, keyPath))
+			tlsOpts = append(tlsOpts, client.WithTLSClientConfig(cacertPath, certPath, keyPath))
+		} else {
+			// the TLS material of the Docker CLI context the host comes from, if any.
+			tlsOpts, err = dockerContextClientOpts(dockerHost)
+			if err != nil {
+				return nil, fmt.Errorf("docker context: %w", err)
+			}
+		}
+	}
+
+	return newClient(dockerHost, tlsOpts, ops...)
+}
+
+// NewClientWithHost returns a new docker client connecting to the given docker host, instead
+// of the one extracted from the different alternatives. The TLS material of the Docker CLI
+// context the host comes from is used, if any, and the ops can set any other.
+func NewClientWithHost(ctx context.Context, dockerHost string, ops ...client.Opt) (*client.Client, error) {
+	tlsOpts, err := dockerContextClientOpts(dockerHost)
+	if err != nil {
+		return nil, fmt.Errorf("docker context: %w", err)
+	}
+
+	return newClient(dockerHost, tlsOpts, ops...)
+}
+
+// newClient returns a new docker client connecting to the docker host with the TLS options.
+func newClient(dockerHost string, tlsOpts []client.Opt, ops ...client.Opt) (*client.Client, error) {
+	tcConfig := config.Read()
+
+	opts := []client.Opt{client.FromEnv}
+	switch {
+	case IsSSHHost(dockerHost):
+		sshOpts, err := sshClientOpts(dockerHost)
+		if err != nil {
+			return nil, err
 		}
+
+		opts = append(opts, sshOpts...)
+	case dockerHost != "":
+		opts = append(opts, client.WithHost(dockerHost))
+		opts = append(opts, tlsOpts...)
 	}
 
 	opts = append(opts, client.WithHTTPHeaders(
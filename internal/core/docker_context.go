@@ -0,0 +1,213 @@
+package core
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/moby/moby/client"
+)
+
+// defaultDockerContext is the name of the Docker CLI context using DOCKER_HOST, or the default socket.
+const defaultDockerContext = "default"
+
+// ErrDockerContextNotSet is returned when there is no active Docker CLI context, other than the default one.
+var ErrDockerContextNotSet = errors.New("docker context not set")
+
+// DockerContext is a Docker CLI context, as created by "docker context create".
+type DockerContext struct {
+	// Name is the name of the context.
+	Name string
+
+	// Host is the address of the Docker daemon of the context.
+	Host string
+
+	// SkipTLSVerify disables the verification of the certificate of the Docker daemon.
+	SkipTLSVerify bool
+
+	// CAFile, CertFile and KeyFile are the paths of the TLS material of the context,
+	// which are empty if the context has none.
+	CAFile   string
+	CertFile string
+	KeyFile  string
+}
+
+// dockerContextMeta is the meta.json file of a Docker CLI context.
+type dockerContextMeta struct {
+	Name      string `json:"Name"`
+	Endpoints map[string]struct {
+		Host          string `json:"Host"`
+		SkipTLSVerify bool   `json:"SkipTLSVerify"`
+	} `json:"Endpoints"`
+}
+
+// dockerConfigDir returns the directory of the Docker CLI configuration, which is the
+// DOCKER_CONFIG environment variable, or ~/.docker by default.
+func dockerConfigDir() (string, error) {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("user home dir: %w", err)
+	}
+
+	return filepath.Join(home, ".docker"), nil
+}
+
+// dockerContextName returns the name of the active Docker CLI context: the DOCKER_CONTEXT
+// environment variable, or the current context of the Docker CLI configuration, as set by
+// "docker context use".
+func dockerContextName(configDir string) (string, error) {
+	if name := os.Getenv("DOCKER_CONTEXT"); name != "" {
+		return name, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(configDir, "config.json"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("read docker config: %w", err)
+	}
+
+	var cfg struct {
+		CurrentContext string `json:"currentContext"`
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return "", fmt.Errorf("decode docker config: %w", err)
+	}
+
+	return cfg.CurrentContext, nil
+}
+
+// CurrentDockerContext returns the active Docker CLI context, read from the contexts of the
+// Docker CLI configuration. It returns [ErrDockerContextNotSet] if the active context is the
+// default one, which uses the DOCKER_HOST environment variable or the default socket.
+func CurrentDockerContext() (*DockerContext, error) {
+	configDir, err := dockerConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := dockerContextName(configDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" || name == defaultDockerContext {
+		return nil, ErrDockerContextNotSet
+	}
+
+	// The contexts are stored in directories named after the digest of their name.
+	sum := sha256.Sum256([]byte(name))
+	id := hex.EncodeToString(sum[:])
+
+	data, err := os.ReadFile(filepath.Join(configDir, "contexts", "meta", id, "meta.json"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("docker context %q not found", name)
+		}
+		return nil, fmt.Errorf("read docker context %q: %w", name, err)
+	}
+
+	var meta dockerContextMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("decode docker context %q: %w", name, err)
+	}
+
+	endpoint, ok := meta.Endpoints["docker"]
+	if !ok || endpoint.Host == "" {
+		return nil, fmt.Errorf("docker context %q has no docker endpoint", name)
+	}
+
+	dc := &DockerContext{
+		Name:          name,
+		Host:          endpoint.Host,
+		SkipTLSVerify: endpoint.SkipTLSVerify,
+	}
+
+	tlsDir := filepath.Join(configDir, "contexts", "tls", id, "docker")
+	for path, file := range map[*string]string{&dc.CAFile: "ca.pem", &dc.CertFile: "cert.pem", &dc.KeyFile: "key.pem"} {
+		if p := filepath.Join(tlsDir, file); fileExists(p) {
+			*path = p
+		}
+	}
+
+	return dc, nil
+}
+
+// tlsConfig returns the TLS configuration of the context, which is nil if the context
+// has no TLS material and does not skip the TLS verification.
+func (dc *DockerContext) tlsConfig() (*tls.Config, error) {
+	if dc.CAFile == "" && dc.CertFile == "" && dc.KeyFile == "" && !dc.SkipTLSVerify {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: dc.SkipTLSVerify, //nolint:gosec // the context explicitly skips the verification
+	}
+
+	if dc.CAFile != "" {
+		ca, err := os.ReadFile(dc.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA of docker context %q: %w", dc.Name, err)
+		}
+
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("invalid CA of docker context %q", dc.Name)
+		}
+	}
+
+	if dc.CertFile != "" && dc.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(dc.CertFile, dc.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load certificate of docker context %q: %w", dc.Name, err)
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// ClientOpts returns the options of a Docker client connecting to the Docker daemon
+// of the context, with its TLS material.
+func (dc *DockerContext) ClientOpts() ([]client.Opt, error) {
+	cfg, err := dc.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []client.Opt
+	if cfg != nil {
+		// The HTTP client must be set before the host, which configures its transport.
+		opts = append(opts, client.WithHTTPClient(&http.Client{
+			Transport: &http.Transport{TLSClientConfig: cfg},
+		}))
+	}
+
+	return append(opts, client.WithHost(dc.Host)), nil
+}
+
+// dockerContextClientOpts returns the options of a Docker client connecting to the host,
+// with the TLS material of the active Docker CLI context, if the host is the one of the
+// context. Otherwise, it returns no options.
+func dockerContextClientOpts(host string) ([]client.Opt, error) {
+	dc, err := CurrentDockerContext()
+	if err != nil || dc.Host != host {
+		return nil, nil //nolint:nilerr // the host does not come from a Docker CLI context
+	}
+
+	return dc.ClientOpts()
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// setupDockerContext writes a Docker CLI context into the Docker CLI configuration directory
+// set by DOCKER_CONFIG, with a self-signed certificate as TLS material if withTLS is true.
+func setupDockerContext(t *testing.T, name, host string, withTLS bool) {
+	t.Helper()
+
+	configDir := os.Getenv("DOCKER_CONFIG")
+	if configDir == "" {
+		configDir = t.TempDir()
+		t.Setenv("DOCKER_CONFIG", configDir)
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	id := hex.EncodeToString(sum[:])
+
+	metaDir := filepath.Join(configDir, "contexts", "meta", id)
+	require.NoError(t, os.MkdirAll(metaDir, 0o755))
+	meta := `{"Name":"` + name + `","Metadata":{},"Endpoints":{"docker":{"Host":"` + host + `","SkipTLSVerify":false}}}`
+	require.NoError(t, os.WriteFile(filepath.Join(metaDir, "meta.json"), []byte(meta), 0o644))
+
+	if !withTLS {
+		return
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	tlsDir := filepath.Join(configDir, "contexts", "tls", id, "docker")
+	require.NoError(t, os.MkdirAll(tlsDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(tlsDir, "ca.pem"), cert, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(tlsDir, "cert.pem"), cert, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(tlsDir, "key.pem"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+}
+
+func TestCurrentDockerContext(t *testing.T) {
+	t.Run("not-set", func(t *testing.T) {
+		t.Setenv("DOCKER_CONFIG", t.TempDir())
+		t.Setenv("DOCKER_CONTEXT", "")
+
+		_, err := CurrentDockerContext()
+		require.ErrorIs(t, err, ErrDockerContextNotSet)
+
+		t.Setenv("DOCKER_CONTEXT", "default")
+		_, err = CurrentDockerContext()
+		require.ErrorIs(t, err, ErrDockerContextNotSet)
+	})
+
+	t.Run("current-context", func(t *testing.T) {
+		configDir := t.TempDir()
+		t.Setenv("DOCKER_CONFIG", configDir)
+		t.Setenv("DOCKER_CONTEXT", "")
+		setupDockerContext(t, "remote", "tcp://127.0.0.1:2376", false)
+		require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"currentContext":"remote"}`), 0o644))
+
+		dc, err := CurrentDockerContext()
+		require.NoError(t, err)
+		require.Equal(t, &DockerContext{Name: "remote", Host: "tcp://127.0.0.1:2376"}, dc)
+
+		opts, err := dc.ClientOpts()
+		require.NoError(t, err)
+		require.Len(t, opts, 1)
+	})
+
+	t.Run("tls", func(t *testing.T) {
+		t.Setenv("DOCKER_CONFIG", t.TempDir())
+		t.Setenv("DOCKER_CONTEXT", "secure")
+		setupDockerContext(t, "secure", "tcp://127.0.0.1:2376", true)
+
+		dc, err := CurrentDockerContext()
+		require.NoError(t, err)
+		require.NotEmpty(t, dc.CAFile)
+		require.NotEmpty(t, dc.CertFile)
+		require.NotEmpty(t, dc.KeyFile)
+
+		cfg, err := dc.tlsConfig()
+		require.NoError(t, err)
+		require.NotNil(t, cfg.RootCAs)
+		require.Len(t, cfg.Certificates, 1)
+
+		opts, err := dockerContextClientOpts("tcp://127.0.0.1:2376")
+		require.NoError(t, err)
+		require.Len(t, opts, 2)
+
+		// the host does not come from the context.
+		opts, err = dockerContextClientOpts("tcp://127.0.0.1:2375")
+		require.NoError(t, err)
+		require.Empty(t, opts)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		t.Setenv("DOCKER_CONFIG", t.TempDir())
+		t.Setenv("DOCKER_CONTEXT", "missing")
+
+		_, err := CurrentDockerContext()
+		require.EqualError(t, err, `docker context "missing" not found`)
+	})
+}
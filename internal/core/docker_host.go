@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
 	"sync"
 
@@ -31,9 +32,10 @@ var (
 )
 
 var (
-	dockerHostCache    string
-	dockerHostErrCache error
-	dockerHostOnce     sync.Once
+	dockerHostCache       string
+	dockerHostErrCache    error
+	dockerHostReportCache []DockerHostCandidate
+	dockerHostOnce        sync.Once
 )
 
 var (
@@ -60,7 +62,7 @@ func DefaultGatewayIP() (string, error) {
 // dockerHostCheck Use a vanilla Docker client to check if the Docker host is reachable.
 // It will avoid recursive calls to this function.
 var dockerHostCheck = func(ctx context.Context, host string) error {
-	cli, err := client.New(client.FromEnv, client.WithHost(host))
+	cli, err := NewClientWithHost(ctx, host)
 	if err != nil {
 		return fmt.Errorf("new client: %w", err)
 	}
@@ -81,10 +83,11 @@ var dockerHostCheck = func(ctx context.Context, host string) error {
 //  1. Docker host from the "tc.host" property in the ~/.testcontainers.properties file.
 //  2. DOCKER_HOST environment variable.
 //  3. Docker host from context.
-//  4. Docker host from the default docker socket path, without the unix schema.
-//  5. Docker host from the "docker.host" property in the ~/.testcontainers.properties file.
-//  6. Rootless docker socket path.
-//  7. Else, because the Docker host is not set, it panics.
+//  4. Docker host from the active Docker CLI context, set by the DOCKER_CONTEXT environment variable or "docker context use".
+//  5. Docker host from the default docker socket path, without the unix schema.
+//  6. Docker host from the "docker.host" property in the ~/.testcontainers.properties file.
+//  7. Rootless docker socket path.
+//  8. Else, because the Docker host is not set, it panics.
 func MustExtractDockerHost(ctx context.Context) string {
 	host, err := ExtractDockerHost(ctx)
 	if err != nil {
@@ -95,11 +98,72 @@ func MustExtractDockerHost(ctx context.Context) string {
 
 func ExtractDockerHost(ctx context.Context) (string, error) {
 	dockerHostOnce.Do(func() {
-		dockerHostCache, dockerHostErrCache = extractDockerHost(ctx)
+		dockerHostCache, dockerHostReportCache, dockerHostErrCache = discoverDockerHost(ctx)
 	})
 	return dockerHostCache, dockerHostErrCache
 }
 
+// DockerHostCandidate is a Docker host discovery strategy tried by [ExtractDockerHost].
+type DockerHostCandidate struct {
+	// Strategy is the name of the strategy, such as "DOCKER_HOST environment variable".
+	Strategy string
+
+	// Host is the Docker host found by the strategy, which is empty if it found none.
+	Host string
+
+	// Selected is true for the strategy whose Docker host is used.
+	Selected bool
+
+	// Err is the reason the strategy was skipped: its Docker host is not set, it is invalid,
+	// or it is not reachable. It is nil for the selected strategy.
+	Err error
+}
+
+// DiscoveryReport is the report of the discovery of the Docker host.
+type DiscoveryReport struct {
+	// Host is the discovered Docker host, which is empty if none was found.
+	Host string
+
+	// Candidates are the strategies tried, in order, until one found a reachable Docker host.
+	Candidates []DockerHostCandidate
+
+	// Err is the error of the discovery, if no Docker host was found.
+	Err error
+}
+
+// String returns a human-readable report, with a line per strategy tried.
+func (r DiscoveryReport) String() string {
+	var sb strings.Builder
+	for _, c := range r.Candidates {
+		switch {
+		case c.Selected:
+			fmt.Fprintf(&sb, "✅ %s: %s\n", c.Strategy, c.Host)
+		case c.Host != "":
+			fmt.Fprintf(&sb, "❌ %s: %s: %s\n", c.Strategy, c.Host, c.Err)
+		default:
+			fmt.Fprintf(&sb, "⏭️ %s: %s\n", c.Strategy, c.Err)
+		}
+	}
+
+	if r.Err != nil {
+		fmt.Fprintf(&sb, "no Docker host found: %s\n", r.Err)
+	}
+
+	return sb.String()
+}
+
+// ExtractDockerHostReport returns the report of the discovery of the Docker host done by
+// [ExtractDockerHost], which is done once.
+func ExtractDockerHostReport(ctx context.Context) DiscoveryReport {
+	host, err := ExtractDockerHost(ctx)
+
+	return DiscoveryReport{
+		Host:       host,
+		Candidates: slices.Clone(dockerHostReportCache),
+		Err:        err,
+	}
+}
+
 // MustExtractDockerSocket Extracts the docker socket from the different alternatives, removing the socket schema and
 // caching the result to avoid unnecessary calculations. Use this function to get the docker socket path,
 // not the host (e.g. mounting the socket in a container). This function does not consider Windows containers at the moment.
@@ -121,41 +185,61 @@ func MustExtractDockerSocket(ctx context.Context) string {
 	return dockerSocketPathCache
 }
 
+// dockerHostStrategy is a Docker host discovery strategy.
+type dockerHostStrategy struct {
+	name string
+	fn   func(context.Context) (string, error)
+}
+
+// dockerHostStrategies are the Docker host discovery strategies, in order.
+var dockerHostStrategies = []dockerHostStrategy{
+	{name: "tc.host property", fn: testcontainersHostFromProperties},
+	{name: "DOCKER_HOST environment variable", fn: dockerHostFromEnv},
+	{name: "Go context", fn: dockerHostFromContext},
+	{name: "Docker CLI context", fn: dockerHostFromDockerContext},
+	{name: "default Docker socket", fn: dockerSocketPath},
+	{name: "docker.host property", fn: dockerHostFromProperties},
+	{name: "rootless Docker socket", fn: rootlessDockerSocketPath},
+}
+
 // extractDockerHost Extracts the docker host from the different alternatives, without caching the result.
 // This internal method is handy for testing purposes.
 func extractDockerHost(ctx context.Context) (string, error) {
-	dockerHostFns := []func(context.Context) (string, error){
-		testcontainersHostFromProperties,
-		dockerHostFromEnv,
-		dockerHostFromContext,
-		dockerSocketPath,
-		dockerHostFromProperties,
-		rootlessDockerSocketPath,
-	}
+	host, _, err := discoverDockerHost(ctx)
+	return host, err
+}
 
+// discoverDockerHost Extracts the docker host from the different alternatives, without caching the result,
+// returning the strategies tried, in order.
+func discoverDockerHost(ctx context.Context) (string, []DockerHostCandidate, error) {
+	var candidates []DockerHostCandidate
 	var errs []error
-	for _, dockerHostFn := range dockerHostFns {
-		dockerHost, err := dockerHostFn(ctx)
+	for _, strategy := range dockerHostStrategies {
+		dockerHost, err := strategy.fn(ctx)
 		if err != nil {
 			if !isHostNotSet(err) {
 				errs = append(errs, err)
 			}
+			candidates = append(candidates, DockerHostCandidate{Strategy: strategy.name, Err: err})
 			continue
 		}
 
 		if err = dockerHostCheck(ctx, dockerHost); err != nil {
-			errs = append(errs, fmt.Errorf("check host %q: %w", dockerHost, err))
+			err = fmt.Errorf("check host %q: %w", dockerHost, err)
+			errs = append(errs, err)
+			candidates = append(candidates, DockerHostCandidate{Strategy: strategy.name, Host: dockerHost, Err: err})
 			continue
 		}
 
-		return dockerHost, nil
+		candidates = append(candidates, DockerHostCandidate{Strategy: strategy.name, Host: dockerHost, Selected: true})
+		return dockerHost, candidates, nil
 	}
 
 	if len(errs) > 0 {
-		return "", errors.Join(errs...)
+		return "", candidates, errors.Join(errs...)
 	}
 
-	return "", ErrSocketNotFound
+	return "", candidates, ErrSocketNotFound
 }
 
 // extractDockerSocket Extracts the docker socket from the different alternatives, without caching the result.
@@ -188,6 +272,11 @@ func extractDockerSocketFromClient(ctx context.Context, cli client.APIClient) st
 			return strings.Replace(socket, DockerSocketSchema, "", 1)
 		}
 
+		// the Docker socket in the Docker host reached through SSH
+		if IsSSHHost(socket) {
+			return SSHDockerSocketPath(socket)
+		}
+
 		return socket
 	}
 
@@ -230,6 +319,7 @@ func isHostNotSet(err error) bool {
 	case errors.Is(err, ErrTestcontainersHostNotSetInProperties),
 		errors.Is(err, ErrDockerHostNotSet),
 		errors.Is(err, ErrDockerSocketNotSetInContext),
+		errors.Is(err, ErrDockerContextNotSet),
 		errors.Is(err, ErrDockerSocketNotSetInProperties),
 		errors.Is(err, ErrSocketNotFoundInPath),
 		errors.Is(err, ErrXDGRuntimeDirNotSet),
@@ -265,6 +355,16 @@ func dockerHostFromContext(ctx context.Context) (string, error) {
 	return "", ErrDockerSocketNotSetInContext
 }
 
+// dockerHostFromDockerContext returns the docker host of the active Docker CLI context, if it's not the default one
+func dockerHostFromDockerContext(_ context.Context) (string, error) {
+	dc, err := CurrentDockerContext()
+	if err != nil {
+		return "", err
+	}
+
+	return dc.Host, nil
+}
+
 // dockerHostFromProperties returns the docker host from the ~/.testcontainers.properties file, if it's not empty
 func dockerHostFromProperties(_ context.Context) (string, error) {
 	cfg := config.Read()
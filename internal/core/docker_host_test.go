@@ -107,6 +107,54 @@ func TestExtractDockerHost(t *testing.T) {
 		require.Empty(t, host)
 	})
 
+	t.Run("Docker Host from Docker CLI context", func(t *testing.T) {
+		setupDockerContext(t, "remote", "tcp://127.0.0.1:2376", false)
+		t.Setenv("DOCKER_CONTEXT", "remote")
+
+		host, err := extractDockerHost(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "tcp://127.0.0.1:2376", host)
+
+		// DOCKER_HOST takes precedence.
+		t.Setenv("DOCKER_HOST", "/path/to/docker.sock")
+		host, err = extractDockerHost(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "/path/to/docker.sock", host)
+	})
+
+	t.Run("Discovery report", func(t *testing.T) {
+		t.Setenv("DOCKER_HOST", "tcp://127.0.0.1:1")
+		setupDockerContext(t, "remote", "tcp://127.0.0.1:2376", false)
+		t.Setenv("DOCKER_CONTEXT", "remote")
+		mockCallbackCheck(t, func(_ context.Context, host string) error {
+			if host == "tcp://127.0.0.1:1" {
+				return errors.New("connection refused")
+			}
+			return nil
+		})
+
+		host, candidates, err := discoverDockerHost(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "tcp://127.0.0.1:2376", host)
+		require.Len(t, candidates, 4)
+
+		require.Equal(t, "tc.host property", candidates[0].Strategy)
+		require.ErrorIs(t, candidates[0].Err, ErrTestcontainersHostNotSetInProperties)
+
+		require.Equal(t, "DOCKER_HOST environment variable", candidates[1].Strategy)
+		require.Equal(t, "tcp://127.0.0.1:1", candidates[1].Host)
+		require.False(t, candidates[1].Selected)
+		require.ErrorContains(t, candidates[1].Err, "connection refused")
+
+		require.ErrorIs(t, candidates[2].Err, ErrDockerSocketNotSetInContext)
+
+		require.Equal(t, DockerHostCandidate{Strategy: "Docker CLI context", Host: "tcp://127.0.0.1:2376", Selected: true}, candidates[3])
+
+		report := DiscoveryReport{Host: host, Candidates: candidates}
+		require.Contains(t, report.String(), "❌ DOCKER_HOST environment variable: tcp://127.0.0.1:1: check host \"tcp://127.0.0.1:1\": connection refused\n")
+		require.Contains(t, report.String(), "✅ Docker CLI context: tcp://127.0.0.1:2376\n")
+	})
+
 	t.Run("Docker Host as environment variable", func(t *testing.T) {
 		t.Setenv("DOCKER_HOST", "/path/to/docker.sock")
 		host, err := extractDockerHost(context.Background())
@@ -531,6 +579,8 @@ func createTmpDockerSocket(parent string) error {
 func setupDockerHostNotFound(t *testing.T) {
 	t.Helper()
 	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_CONTEXT", "")
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
 }
 
 func setupDockerSocket(t *testing.T) string {
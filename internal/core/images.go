@@ -50,6 +50,9 @@ func ExtractImagesFromReader(r io.Reader, buildArgs map[string]*string) ([]strin
 
 	images := make([]string, 0, len(lines))
 
+	// stages are the names of the build stages, which are not images when used in a FROM instruction.
+	stages := map[string]bool{}
+
 	// extract images from dockerfile
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -57,13 +60,25 @@ func ExtractImagesFromReader(r io.Reader, buildArgs map[string]*string) ([]strin
 			continue
 		}
 
-		// remove FROM
+		// remove FROM and the flags, such as --platform
 		line = strings.TrimPrefix(line, "FROM")
-		parts := strings.Split(strings.TrimSpace(line), " ")
+		parts := strings.Fields(line)
+		for len(parts) > 0 && strings.HasPrefix(parts[0], "--") {
+			parts = parts[1:]
+		}
 		if len(parts) == 0 {
 			continue
 		}
 
+		isStage := stages[strings.ToLower(parts[0])]
+		if len(parts) == 3 && strings.EqualFold(parts[1], "AS") {
+			stages[strings.ToLower(parts[2])] = true
+		}
+
+		if isStage {
+			continue
+		}
+
 		// interpolate build args
 		for k, v := range buildArgs {
 			if v != nil {
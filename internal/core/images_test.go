@@ -60,6 +60,12 @@ func TestExtractImagesFromDockerfile(t *testing.T) {
 			buildArgs:  map[string]*string{"BASE_IMAGE": &baseImage, "REGISTRY_HOST": &registryHost, "REGISTRY_PORT": &registryPort, "NGINX_IMAGE": &nginxImage},
 			expected:   []string{"nginx:latest", "localhost:5000/nginx:latest", "scratch"},
 		},
+		{
+			name:       "Multiple Images with stages and flags",
+			dockerfile: filepath.Join("testdata", "Dockerfile.multistage.stages"),
+			buildArgs:  nil,
+			expected:   []string{"golang:1.25", "alpine:3"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -31,6 +31,13 @@ const (
 
 	// LabelReap specifies the container should be reaped by the reaper.
 	LabelReap = LabelBase + ".reap"
+
+	// LabelBuildCache identifies the image as built with a content-addressed build cache tag.
+	LabelBuildCache = LabelBase + ".buildCache"
+
+	// LabelBuildCacheID identifies the build of an image built with a content-addressed build
+	// cache tag, independently of the content of its sources.
+	LabelBuildCacheID = LabelBase + ".buildCacheId"
 )
 
 // DefaultLabels returns the standard set of labels which
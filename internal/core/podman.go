@@ -0,0 +1,339 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/moby/moby/client"
+)
+
+// PodmanMachineName is the name of the default Podman machine.
+const PodmanMachineName = "podman-machine-default"
+
+var (
+	ErrContainerHostNotSet         = errors.New("CONTAINER_HOST is not set")
+	ErrPodmanNotFound              = errors.New("podman socket not found")
+	ErrPodmanNotFoundRunDir        = errors.New("checked paths: $XDG_RUNTIME_DIR/podman/podman.sock, /run/user/${uid}/podman/podman.sock")
+	ErrPodmanNotFoundRootfulRunDir = errors.New("checked path: /run/podman/podman.sock")
+	ErrPodmanMachineNotFound       = errors.New("checked paths: the API sockets of the " + PodmanMachineName + " machine")
+)
+
+// podmanHostStrategies are the Podman host discovery strategies, in order.
+var podmanHostStrategies = []dockerHostStrategy{
+	{name: "tc.host property", fn: testcontainersHostFromProperties},
+	{name: "DOCKER_HOST environment variable", fn: dockerHostFromEnv},
+	{name: "CONTAINER_HOST environment variable", fn: podmanHostFromEnv},
+	{name: "Go context", fn: dockerHostFromContext},
+	{name: "rootless Podman socket", fn: rootlessPodmanSocketPath},
+	{name: "rootful Podman socket", fn: rootfulPodmanSocketPath},
+	{name: "Podman machine socket", fn: podmanMachineSocketPath},
+}
+
+// ExtractPodmanHost extracts the host of the Podman service from the different alternatives.
+// The possible alternatives are:
+//
+//  1. Docker host from the "tc.host" property in the ~/.testcontainers.properties file.
+//  2. DOCKER_HOST environment variable.
+//  3. CONTAINER_HOST environment variable, used by the Podman CLI.
+//  4. Docker host from context.
+//  5. Rootless Podman socket: $XDG_RUNTIME_DIR/podman/podman.sock, or /run/user/${uid}/podman/podman.sock.
+//  6. Rootful Podman socket: /run/podman/podman.sock.
+//  7. API socket of the default Podman machine, on macOS and Windows.
+//  8. Else, because no Podman socket was found, it returns ErrPodmanNotFound.
+//
+// Unlike ExtractDockerHost, the result is not cached, as the sockets are only checked for existence.
+func ExtractPodmanHost(ctx context.Context) (string, error) {
+	errs := []error{ErrPodmanNotFound}
+	for _, s := range podmanHostStrategies {
+		host, err := s.fn(ctx)
+		if err != nil {
+			if !isHostNotSet(err) && !errors.Is(err, ErrContainerHostNotSet) {
+				errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+			}
+			continue
+		}
+
+		return host, nil
+	}
+
+	return "", errors.Join(errs...)
+}
+
+// podmanHostFromEnv returns the Podman host from the CONTAINER_HOST environment variable, if it's not empty.
+func podmanHostFromEnv(_ context.Context) (string, error) {
+	if host := os.Getenv("CONTAINER_HOST"); host != "" {
+		return host, nil
+	}
+
+	return "", ErrContainerHostNotSet
+}
+
+// rootlessPodmanSocketPath returns the rootless Podman socket of the current user, from
+// the XDG_RUNTIME_DIR environment variable, or the /run/user/${uid} directory otherwise.
+func rootlessPodmanSocketPath(_ context.Context) (string, error) {
+	if IsWindows() {
+		return "", ErrPodmanNotFoundRunDir
+	}
+
+	if xdgRuntimeDir := os.Getenv("XDG_RUNTIME_DIR"); xdgRuntimeDir != "" {
+		f := filepath.Join(xdgRuntimeDir, "podman", "podman.sock")
+		if fileExists(f) {
+			return DockerSocketSchema + f, nil
+		}
+	}
+
+	f := filepath.Join(baseRunDir, "user", strconv.Itoa(os.Getuid()), "podman", "podman.sock")
+	if fileExists(f) {
+		return DockerSocketSchema + f, nil
+	}
+
+	return "", ErrPodmanNotFoundRunDir
+}
+
+// rootfulPodmanSocketPath returns the rootful Podman socket, /run/podman/podman.sock.
+func rootfulPodmanSocketPath(_ context.Context) (string, error) {
+	f := filepath.Join(baseRunDir, "podman", "podman.sock")
+	if !IsWindows() && fileExists(f) {
+		return DockerSocketSchema + f, nil
+	}
+
+	return "", ErrPodmanNotFoundRootfulRunDir
+}
+
+// podmanMachineSocketPath returns the API socket of the default Podman machine, which forwards
+// the socket of the Podman service running in the virtual machine to the local host.
+func podmanMachineSocketPath(_ context.Context) (string, error) {
+	if IsWindows() {
+		pipe := `\\.\pipe\` + PodmanMachineName
+		if fileExists(pipe) {
+			return "npipe:////./pipe/" + PodmanMachineName, nil
+		}
+
+		return "", ErrPodmanMachineNotFound
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	candidates := []string{
+		// Podman 5 on macOS, and Linux, where XDG_RUNTIME_DIR is set.
+		filepath.Join(os.TempDir(), "podman", PodmanMachineName+"-api.sock"),
+		filepath.Join(os.Getenv("XDG_RUNTIME_DIR"), "podman", PodmanMachineName+"-api.sock"),
+		// Podman 4 on macOS.
+		filepath.Join(home, ".local", "share", "containers", "podman", "machine", PodmanMachineName, "podman.sock"),
+		filepath.Join(home, ".local", "share", "containers", "podman", "machine", "qemu", "podman.sock"),
+	}
+
+	for _, f := range candidates {
+		if filepath.IsAbs(f) && fileExists(f) {
+			return DockerSocketSchema + f, nil
+		}
+	}
+
+	return "", ErrPodmanMachineNotFound
+}
+
+// libpodAPIPath is the path prefix of the libpod API, supported since Podman 4.
+const libpodAPIPath = "/v4.0.0/libpod"
+
+// PodmanInfo is the information of a Podman service, from the libpod API.
+type PodmanInfo struct {
+	// Version is the version of Podman.
+	Version string
+
+	// Rootless is true if the Podman service runs rootless.
+	Rootless bool
+
+	// NetworkCmd is the rootless network of the containers, "pasta" or "slirp4netns",
+	// which publishes their ports in the network namespace of the user.
+	NetworkCmd string
+
+	// NetworkBackend is the network backend of the Podman service, "netavark" or "cni".
+	NetworkBackend string
+
+	// RemoteSocket is the socket of the Podman service in its host, which is the virtual
+	// machine of a Podman machine. The schema is removed.
+	RemoteSocket string
+}
+
+// PodmanPodSpec is the specification of a Podman pod.
+type PodmanPodSpec struct {
+	Name         string              `json:"name,omitempty"`
+	Labels       map[string]string   `json:"labels,omitempty"`
+	PortMappings []PodmanPortMapping `json:"portmappings,omitempty"`
+}
+
+// PodmanPortMapping is a port published by a Podman pod. A zero HostPort publishes
+// the port on a random port of the host.
+type PodmanPortMapping struct {
+	ContainerPort uint16 `json:"container_port"`
+	HostPort      uint16 `json:"host_port,omitempty"`
+	Protocol      string `json:"protocol,omitempty"`
+}
+
+// PodmanPod is the state of a Podman pod.
+type PodmanPod struct {
+	ID               string `json:"Id"`
+	Name             string `json:"Name"`
+	InfraContainerID string `json:"InfraContainerID"`
+}
+
+// PodmanClient is a client of the libpod API of a Podman service, which extends its
+// Docker compatible API with the Podman specific resources, such as the pods.
+type PodmanClient struct {
+	httpClient *http.Client
+}
+
+// NewPodmanClient returns a libpod API client connecting to the Podman service of the given
+// Docker client, with the same transport, so it works for all the supported Docker hosts.
+func NewPodmanClient(cli client.APIClient) *PodmanClient {
+	dial := cli.Dialer()
+	return &PodmanClient{
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return dial(ctx)
+				},
+			},
+		},
+	}
+}
+
+// Info returns the information of the Podman service.
+func (c *PodmanClient) Info(ctx context.Context) (PodmanInfo, error) {
+	var resp struct {
+		Host struct {
+			NetworkBackend     string `json:"networkBackend"`
+			RootlessNetworkCmd string `json:"rootlessNetworkCmd"`
+			RemoteSocket       struct {
+				Path string `json:"path"`
+			} `json:"remoteSocket"`
+			Security struct {
+				Rootless bool `json:"rootless"`
+			} `json:"security"`
+		} `json:"host"`
+		Version struct {
+			Version string `json:"Version"`
+		} `json:"version"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/info", nil, nil, &resp); err != nil {
+		return PodmanInfo{}, fmt.Errorf("podman info: %w", err)
+	}
+
+	info := PodmanInfo{
+		Version:        resp.Version.Version,
+		Rootless:       resp.Host.Security.Rootless,
+		NetworkBackend: resp.Host.NetworkBackend,
+		RemoteSocket:   strings.TrimPrefix(resp.Host.RemoteSocket.Path, DockerSocketSchema),
+	}
+	if info.Rootless {
+		info.NetworkCmd = resp.Host.RootlessNetworkCmd
+		if info.NetworkCmd == "" {
+			// Podman 4 does not report it, and uses slirp4netns by default.
+			info.NetworkCmd = "slirp4netns"
+		}
+	}
+
+	return info, nil
+}
+
+// CreatePod creates a pod, with an infra container holding its namespaces and publishing its ports.
+func (c *PodmanClient) CreatePod(ctx context.Context, spec PodmanPodSpec) (PodmanPod, error) {
+	var created struct {
+		ID string `json:"Id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/pods/create", nil, spec, &created); err != nil {
+		return PodmanPod{}, fmt.Errorf("create pod: %w", err)
+	}
+
+	return c.InspectPod(ctx, created.ID)
+}
+
+// InspectPod returns the state of the pod with the given name or ID.
+func (c *PodmanClient) InspectPod(ctx context.Context, nameOrID string) (PodmanPod, error) {
+	var pod PodmanPod
+	if err := c.do(ctx, http.MethodGet, "/pods/"+url.PathEscape(nameOrID)+"/json", nil, nil, &pod); err != nil {
+		return PodmanPod{}, fmt.Errorf("inspect pod: %w", err)
+	}
+
+	return pod, nil
+}
+
+// RemovePod removes the pod with the given name or ID, stopping and removing its containers.
+func (c *PodmanClient) RemovePod(ctx context.Context, nameOrID string) error {
+	query := url.Values{"force": []string{"true"}}
+	if err := c.do(ctx, http.MethodDelete, "/pods/"+url.PathEscape(nameOrID), query, nil, nil); err != nil {
+		return fmt.Errorf("remove pod: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the idle connections of the client.
+func (c *PodmanClient) Close() error {
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// do calls the libpod API, encoding the body and decoding the response as JSON.
+func (c *PodmanClient) do(ctx context.Context, method, path string, query url.Values, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("marshal: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	// The host is not used, as the client dials the Podman service itself.
+	u := url.URL{Scheme: "http", Host: "podman", Path: libpodAPIPath + path, RawQuery: query.Encode()}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(data, &apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(data))
+		}
+
+		return fmt.Errorf("%s %s: status %d: %s", method, path, resp.StatusCode, apiErr.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,225 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+)
+
+// setupPodmanNotFound sets up an environment without Podman sockets nor Docker hosts.
+func setupPodmanNotFound(t *testing.T) {
+	t.Helper()
+
+	setupDockerHostNotFound(t)
+	setupTestcontainersProperties(t, "")
+	t.Setenv("CONTAINER_HOST", "")
+	t.Setenv("TMPDIR", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	baseRunDir = t.TempDir()
+	t.Cleanup(func() {
+		baseRunDir = originalBaseRunDir
+	})
+}
+
+// createTmpPodmanSocket creates a fake Podman socket in the given directory.
+func createTmpPodmanSocket(t *testing.T, dir string) string {
+	t.Helper()
+
+	require.NoError(t, createTmpDir(dir))
+	socket := filepath.Join(dir, "podman.sock")
+	require.NoError(t, os.WriteFile(socket, nil, 0o600))
+
+	return socket
+}
+
+func TestExtractPodmanHost(t *testing.T) {
+	ctx := context.Background()
+
+	if IsWindows() {
+		t.Skip("Podman sockets are not supported on Windows")
+	}
+
+	t.Run("DOCKER_HOST", func(t *testing.T) {
+		setupPodmanNotFound(t)
+		t.Setenv("DOCKER_HOST", "tcp://podman.corp:8888")
+		t.Setenv("CONTAINER_HOST", "unix:///run/podman/podman.sock")
+
+		host, err := ExtractPodmanHost(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "tcp://podman.corp:8888", host)
+	})
+
+	t.Run("CONTAINER_HOST", func(t *testing.T) {
+		setupPodmanNotFound(t)
+		t.Setenv("CONTAINER_HOST", "unix:///run/podman/podman.sock")
+
+		host, err := ExtractPodmanHost(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "unix:///run/podman/podman.sock", host)
+	})
+
+	t.Run("rootless/XDG_RUNTIME_DIR", func(t *testing.T) {
+		setupPodmanNotFound(t)
+		xdgRuntimeDir := t.TempDir()
+		t.Setenv("XDG_RUNTIME_DIR", xdgRuntimeDir)
+		socket := createTmpPodmanSocket(t, filepath.Join(xdgRuntimeDir, "podman"))
+
+		// the rootful socket is also present, but the rootless one comes first.
+		createTmpPodmanSocket(t, filepath.Join(baseRunDir, "podman"))
+
+		host, err := ExtractPodmanHost(ctx)
+		require.NoError(t, err)
+		require.Equal(t, DockerSocketSchema+socket, host)
+	})
+
+	t.Run("rootless/run-dir", func(t *testing.T) {
+		setupPodmanNotFound(t)
+		socket := createTmpPodmanSocket(t, filepath.Join(baseRunDir, "user", strconv.Itoa(os.Getuid()), "podman"))
+
+		host, err := ExtractPodmanHost(ctx)
+		require.NoError(t, err)
+		require.Equal(t, DockerSocketSchema+socket, host)
+	})
+
+	t.Run("rootful", func(t *testing.T) {
+		setupPodmanNotFound(t)
+		socket := createTmpPodmanSocket(t, filepath.Join(baseRunDir, "podman"))
+
+		host, err := ExtractPodmanHost(ctx)
+		require.NoError(t, err)
+		require.Equal(t, DockerSocketSchema+socket, host)
+	})
+
+	t.Run("machine", func(t *testing.T) {
+		setupPodmanNotFound(t)
+		tmpDir := t.TempDir()
+		t.Setenv("TMPDIR", tmpDir)
+
+		socket := filepath.Join(tmpDir, "podman", PodmanMachineName+"-api.sock")
+		require.NoError(t, createTmpDir(filepath.Dir(socket)))
+		require.NoError(t, os.WriteFile(socket, nil, 0o600))
+
+		host, err := ExtractPodmanHost(ctx)
+		require.NoError(t, err)
+		require.Equal(t, DockerSocketSchema+socket, host)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		setupPodmanNotFound(t)
+
+		host, err := ExtractPodmanHost(ctx)
+		require.ErrorIs(t, err, ErrPodmanNotFound)
+		require.ErrorIs(t, err, ErrPodmanNotFoundRunDir)
+		require.ErrorIs(t, err, ErrPodmanNotFoundRootfulRunDir)
+		require.ErrorIs(t, err, ErrPodmanMachineNotFound)
+		require.Empty(t, host)
+	})
+}
+
+// startLibpodAPI serves a fake libpod API on a unix socket, returning the Docker client
+// connecting to it, and the requests it received.
+func startLibpodAPI(t *testing.T) (*client.Client, *[]string) {
+	t.Helper()
+
+	// unix socket paths are limited in length, so a short temporary directory is used.
+	dir, err := os.MkdirTemp("", "tc-podman")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	socket := filepath.Join(dir, "podman.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	var requests []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v4.0.0/libpod/info", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{
+			"host": {
+				"networkBackend": "netavark",
+				"rootlessNetworkCmd": "pasta",
+				"remoteSocket": {"path": "unix:///run/user/1000/podman/podman.sock", "exists": true},
+				"security": {"rootless": true}
+			},
+			"version": {"Version": "5.2.1"}
+		}`)
+	})
+	mux.HandleFunc("POST /v4.0.0/libpod/pods/create", func(w http.ResponseWriter, r *http.Request) {
+		var spec PodmanPodSpec
+		if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, "create "+spec.Name+" "+strconv.Itoa(int(spec.PortMappings[0].ContainerPort)))
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"Id": "pod-id"}`)
+	})
+	mux.HandleFunc("GET /v4.0.0/libpod/pods/{id}/json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"Id": "`+r.PathValue("id")+`", "Name": "web", "InfraContainerID": "infra-id"}`)
+	})
+	mux.HandleFunc("DELETE /v4.0.0/libpod/pods/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "pod-id" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"cause": "no such pod", "message": "no pod with name or ID `+r.PathValue("id")+` found: no such pod", "response": 404}`)
+			return
+		}
+		requests = append(requests, "remove "+r.PathValue("id")+" force="+r.URL.Query().Get("force"))
+		_, _ = io.WriteString(w, `{}`)
+	})
+
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(listener) //nolint:errcheck // the test fails anyway
+	t.Cleanup(func() { srv.Close() })
+
+	cli, err := NewClientWithHost(context.Background(), DockerSocketSchema+socket)
+	require.NoError(t, err)
+	t.Cleanup(func() { cli.Close() })
+
+	return cli, &requests
+}
+
+func TestPodmanClient(t *testing.T) {
+	ctx := context.Background()
+
+	if IsWindows() {
+		t.Skip("unix sockets are not supported on Windows")
+	}
+
+	cli, requests := startLibpodAPI(t)
+	pc := NewPodmanClient(cli)
+	defer pc.Close()
+
+	info, err := pc.Info(ctx)
+	require.NoError(t, err)
+	require.Equal(t, PodmanInfo{
+		Version:        "5.2.1",
+		Rootless:       true,
+		NetworkCmd:     "pasta",
+		NetworkBackend: "netavark",
+		RemoteSocket:   "/run/user/1000/podman/podman.sock",
+	}, info)
+
+	pod, err := pc.CreatePod(ctx, PodmanPodSpec{
+		Name:         "web",
+		PortMappings: []PodmanPortMapping{{ContainerPort: 80, Protocol: "tcp"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, PodmanPod{ID: "pod-id", Name: "web", InfraContainerID: "infra-id"}, pod)
+
+	require.NoError(t, pc.RemovePod(ctx, pod.ID))
+
+	err = pc.RemovePod(ctx, "unknown")
+	require.EqualError(t, err, "remove pod: DELETE /pods/unknown: status 404: no pod with name or ID unknown found: no such pod")
+
+	require.Equal(t, []string{"create web 80", "remove pod-id force=true"}, *requests)
+}
@@ -0,0 +1,37 @@
+package core
+
+import "sync"
+
+// terminationCallbacks are the functions called once a container is terminated,
+// by container ID and then by key, so the state kept for a container goes away with it.
+var terminationCallbacks = struct {
+	sync.Mutex
+	byContainer map[string]map[string]func()
+}{byContainer: map[string]map[string]func(){}}
+
+// OnContainerTerminated registers fn to be called once the container with the given ID is
+// terminated, replacing the function previously registered for the container with the same key.
+func OnContainerTerminated(containerID, key string, fn func()) {
+	terminationCallbacks.Lock()
+	defer terminationCallbacks.Unlock()
+
+	callbacks, ok := terminationCallbacks.byContainer[containerID]
+	if !ok {
+		callbacks = map[string]func(){}
+		terminationCallbacks.byContainer[containerID] = callbacks
+	}
+	callbacks[key] = fn
+}
+
+// ContainerTerminated calls and forgets the functions registered for the container with the
+// given ID, see [OnContainerTerminated].
+func ContainerTerminated(containerID string) {
+	terminationCallbacks.Lock()
+	callbacks := terminationCallbacks.byContainer[containerID]
+	delete(terminationCallbacks.byContainer, containerID)
+	terminationCallbacks.Unlock()
+
+	for _, fn := range callbacks {
+		fn()
+	}
+}
@@ -0,0 +1,24 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerTerminated(t *testing.T) {
+	var called []string
+	OnContainerTerminated("ctr-1", "a", func() { called = append(called, "a1") })
+	OnContainerTerminated("ctr-1", "a", func() { called = append(called, "a2") })
+	OnContainerTerminated("ctr-2", "b", func() { called = append(called, "b") })
+
+	ContainerTerminated("ctr-1")
+	require.Equal(t, []string{"a2"}, called)
+
+	// the callbacks are called once.
+	ContainerTerminated("ctr-1")
+	require.Equal(t, []string{"a2"}, called)
+
+	ContainerTerminated("ctr-2")
+	require.Equal(t, []string{"a2", "b"}, called)
+}
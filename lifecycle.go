@@ -464,6 +464,10 @@ func (c ContainerLifecycleHooks) Terminated(ctx context.Context) func(container
 }
 
 func (p *DockerProvider) preCreateContainerHook(ctx context.Context, req ContainerRequest, dockerInput *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig) error {
+	if err := p.checkImagePolicy(ctx, &req, dockerInput.Image); err != nil {
+		return fmt.Errorf("image policy: %w", err)
+	}
+
 	var mountErrors []error
 	for _, m := range req.Mounts {
 		// validate only the mount sources that implement the Validator interface
@@ -515,6 +519,14 @@ func (p *DockerProvider) preCreateContainerHook(ctx context.Context, req Contain
 	}
 	req.HostConfigModifier(hostConfig)
 
+	if err := p.resolveRuntime(ctx, req, hostConfig); err != nil {
+		return fmt.Errorf("resolve runtime: %w", err)
+	}
+
+	if err := p.validateResources(ctx, hostConfig); err != nil {
+		return fmt.Errorf("validate resources: %w", err)
+	}
+
 	if req.EndpointSettingsModifier != nil {
 		req.EndpointSettingsModifier(endpointSettings)
 	}
@@ -542,6 +554,12 @@ func (p *DockerProvider) preCreateContainerHook(ctx context.Context, req Contain
 		return err
 	}
 
+	if hostConfig.NetworkMode.IsContainer() {
+		// The ports are published by the container whose network namespace is shared, such as the
+		// infra container of a pod, as the daemon rejects exposing ports in a container network mode.
+		exposedPortSet = nil
+	}
+
 	dockerInput.ExposedPorts = exposedPortSet
 	hostConfig.PortBindings = mergePortBindings(hostConfig.PortBindings, exposedPortSet)
 	return nil
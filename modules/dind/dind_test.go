@@ -13,6 +13,8 @@ import (
 )
 
 func Test_LoadImages(t *testing.T) {
+	testcontainers.SkipUnless(t, testcontainers.CapabilityRootful)
+
 	// Give up to three minutes to run this test
 	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(3*time.Minute))
 	defer cancel()
@@ -21,6 +21,8 @@ import (
 )
 
 func Test_LoadImages(t *testing.T) {
+	testcontainers.SkipUnless(t, testcontainers.CapabilityRootful)
+
 	// Give up to three minutes to run this test
 	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(3*time.Minute))
 	defer cancel()
@@ -103,6 +105,8 @@ func Test_LoadImages(t *testing.T) {
 }
 
 func Test_LoadImagesWithPlatform(t *testing.T) {
+	testcontainers.SkipUnless(t, testcontainers.CapabilityRootful)
+
 	// Give up to three minutes to run this test
 	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(3*time.Minute))
 	defer cancel()
@@ -227,6 +231,8 @@ func getTestPodState(ctx context.Context, k8s *kubernetes.Clientset) (corev1.Con
 }
 
 func Test_APIServerReady(t *testing.T) {
+	testcontainers.SkipUnless(t, testcontainers.CapabilityRootful)
+
 	ctx := context.Background()
 
 	k3sContainer, err := k3s.Run(ctx, "rancher/k3s:v1.27.1-k3s1")
@@ -265,6 +271,8 @@ func Test_APIServerReady(t *testing.T) {
 }
 
 func Test_WithManifestOption(t *testing.T) {
+	testcontainers.SkipUnless(t, testcontainers.CapabilityRootful)
+
 	ctx := context.Background()
 
 	k3sContainer, err := k3s.Run(ctx,
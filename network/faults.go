@@ -0,0 +1,377 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+	"github.com/testcontainers/testcontainers-go/internal/core"
+)
+
+const (
+	// netshootImage {
+	// netshootImage is the image used by the sidecar containers that inject faults
+	// in the network namespace of the target containers. It ships iptables and tc.
+	netshootImage = "nicolaka/netshoot:v0.13"
+	// }
+
+	// netAdminCapability is the capability needed to modify the network namespace.
+	netAdminCapability = "NET_ADMIN"
+)
+
+// disconnected stores the endpoint settings of the containers disconnected with Disconnect,
+// keyed by network ID and container ID, so that Reconnect is able to restore them. The settings
+// of a container are forgotten once it is terminated.
+var disconnected sync.Map
+
+// disconnectedKey returns the key used to store the endpoint settings of a disconnected container.
+func disconnectedKey(nw *testcontainers.DockerNetwork, ctr testcontainers.Container) string {
+	return nw.ID + "/" + ctr.GetContainerID()
+}
+
+// Disconnect detaches a running container from the network, simulating a total loss of
+// connectivity on that network. The endpoint settings of the container, including its
+// network aliases, are kept so that Reconnect can restore them.
+func Disconnect(ctx context.Context, nw *testcontainers.DockerNetwork, ctr testcontainers.Container) error {
+	inspect, err := ctr.Inspect(ctx)
+	if err != nil {
+		return fmt.Errorf("inspect container: %w", err)
+	}
+
+	endpoint, ok := inspect.NetworkSettings.Networks[nw.Name]
+	if !ok {
+		return fmt.Errorf("container %s is not attached to network %s", ctr.GetContainerID(), nw.Name)
+	}
+
+	if err := nw.Disconnect(ctx, ctr); err != nil {
+		return err
+	}
+
+	key := disconnectedKey(nw, ctr)
+	disconnected.Store(key, endpoint)
+	core.OnContainerTerminated(ctr.GetContainerID(), key, func() {
+		disconnected.Delete(key)
+	})
+
+	return nil
+}
+
+// Reconnect attaches a container previously detached with Disconnect to the network again,
+// restoring its network aliases. The container receives a new IP address on the network.
+func Reconnect(ctx context.Context, nw *testcontainers.DockerNetwork, ctr testcontainers.Container) error {
+	var aliases []string
+	if v, ok := disconnected.Load(disconnectedKey(nw, ctr)); ok {
+		if endpoint, ok := v.(*network.EndpointSettings); ok && endpoint != nil {
+			aliases = endpoint.Aliases
+		}
+	}
+
+	if err := nw.Connect(ctx, ctr, aliases...); err != nil {
+		return err
+	}
+
+	disconnected.Delete(disconnectedKey(nw, ctr))
+
+	return nil
+}
+
+// Fault represents a network fault injected in the network namespace of one or more containers.
+// The fault stays in place until Heal is called or the affected containers are removed.
+type Fault struct {
+	heals []faultHeal
+}
+
+// faultHeal is the script that reverts a fault in the network namespace of a container.
+type faultHeal struct {
+	ctr    testcontainers.Container
+	script string
+}
+
+// Heal reverts the fault, restoring the connectivity between the affected containers.
+func (f *Fault) Heal(ctx context.Context) error {
+	var errs []error
+	for _, h := range f.heals {
+		if _, err := runInNetNS(ctx, h.ctr, h.script); err != nil {
+			errs = append(errs, fmt.Errorf("heal %s: %w", h.ctr.GetContainerID(), err))
+		}
+	}
+
+	f.heals = nil
+
+	return errors.Join(errs...)
+}
+
+// Partition cuts the connectivity on the network between every container in groupA and every
+// container in groupB, in both directions, while keeping the containers attached to the network.
+// Containers in the same group keep talking to each other. Call Heal on the returned fault to
+// restore the connectivity.
+func Partition(ctx context.Context, nw *testcontainers.DockerNetwork, groupA, groupB []testcontainers.Container) (*Fault, error) {
+	if len(groupA) == 0 || len(groupB) == 0 {
+		return nil, errors.New("partition needs at least one container on each side")
+	}
+
+	ipsB := make([]netip.Addr, 0, len(groupB))
+	for _, ctr := range groupB {
+		ips, err := containerIPs(ctx, nw, ctr)
+		if err != nil {
+			return nil, err
+		}
+		ipsB = append(ipsB, ips...)
+	}
+
+	fault := &Fault{}
+
+	// Dropping the traffic from and to group B in the namespaces of group A
+	// is enough to isolate both groups, in both directions.
+	for _, ctr := range groupA {
+		if _, err := runInNetNS(ctx, ctr, partitionScript("-I", ipsB)); err != nil {
+			return nil, errors.Join(fmt.Errorf("partition %s: %w", ctr.GetContainerID(), err), fault.Heal(ctx))
+		}
+
+		fault.heals = append(fault.heals, faultHeal{ctr: ctr, script: partitionScript("-D", ipsB)})
+	}
+
+	return fault, nil
+}
+
+// partitionScript returns the iptables commands that insert or delete, depending on the action,
+// the rules dropping the traffic from and to the given IPs.
+func partitionScript(action string, ips []netip.Addr) string {
+	cmds := []string{"set -e"}
+	for _, ip := range ips {
+		bin := "iptables"
+		if ip.Is6() {
+			bin = "ip6tables"
+		}
+
+		cmds = append(cmds,
+			fmt.Sprintf("%s %s INPUT -s %s -j DROP", bin, action, ip),
+			fmt.Sprintf("%s %s OUTPUT -d %s -j DROP", bin, action, ip),
+		)
+	}
+
+	return strings.Join(cmds, "\n")
+}
+
+// linkFault holds the characteristics of a degraded link.
+type linkFault struct {
+	latency time.Duration
+	jitter  time.Duration
+	loss    float64
+}
+
+// LinkOption is a type that can be used to configure the characteristics of a degraded link.
+type LinkOption func(*linkFault) error
+
+// WithLatency adds the given latency to the packets sent on the link,
+// varying randomly by up to the given jitter. Use a zero jitter for a constant latency.
+func WithLatency(latency, jitter time.Duration) LinkOption {
+	return func(lf *linkFault) error {
+		if latency < 0 || jitter < 0 {
+			return errors.New("latency and jitter must not be negative")
+		}
+
+		lf.latency = latency
+		lf.jitter = jitter
+
+		return nil
+	}
+}
+
+// WithPacketLoss drops the given percentage, from 0 to 100, of the packets sent on the link.
+func WithPacketLoss(percent float64) LinkOption {
+	return func(lf *linkFault) error {
+		if percent < 0 || percent > 100 {
+			return fmt.Errorf("packet loss must be between 0 and 100, got %v", percent)
+		}
+
+		lf.loss = percent
+
+		return nil
+	}
+}
+
+// netemArgs returns the arguments of the tc netem qdisc implementing the link fault.
+func (lf linkFault) netemArgs() (string, error) {
+	var args []string
+	if lf.latency > 0 {
+		args = append(args, "delay", strconv.FormatInt(lf.latency.Microseconds(), 10)+"us")
+		if lf.jitter > 0 {
+			args = append(args, strconv.FormatInt(lf.jitter.Microseconds(), 10)+"us")
+		}
+	}
+
+	if lf.loss > 0 {
+		args = append(args, "loss", strconv.FormatFloat(lf.loss, 'f', -1, 64)+"%")
+	}
+
+	if len(args) == 0 {
+		return "", errors.New("no latency nor packet loss configured for the link")
+	}
+
+	return strings.Join(args, " "), nil
+}
+
+// DegradeLink degrades the packets sent on the network by the from container to the to container,
+// using tc netem in the network namespace of the from container. The link is unidirectional, so call
+// it twice with the containers swapped to degrade both directions. Call Heal on the returned fault to
+// restore the link.
+func DegradeLink(ctx context.Context, nw *testcontainers.DockerNetwork, from, to testcontainers.Container, opts ...LinkOption) (*Fault, error) {
+	var lf linkFault
+	for _, opt := range opts {
+		if err := opt(&lf); err != nil {
+			return nil, err
+		}
+	}
+
+	netem, err := lf.netemArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	fromIPs, err := containerIPs(ctx, nw, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toIPs, err := containerIPs(ctx, nw, to)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := runInNetNS(ctx, from, degradeScript(fromIPs[0], toIPs, netem))
+	if err != nil {
+		return nil, fmt.Errorf("degrade link: %w", err)
+	}
+
+	// The script prints the filter priority allocated to the link, used to remove it later.
+	prio, err := strconv.Atoi(strings.TrimSpace(out))
+	if err != nil {
+		return nil, fmt.Errorf("parse link priority %q: %w", out, err)
+	}
+
+	return &Fault{
+		heals: []faultHeal{{ctr: from, script: healLinkScript(fromIPs[0], prio)}},
+	}, nil
+}
+
+// interfaceScript returns the shell command that stores in the IFACE variable the name of the
+// interface holding the given IP address.
+func interfaceScript(ip netip.Addr) string {
+	return fmt.Sprintf(`IFACE=$(ip -o addr show | awk '$4 ~ "^%s/" { sub(/@.*/, "", $2); print $2; exit }')
+[ -n "$IFACE" ] || { echo "no interface found for %s" >&2; exit 1; }`, ip, ip)
+}
+
+// degradeScript returns the tc commands that route the packets sent to the given IPs
+// through a dedicated htb class with a netem qdisc, and print the filter priority used.
+// The rest of the traffic flows through the default class, unaffected. The priority is
+// allocated after the highest class already present, so that several links can coexist.
+func degradeScript(src netip.Addr, dst []netip.Addr, netem string) string {
+	cmds := []string{
+		"set -e",
+		interfaceScript(src),
+		`tc qdisc show dev "$IFACE" | grep -q "qdisc htb 1: root" || tc qdisc replace dev "$IFACE" root handle 1: htb default 1`,
+		`tc class show dev "$IFACE" | grep -q "class htb 1:1 " || tc class add dev "$IFACE" parent 1: classid 1:1 htb rate 10gbit`,
+		`PRIO=100`,
+		`for c in $(tc class show dev "$IFACE" | awk '{ split($3, id, ":"); print id[2] }'); do [ $((0x$c)) -lt "$PRIO" ] || PRIO=$((0x$c + 1)); done`,
+		`CLASS=$(printf '%x' "$PRIO")`,
+		`tc class add dev "$IFACE" parent 1: classid "1:$CLASS" htb rate 10gbit`,
+		fmt.Sprintf(`tc qdisc add dev "$IFACE" parent "1:$CLASS" handle "$CLASS:" netem %s`, netem),
+	}
+
+	for _, ip := range dst {
+		if ip.Is6() {
+			cmds = append(cmds, fmt.Sprintf(`tc filter add dev "$IFACE" protocol ipv6 parent 1: prio "$PRIO" u32 match ip6 dst %s/128 flowid "1:$CLASS"`, ip))
+			continue
+		}
+		cmds = append(cmds, fmt.Sprintf(`tc filter add dev "$IFACE" protocol ip parent 1: prio "$PRIO" u32 match ip dst %s/32 flowid "1:$CLASS"`, ip))
+	}
+
+	cmds = append(cmds, `echo "$PRIO"`)
+
+	return strings.Join(cmds, "\n")
+}
+
+// healLinkScript returns the tc commands removing the link degraded with the given filter priority.
+func healLinkScript(src netip.Addr, prio int) string {
+	return strings.Join([]string{
+		"set -e",
+		interfaceScript(src),
+		fmt.Sprintf(`tc filter del dev "$IFACE" parent 1: prio %d`, prio),
+		fmt.Sprintf(`tc qdisc del dev "$IFACE" parent 1:%x`, prio),
+		fmt.Sprintf(`tc class del dev "$IFACE" classid 1:%x`, prio),
+	}, "\n")
+}
+
+// containerIPs returns the IP addresses, IPv4 first, of the container on the given network.
+func containerIPs(ctx context.Context, nw *testcontainers.DockerNetwork, ctr testcontainers.Container) ([]netip.Addr, error) {
+	inspect, err := ctr.Inspect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("inspect container: %w", err)
+	}
+
+	endpoint, ok := inspect.NetworkSettings.Networks[nw.Name]
+	if !ok {
+		return nil, fmt.Errorf("container %s is not attached to network %s", ctr.GetContainerID(), nw.Name)
+	}
+
+	var ips []netip.Addr
+	if endpoint.IPAddress.IsValid() {
+		ips = append(ips, endpoint.IPAddress)
+	}
+
+	if endpoint.GlobalIPv6Address.IsValid() {
+		ips = append(ips, endpoint.GlobalIPv6Address)
+	}
+
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("container %s has no IP address on network %s", ctr.GetContainerID(), nw.Name)
+	}
+
+	return ips, nil
+}
+
+// runInNetNS runs the shell script in the network namespace of the container, using a
+// short-lived sidecar container with the NET_ADMIN capability, returning its output.
+func runInNetNS(ctx context.Context, ctr testcontainers.Container, script string) (out string, err error) {
+	sidecar, err := testcontainers.Run(ctx, netshootImage,
+		testcontainers.WithCmd("sleep", "infinity"),
+		testcontainers.WithHostConfigModifier(func(hc *container.HostConfig) {
+			hc.NetworkMode = container.NetworkMode("container:" + ctr.GetContainerID())
+			hc.CapAdd = append(hc.CapAdd, netAdminCapability)
+		}),
+	)
+	defer func() {
+		err = errors.Join(err, testcontainers.TerminateContainer(sidecar))
+	}()
+	if err != nil {
+		return "", fmt.Errorf("run sidecar: %w", err)
+	}
+
+	code, reader, err := sidecar.Exec(ctx, []string{"sh", "-c", script}, tcexec.Multiplexed())
+	if err != nil {
+		return "", fmt.Errorf("exec: %w", err)
+	}
+
+	output, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("read output: %w", err)
+	}
+
+	if code != 0 {
+		return "", fmt.Errorf("exit code %d: %s", code, strings.TrimSpace(string(output)))
+	}
+
+	return string(output), nil
+}
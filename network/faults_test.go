@@ -0,0 +1,145 @@
+package network_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+	"github.com/testcontainers/testcontainers-go/network"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// runNginxInNetwork runs an nginx container attached to the network with the given alias.
+func runNginxInNetwork(t *testing.T, nw *testcontainers.DockerNetwork, alias string) testcontainers.Container {
+	t.Helper()
+
+	ctr, err := testcontainers.Run(context.Background(), nginxAlpineImage,
+		testcontainers.WithExposedPorts(nginxDefaultPort),
+		network.WithNetwork([]string{alias}, nw),
+		testcontainers.WithWaitStrategy(wait.ForListeningPort(nginxDefaultPort)),
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	return ctr
+}
+
+// canReach returns true if the container is able to fetch the nginx index page of the given host.
+func canReach(t *testing.T, ctr testcontainers.Container, host string) bool {
+	t.Helper()
+
+	code, reader, err := ctr.Exec(context.Background(), []string{"wget", "-q", "-T", "2", "-O", "/dev/null", "http://" + host}, tcexec.Multiplexed())
+	require.NoError(t, err)
+
+	_, err = io.ReadAll(reader)
+	require.NoError(t, err)
+
+	return code == 0
+}
+
+func TestPartition(t *testing.T) {
+	ctx := context.Background()
+
+	nw, err := network.New(ctx)
+	require.NoError(t, err)
+	testcontainers.CleanupNetwork(t, nw)
+
+	a1 := runNginxInNetwork(t, nw, "a1")
+	a2 := runNginxInNetwork(t, nw, "a2")
+	b1 := runNginxInNetwork(t, nw, "b1")
+
+	require.True(t, canReach(t, a1, "b1"))
+
+	// partition {
+	fault, err := network.Partition(ctx, nw, []testcontainers.Container{a1, a2}, []testcontainers.Container{b1})
+	require.NoError(t, err)
+	// }
+
+	require.False(t, canReach(t, a1, "b1"))
+	require.False(t, canReach(t, b1, "a2"))
+	require.True(t, canReach(t, a1, "a2"))
+
+	require.NoError(t, fault.Heal(ctx))
+
+	require.True(t, canReach(t, a1, "b1"))
+	require.True(t, canReach(t, b1, "a2"))
+}
+
+func TestPartition_emptyGroup(t *testing.T) {
+	_, err := network.Partition(context.Background(), &testcontainers.DockerNetwork{}, nil, nil)
+	require.Error(t, err)
+}
+
+func TestDisconnectReconnect(t *testing.T) {
+	ctx := context.Background()
+
+	nw, err := network.New(ctx)
+	require.NoError(t, err)
+	testcontainers.CleanupNetwork(t, nw)
+
+	client := runNginxInNetwork(t, nw, "client")
+	server := runNginxInNetwork(t, nw, "server")
+
+	require.True(t, canReach(t, client, "server"))
+
+	require.NoError(t, network.Disconnect(ctx, nw, server))
+	require.False(t, canReach(t, client, "server"))
+
+	require.NoError(t, network.Reconnect(ctx, nw, server))
+	require.True(t, canReach(t, client, "server"))
+
+	aliases, err := server.NetworkAliases(ctx)
+	require.NoError(t, err)
+	require.Contains(t, aliases[nw.Name], "server")
+}
+
+func TestDegradeLink(t *testing.T) {
+	ctx := context.Background()
+
+	nw, err := network.New(ctx)
+	require.NoError(t, err)
+	testcontainers.CleanupNetwork(t, nw)
+
+	client := runNginxInNetwork(t, nw, "client")
+	server := runNginxInNetwork(t, nw, "server")
+
+	elapsed := func() time.Duration {
+		start := time.Now()
+		require.True(t, canReach(t, client, "server"))
+		return time.Since(start)
+	}
+
+	fault, err := network.DegradeLink(ctx, nw, client, server, network.WithLatency(500*time.Millisecond, 0))
+	require.NoError(t, err)
+
+	// The TCP handshake and the HTTP request cross the degraded link at least twice.
+	require.GreaterOrEqual(t, elapsed(), time.Second)
+
+	require.NoError(t, fault.Heal(ctx))
+	require.Less(t, elapsed(), time.Second)
+}
+
+func TestDegradeLink_invalidOptions(t *testing.T) {
+	ctx := context.Background()
+	nw := &testcontainers.DockerNetwork{}
+
+	t.Run("no-options", func(t *testing.T) {
+		_, err := network.DegradeLink(ctx, nw, nil, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("packet-loss-out-of-range", func(t *testing.T) {
+		_, err := network.DegradeLink(ctx, nw, nil, nil, network.WithPacketLoss(150))
+		require.Error(t, err)
+	})
+
+	t.Run("negative-latency", func(t *testing.T) {
+		_, err := network.DegradeLink(ctx, nw, nil, nil, network.WithLatency(-time.Second, 0))
+		require.Error(t, err)
+	})
+}
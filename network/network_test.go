@@ -129,6 +129,55 @@ func TestContainerIPs(t *testing.T) {
 	require.Len(t, ips, 2)
 }
 
+func TestContainerIPv6s(t *testing.T) {
+	ctx := context.Background()
+
+	// ipv6Network {
+	newNetwork, err := network.New(ctx,
+		network.WithEnableIPv6(),
+		network.WithIPAM(&dockernetwork.IPAM{
+			Config: []dockernetwork.IPAMConfig{
+				{Subnet: netip.MustParsePrefix("fd00:dead:beef::/64")},
+			},
+		}),
+	)
+	// }
+	if err != nil {
+		t.Skipf("IPv6 networks not supported by the Docker daemon: %s", err)
+	}
+	testcontainers.CleanupNetwork(t, newNetwork)
+
+	nginx, err := testcontainers.Run(ctx, nginxAlpineImage,
+		testcontainers.WithExposedPorts(nginxDefaultPort),
+		network.WithNetwork([]string{"nginx"}, newNetwork),
+		testcontainers.WithPreferIPv6(),
+		testcontainers.WithWaitStrategy(wait.ForListeningPort(nginxDefaultPort)),
+	)
+	testcontainers.CleanupContainer(t, nginx)
+	require.NoError(t, err)
+
+	ipv4s, err := nginx.ContainerIPv4s(ctx)
+	require.NoError(t, err)
+	require.Len(t, ipv4s, 1)
+
+	ipv6s, err := nginx.ContainerIPv6s(ctx)
+	require.NoError(t, err)
+	require.Len(t, ipv6s, 1)
+
+	ip, err := netip.ParseAddr(ipv6s[0])
+	require.NoError(t, err)
+	require.True(t, netip.MustParsePrefix("fd00:dead:beef::/64").Contains(ip))
+
+	endpoint, err := nginx.PortEndpoint(ctx, nginxDefaultPort, "http")
+	require.NoError(t, err)
+
+	host, err := nginx.Host(ctx)
+	require.NoError(t, err)
+	if host == "::1" {
+		require.Contains(t, endpoint, "http://[::1]:")
+	}
+}
+
 func TestContainerWithReaperNetwork(t *testing.T) {
 	if core.IsWindows() {
 		t.Skip("Skip for Windows. See https://stackoverflow.com/questions/43784916/docker-for-windows-networking-container-with-multiple-network-interfaces")
@@ -459,3 +508,46 @@ func TestContainerWithNetworkModeAndNetworkTogether(t *testing.T) {
 		t.Logf("Can't use Network and NetworkMode together, %s\n", err)
 	}
 }
+
+func TestDockerNetwork_ConnectDisconnect(t *testing.T) {
+	ctx := context.Background()
+
+	nw, err := network.New(ctx)
+	require.NoError(t, err)
+	testcontainers.CleanupNetwork(t, nw)
+
+	nginx, err := testcontainers.Run(ctx, nginxAlpineImage,
+		testcontainers.WithExposedPorts(nginxDefaultPort),
+	)
+	testcontainers.CleanupContainer(t, nginx)
+	require.NoError(t, err)
+
+	// connectNetwork {
+	err = nw.Connect(ctx, nginx, "late-joiner")
+	// }
+	require.NoError(t, err)
+
+	networks, err := nginx.Networks(ctx)
+	require.NoError(t, err)
+	require.Contains(t, networks, nw.Name)
+
+	aliases, err := nginx.NetworkAliases(ctx)
+	require.NoError(t, err)
+	require.Contains(t, aliases[nw.Name], "late-joiner")
+
+	// disconnectNetwork {
+	err = nw.Disconnect(ctx, nginx)
+	// }
+	require.NoError(t, err)
+
+	networks, err = nginx.Networks(ctx)
+	require.NoError(t, err)
+	require.NotContains(t, networks, nw.Name)
+}
+
+func TestDockerNetwork_ConnectBridgeWithAliases(t *testing.T) {
+	nw := &testcontainers.DockerNetwork{Name: "bridge"}
+
+	err := nw.Connect(context.Background(), nil, "alias")
+	require.Error(t, err)
+}
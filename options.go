@@ -52,6 +52,23 @@ func WithDockerfile(df FromDockerfile) CustomizeRequestOption {
 	}
 }
 
+// WithBuildCache tags the image built from the Dockerfile with the content hash of the build,
+// which covers the files of the build context, the Dockerfile, the build args, the target and
+// the platforms, and skips the build when the image already exists locally. The image is kept
+// after the container is terminated, so the next runs reuse it.
+// The build options set by [FromDockerfile.BuildOptionsModifier] are part of the hash,
+// but the modifier itself is not, so it must produce the same options for the same build.
+func WithBuildCache() CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if !req.ShouldBuildImage() {
+			return errors.New("build cache requires a Dockerfile build, set it after WithDockerfile")
+		}
+
+		req.BuildCache = true
+		return nil
+	}
+}
+
 // WithConfigModifier allows to override the default container config
 // New in v0.43.1: The method can be called multiple times and the method will run sequentially with the last call
 // being called last.
@@ -134,6 +151,17 @@ func WithHostPortAccess(ports ...int) CustomizeRequestOption {
 	}
 }
 
+// WithSharedHostAccess allows the container to reach the host ports exposed at any time,
+// even after the container has started, with [ExposeHostPort]. All the containers using
+// this option share the same session-wide SSHD container, which is terminated once they are
+// all terminated and no host port is exposed anymore.
+func WithSharedHostAccess() CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		req.SharedHostAccess = true
+		return nil
+	}
+}
+
 // WithName will set the name of the container.
 func WithName(containerName string) CustomizeRequestOption {
 	return func(req *GenericContainerRequest) error {
@@ -455,6 +483,77 @@ func WithAlwaysPull() CustomizeRequestOption {
 	}
 }
 
+// WithPullProgressConsumer sets the consumer of the progress events of the pull of the image,
+// which is useful to report the progress of slow pulls.
+func WithPullProgressConsumer(consumer PullProgressConsumer) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		req.PullProgressConsumer = consumer
+		return nil
+	}
+}
+
+// WithImageLock sets the image lock recording and enforcing the digest of the image of the container,
+// overriding the image lock of the configuration.
+func WithImageLock(lock *ImageLock) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if lock == nil {
+			return errors.New("image lock cannot be nil")
+		}
+
+		req.ImageLock = lock
+		return nil
+	}
+}
+
+// WithProviderPool creates the container in one of the Docker hosts of the pool, chosen
+// by its scheduler, instead of the Docker host of the provider type.
+func WithProviderPool(pool *ProviderPool) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if pool == nil {
+			return errors.New("provider pool cannot be nil")
+		}
+
+		req.Pool = pool
+		return nil
+	}
+}
+
+// WithImagePolicy sets the policy the image of the container must satisfy, checked right before
+// creating the container, overriding the image policy of the configuration.
+func WithImagePolicy(policy *ImagePolicy) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if policy == nil {
+			return errors.New("image policy cannot be nil")
+		}
+
+		req.ImagePolicy = policy
+		return nil
+	}
+}
+
+// WithPullPolicy sets the policy deciding whether to pull the image before starting the container,
+// overriding both [WithAlwaysPull] and the pull policy of the configuration.
+func WithPullPolicy(policy PullPolicy) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if policy == nil {
+			return errors.New("pull policy cannot be nil")
+		}
+
+		req.ImagePullPolicy = policy
+		return nil
+	}
+}
+
+// WithPreferIPv6 makes the container prefer its IPv6 port bindings in [DockerContainer.MappedPort],
+// and the IPv6 loopback address in [DockerContainer.Host] when the Docker daemon is local,
+// which is needed for services that listen only on IPv6.
+func WithPreferIPv6() CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		req.PreferIPv6 = true
+		return nil
+	}
+}
+
 // WithImagePlatform sets the platform for a container
 func WithImagePlatform(platform string) CustomizeRequestOption {
 	return func(req *GenericContainerRequest) error {
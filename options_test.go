@@ -3,6 +3,7 @@ package testcontainers_test
 import (
 	"context"
 	"io"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -249,6 +250,31 @@ func TestWithHostPortAccess(t *testing.T) {
 	})
 }
 
+func TestWithSharedHostAccess(t *testing.T) {
+	req := &testcontainers.GenericContainerRequest{}
+
+	opt := testcontainers.WithSharedHostAccess()
+	require.NoError(t, opt.Customize(req))
+	require.True(t, req.SharedHostAccess)
+}
+
+func TestWithBuildCache(t *testing.T) {
+	t.Run("with-dockerfile", func(t *testing.T) {
+		req := &testcontainers.GenericContainerRequest{}
+
+		require.NoError(t, testcontainers.WithDockerfile(testcontainers.FromDockerfile{Context: "testdata"}).Customize(req))
+		require.NoError(t, testcontainers.WithBuildCache().Customize(req))
+		require.True(t, req.BuildCache)
+		require.True(t, req.ShouldKeepBuiltImage())
+	})
+
+	t.Run("without-dockerfile", func(t *testing.T) {
+		req := &testcontainers.GenericContainerRequest{}
+
+		require.Error(t, testcontainers.WithBuildCache().Customize(req))
+	})
+}
+
 func TestWithEntrypoint(t *testing.T) {
 	testEntrypoint := func(t *testing.T, initial []string, add []string, expected []string) {
 		t.Helper()
@@ -385,6 +411,77 @@ func TestWithAlwaysPull(t *testing.T) {
 	require.True(t, req.AlwaysPullImage)
 }
 
+type noopPullProgressConsumer struct{}
+
+func (noopPullProgressConsumer) Accept(testcontainers.PullProgress) {}
+
+func TestWithPullProgressConsumer(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image: "alpine",
+		},
+	}
+
+	opt := testcontainers.WithPullProgressConsumer(noopPullProgressConsumer{})
+	require.NoError(t, opt.Customize(&req))
+	require.Equal(t, noopPullProgressConsumer{}, req.PullProgressConsumer)
+}
+
+func TestWithImageLock(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image: "alpine",
+		},
+	}
+
+	lock, err := testcontainers.NewImageLock(filepath.Join(t.TempDir(), "testcontainers.lock"), testcontainers.ImageLockVerify)
+	require.NoError(t, err)
+
+	opt := testcontainers.WithImageLock(lock)
+	require.NoError(t, opt.Customize(&req))
+	require.Same(t, lock, req.ImageLock)
+
+	require.EqualError(t, testcontainers.WithImageLock(nil).Customize(&req), "image lock cannot be nil")
+}
+
+func TestWithImagePolicy(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image: "alpine",
+		},
+	}
+
+	policy := &testcontainers.ImagePolicy{AllowedRegistries: []string{"mirror.corp"}}
+	opt := testcontainers.WithImagePolicy(policy)
+	require.NoError(t, opt.Customize(&req))
+	require.Same(t, policy, req.ImagePolicy)
+
+	require.EqualError(t, testcontainers.WithImagePolicy(nil).Customize(&req), "image policy cannot be nil")
+}
+
+func TestWithPullPolicy(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image: "alpine",
+		},
+	}
+
+	policy := testcontainers.PullMaxAge(time.Hour)
+	opt := testcontainers.WithPullPolicy(policy)
+	require.NoError(t, opt.Customize(&req))
+	require.Equal(t, policy, req.ImagePullPolicy)
+
+	require.EqualError(t, testcontainers.WithPullPolicy(nil).Customize(&req), "pull policy cannot be nil")
+}
+
+func TestWithPreferIPv6(t *testing.T) {
+	req := &testcontainers.GenericContainerRequest{}
+
+	opt := testcontainers.WithPreferIPv6()
+	require.NoError(t, opt.Customize(req))
+	require.True(t, req.PreferIPv6)
+}
+
 func TestWithImagePlatform(t *testing.T) {
 	req := testcontainers.GenericContainerRequest{
 		ContainerRequest: testcontainers.ContainerRequest{
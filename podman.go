@@ -0,0 +1,304 @@
+package testcontainers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+
+	"github.com/testcontainers/testcontainers-go/internal/config"
+	"github.com/testcontainers/testcontainers-go/internal/core"
+	"github.com/testcontainers/testcontainers-go/log"
+)
+
+// PodmanInfo is the information of a Podman service.
+type PodmanInfo = core.PodmanInfo
+
+// podmanContainersHost is the host of the containers' host, added by Podman to the
+// /etc/hosts file of the containers.
+const podmanContainersHost = "host.containers.internal"
+
+var (
+	// podmanInfos are the information of the Podman services, by Podman host.
+	podmanInfos   = map[string]PodmanInfo{}
+	podmanInfosMx sync.Mutex
+
+	// podmanSpawners are the reaper spawners of the Podman hosts other than the
+	// Docker host extracted from the different alternatives, by Podman host.
+	podmanSpawners   = map[string]*reaperSpawner{}
+	podmanSpawnersMx sync.Mutex
+)
+
+// PodmanProvider is a provider of containers running in a Podman service, through its Docker
+// compatible API, extended with the Podman specific features, such as the pods.
+type PodmanProvider struct {
+	*DockerProvider
+}
+
+// NewPodmanProvider creates a Podman provider for the Podman service found by the Podman host
+// discovery: the explicitly configured Docker hosts first, then the Podman sockets.
+func NewPodmanProvider(provOpts ...DockerProviderOption) (*PodmanProvider, error) {
+	provider, err := newPodmanDockerProvider(provOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PodmanProvider{DockerProvider: provider}, nil
+}
+
+// newPodmanDockerProvider creates the Docker provider of the Podman service found by the Podman
+// host discovery, which is the one returned by [ProviderPodman], see [DockerProvider.Podman].
+func newPodmanDockerProvider(provOpts ...DockerProviderOption) (*DockerProvider, error) {
+	o := &DockerProviderOptions{
+		GenericProviderOptions: &GenericProviderOptions{
+			Logger: log.Default(),
+		},
+		defaultBridgeNetworkName: Podman,
+	}
+
+	for idx := range provOpts {
+		provOpts[idx].ApplyDockerTo(o)
+	}
+
+	ctx := context.Background()
+	host, err := core.ExtractPodmanHost(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cli, err := core.NewClientWithHost(ctx, host)
+	if err != nil {
+		return nil, fmt.Errorf("new client: %w", err)
+	}
+
+	provider := &DockerProvider{
+		DockerProviderOptions: o,
+		client: &DockerClient{
+			Client: cli,
+			config: config.Read(),
+		},
+		host:   host,
+		config: config.Read(),
+		podman: true,
+	}
+	provider.spawner = podmanSpawner(ctx, provider)
+
+	return provider, nil
+}
+
+// Podman returns the Podman provider of the provider, giving access to the Podman specific
+// features, such as the pods, and true, if the provider runs the containers in a Podman service,
+// such as the provider of [ProviderPodman].
+func (p *DockerProvider) Podman() (*PodmanProvider, bool) {
+	if !p.podman {
+		return nil, false
+	}
+
+	return &PodmanProvider{DockerProvider: p}, true
+}
+
+// podmanSpawner returns the reaper spawner of the Podman host of the provider: the default one if
+// it's the Docker host extracted from the different alternatives, or the one of the Podman host otherwise.
+func podmanSpawner(ctx context.Context, p *DockerProvider) *reaperSpawner {
+	if host, err := core.ExtractDockerHost(ctx); err == nil && host == p.host {
+		return nil
+	}
+
+	podmanSpawnersMx.Lock()
+	defer podmanSpawnersMx.Unlock()
+
+	s, ok := podmanSpawners[p.host]
+	if !ok {
+		s = &reaperSpawner{provider: p}
+		podmanSpawners[p.host] = s
+	}
+
+	return s
+}
+
+// Info returns the information of the Podman service, which is retrieved once.
+func (p *PodmanProvider) Info(ctx context.Context) (PodmanInfo, error) {
+	return p.podmanInfo(ctx)
+}
+
+// podmanInfo returns the information of the Podman service of the provider, which is retrieved once per Podman host.
+func (p *DockerProvider) podmanInfo(ctx context.Context) (PodmanInfo, error) {
+	podmanInfosMx.Lock()
+	defer podmanInfosMx.Unlock()
+
+	if info, ok := podmanInfos[p.host]; ok {
+		return info, nil
+	}
+
+	pc := core.NewPodmanClient(p.client)
+	defer pc.Close()
+
+	info, err := pc.Info(ctx)
+	if err != nil {
+		return PodmanInfo{}, err
+	}
+
+	podmanInfos[p.host] = info
+
+	return info, nil
+}
+
+// podmanSocket returns the Podman socket mounted by the reaper: the socket of the Podman service in
+// its host, which differs from the local one for a Podman machine, or the local one if it is unknown.
+func (p *DockerProvider) podmanSocket(ctx context.Context) string {
+	if info, err := p.podmanInfo(ctx); err == nil && info.RemoteSocket != "" {
+		return info.RemoteSocket
+	}
+
+	if u, err := url.Parse(p.host); err == nil && u.Scheme == "unix" {
+		return u.Path
+	}
+
+	return core.DockerSocketPath
+}
+
+// podmanHostLocked returns the host of the ports published by a local Podman service, and true,
+// if they are not published like the Docker ones: the rootless Podman and the Podman machines
+// publish them in the network namespace of the user, through slirp4netns, pasta or gvproxy,
+// so the gateway of the container networks does not reach them.
+//
+//   - from a container, they are reached through host.containers.internal, which Podman
+//     adds to the /etc/hosts file of the containers.
+//   - pasta publishes them on the IPv4 and IPv6 loopbacks, so "localhost" reaches them.
+//   - slirp4netns and gvproxy only publish them on the IPv4 loopback, so "127.0.0.1" is
+//     used, as "localhost" may resolve to the IPv6 loopback first.
+func (p *DockerProvider) podmanHostLocked(ctx context.Context, daemonURL *url.URL) (string, bool, error) {
+	info, err := p.podmanInfo(ctx)
+	if err != nil {
+		return "", false, err
+	}
+
+	machine := info.RemoteSocket != "" && daemonURL.Scheme == "unix" && daemonURL.Path != info.RemoteSocket
+	if !info.Rootless && !machine && daemonURL.Scheme != "npipe" {
+		return "", false, nil
+	}
+
+	switch {
+	case core.InAContainer():
+		return podmanContainersHost, true, nil
+	case info.NetworkCmd == "pasta" && !machine:
+		return "localhost", true, nil
+	default:
+		return "127.0.0.1", true, nil
+	}
+}
+
+// PodRequest represents the parameters of a Podman pod.
+type PodRequest struct {
+	// Name is the name of the pod, which is generated by Podman if empty.
+	Name string
+
+	// ExposedPorts are the ports of the containers of the pod published on random
+	// ports of the host, e.g. "80/tcp". The containers of the pod cannot publish ports.
+	ExposedPorts []string
+
+	// Labels are the labels of the pod.
+	Labels map[string]string
+}
+
+// Pod is a Podman pod: a group of containers sharing the network namespace of the infra container
+// of the pod, which publishes the ports of the pod, so they reach each other through localhost.
+type Pod struct {
+	ID      string
+	Name    string
+	InfraID string // the ID of the infra container of the pod
+
+	provider *PodmanProvider
+}
+
+// CreatePod creates a Podman pod, to run containers in with the WithPod option.
+func (p *PodmanProvider) CreatePod(ctx context.Context, req PodRequest) (*Pod, error) {
+	exposedPorts, err := parseExposedPorts(req.ExposedPorts)
+	if err != nil {
+		return nil, fmt.Errorf("exposed ports: %w", err)
+	}
+
+	spec := core.PodmanPodSpec{
+		Name:   req.Name,
+		Labels: core.DefaultLabels(SessionID()),
+	}
+	for k, v := range req.Labels {
+		spec.Labels[k] = v
+	}
+	for port := range exposedPorts {
+		spec.PortMappings = append(spec.PortMappings, core.PodmanPortMapping{
+			ContainerPort: port.Num(),
+			Protocol:      string(port.Proto()),
+		})
+	}
+
+	pc := core.NewPodmanClient(p.client)
+	defer pc.Close()
+
+	pod, err := pc.CreatePod(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Pod{
+		ID:       pod.ID,
+		Name:     pod.Name,
+		InfraID:  pod.InfraContainerID,
+		provider: p,
+	}, nil
+}
+
+// Host returns the host of the ports published by the pod.
+func (pod *Pod) Host(ctx context.Context) (string, error) {
+	return pod.provider.DaemonHost(ctx)
+}
+
+// MappedPort returns the host port the given port of the containers of the pod is published on.
+func (pod *Pod) MappedPort(ctx context.Context, port string) (network.Port, error) {
+	infra := &DockerContainer{ID: pod.InfraID, provider: pod.provider.DockerProvider}
+
+	return infra.MappedPort(ctx, port)
+}
+
+// Terminate removes the pod, with its infra container. The containers of the pod must
+// be terminated first.
+func (pod *Pod) Terminate(ctx context.Context) error {
+	pc := core.NewPodmanClient(pod.provider.client)
+	defer pc.Close()
+
+	if err := pc.RemovePod(ctx, pod.ID); err != nil {
+		if strings.Contains(err.Error(), "no such pod") {
+			return errdefs.ErrNotFound.WithMessage(err.Error())
+		}
+		return err
+	}
+
+	return nil
+}
+
+// WithPod runs the container in the given Podman pod, sharing the network namespace of
+// the pod: the container reaches the other containers of the pod through localhost,
+// and its ports are published by the pod, so its exposed ports must be exposed by the pod.
+func WithPod(pod *Pod) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if pod == nil {
+			return errors.New("pod cannot be nil")
+		}
+
+		if req.Pool != nil {
+			return errors.New("pod cannot be used with a provider pool")
+		}
+
+		req.ProviderType = ProviderPodman
+
+		return WithHostConfigModifier(func(hc *container.HostConfig) {
+			hc.NetworkMode = container.NetworkMode("container:" + pod.InfraID)
+		})(req)
+	}
+}
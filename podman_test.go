@@ -0,0 +1,227 @@
+package testcontainers
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/internal/core"
+	"github.com/testcontainers/testcontainers-go/log"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// skipIfNotPodman skips the test if the tests do not run against a Podman service.
+func skipIfNotPodman(t *testing.T) {
+	t.Helper()
+
+	if providerType != ProviderPodman {
+		t.Skip("Podman is not the container runtime")
+	}
+}
+
+func TestPodmanProvider_host(t *testing.T) {
+	ctx := context.Background()
+
+	const host = "unix:///run/user/1000/podman/podman.sock"
+
+	tests := []struct {
+		name     string
+		host     string
+		info     PodmanInfo
+		want     string
+		override bool
+	}{
+		{
+			name: "rootless/pasta",
+			host: host,
+			info: PodmanInfo{Rootless: true, NetworkCmd: "pasta", RemoteSocket: "/run/user/1000/podman/podman.sock"},
+			want: "localhost",
+		},
+		{
+			name: "rootless/slirp4netns",
+			host: host,
+			info: PodmanInfo{Rootless: true, NetworkCmd: "slirp4netns", RemoteSocket: "/run/user/1000/podman/podman.sock"},
+			want: "127.0.0.1",
+		},
+		{
+			name: "machine",
+			host: "unix:///tmp/podman/podman-machine-default-api.sock",
+			info: PodmanInfo{RemoteSocket: "/run/podman/podman.sock"},
+			want: "127.0.0.1",
+		},
+		{
+			// the rootful Podman publishes the ports like Docker.
+			name:     "rootful",
+			host:     "unix:///run/podman/podman.sock",
+			info:     PodmanInfo{RemoteSocket: "/run/podman/podman.sock"},
+			override: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podmanInfos[tt.host] = tt.info
+			t.Cleanup(func() {
+				podmanInfosMx.Lock()
+				defer podmanInfosMx.Unlock()
+				delete(podmanInfos, tt.host)
+			})
+
+			daemonURL, err := url.Parse(tt.host)
+			require.NoError(t, err)
+
+			p := &DockerProvider{host: tt.host, podman: true}
+			got, ok, err := p.podmanHostLocked(ctx, daemonURL)
+			require.NoError(t, err)
+			require.Equal(t, !tt.override, ok)
+
+			want := tt.want
+			if ok && core.InAContainer() {
+				// the ports are reached through the host of the containers' host.
+				want = podmanContainersHost
+			}
+			require.Equal(t, want, got)
+		})
+	}
+}
+
+func TestPodmanProvider_hostWithoutLibpod(t *testing.T) {
+	// a Docker compatible daemon without the libpod API.
+	dir, err := os.MkdirTemp("", "tc-podman")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	listener, err := net.Listen("unix", filepath.Join(dir, "docker.sock"))
+	require.NoError(t, err)
+
+	var libpodRequests atomic.Int32
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/libpod/") {
+			libpodRequests.Add(1)
+		}
+		http.NotFound(w, r)
+	})}
+	go srv.Serve(listener) //nolint:errcheck // the test fails anyway
+	t.Cleanup(func() { srv.Close() })
+
+	orig := core.DockerEnvFile
+	core.DockerEnvFile = filepath.Join(dir, ".dockerenv")
+	t.Cleanup(func() { core.DockerEnvFile = orig })
+
+	host := "unix://" + listener.Addr().String()
+	cli, err := client.New(client.WithHost(host))
+	require.NoError(t, err)
+
+	p := &DockerProvider{
+		DockerProviderOptions: &DockerProviderOptions{
+			GenericProviderOptions: &GenericProviderOptions{Logger: log.TestLogger(t)},
+		},
+		client: &DockerClient{Client: cli},
+		host:   host,
+		podman: true,
+	}
+
+	for range 2 {
+		got, err := p.DaemonHost(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "localhost", got)
+	}
+
+	// the inferred host is cached, so the Podman information is not requested again.
+	require.Equal(t, int32(1), libpodRequests.Load())
+}
+
+func TestWithPod(t *testing.T) {
+	pod := &Pod{ID: "pod-id", InfraID: "infra-id"}
+
+	req := GenericContainerRequest{}
+	require.NoError(t, WithPod(pod).Customize(&req))
+	require.Equal(t, ProviderPodman, req.ProviderType)
+
+	hc := &container.HostConfig{}
+	req.HostConfigModifier(hc)
+	require.Equal(t, container.NetworkMode("container:infra-id"), hc.NetworkMode)
+
+	require.EqualError(t, WithPod(nil).Customize(&req), "pod cannot be nil")
+}
+
+func TestPodmanProvider_Info(t *testing.T) {
+	skipIfNotPodman(t)
+
+	ctx := context.Background()
+	provider, err := NewPodmanProvider()
+	require.NoError(t, err)
+	defer provider.Close()
+
+	info, err := provider.Info(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, info.Version)
+	require.NotEmpty(t, info.RemoteSocket)
+	if info.Rootless {
+		require.Contains(t, []string{"pasta", "slirp4netns"}, info.NetworkCmd)
+	}
+}
+
+func TestPodmanPod(t *testing.T) {
+	skipIfNotPodman(t)
+
+	ctx := context.Background()
+	provider, err := NewPodmanProvider()
+	require.NoError(t, err)
+	defer provider.Close()
+
+	pod, err := provider.CreatePod(ctx, PodRequest{ExposedPorts: []string{nginxDefaultPort}})
+	CleanupPod(t, pod)
+	require.NoError(t, err)
+
+	nginx, err := Run(ctx, nginxAlpineImage,
+		WithPod(pod),
+		WithExposedPorts(nginxDefaultPort),
+		WithWaitStrategy(wait.ForListeningPort(nginxDefaultPort)),
+	)
+	CleanupContainer(t, nginx)
+	require.NoError(t, err)
+
+	// the containers of the pod reach each other through localhost.
+	client, err := Run(ctx, nginxAlpineImage,
+		WithPod(pod),
+		WithCmd("wget", "-q", "-O", "/dev/null", "http://localhost:80"),
+		WithWaitStrategy(wait.ForExit()),
+	)
+	CleanupContainer(t, client)
+	require.NoError(t, err)
+
+	state, err := client.State(ctx)
+	require.NoError(t, err)
+	require.Zero(t, state.ExitCode)
+
+	// the ports of the containers are the ports published by the pod.
+	podPort, err := pod.MappedPort(ctx, nginxDefaultPort)
+	require.NoError(t, err)
+
+	port, err := nginx.MappedPort(ctx, nginxDefaultPort)
+	require.NoError(t, err)
+	require.Equal(t, podPort, port)
+
+	endpoint, err := nginx.PortEndpoint(ctx, nginxDefaultPort, "http")
+	require.NoError(t, err)
+
+	resp, err := http.Get(endpoint)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	_, err = io.Copy(io.Discard, resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
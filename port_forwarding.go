@@ -12,8 +12,12 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/system"
+	"github.com/moby/moby/client"
+	"github.com/moby/moby/client/pkg/versions"
 	"golang.org/x/crypto/ssh"
 
+	"github.com/testcontainers/testcontainers-go/internal/config"
 	"github.com/testcontainers/testcontainers-go/internal/core/network"
 	"github.com/testcontainers/testcontainers-go/wait"
 )
@@ -28,6 +32,12 @@ const (
 	HostInternal string = "host.testcontainers.internal"
 	user         string = "root"
 	sshPort             = "22/tcp"
+
+	// hostGateway is the special value of an extra host resolving to the host-gateway IP of the daemon.
+	hostGateway string = "host-gateway"
+
+	// hostGatewayMinVersion is the first Docker Engine version resolving the host-gateway extra host.
+	hostGatewayMinVersion string = "20.10"
 )
 
 // sshPassword is a random password generated for the SSHD container.
@@ -46,14 +56,7 @@ func exposeHostPorts(ctx context.Context, req *ContainerRequest, ports ...int) (
 	}
 
 	// Use the first network of the container to connect to the SSHD container.
-	var sshdFirstNetwork string
-	if len(req.Networks) > 0 {
-		sshdFirstNetwork = req.Networks[0]
-	}
-
-	if sshdFirstNetwork == "bridge" && len(req.Networks) > 1 {
-		sshdFirstNetwork = req.Networks[1]
-	}
+	sshdFirstNetwork := hostAccessNetwork(req)
 
 	opts := []ContainerCustomizer{}
 	if len(req.Networks) > 0 {
@@ -89,6 +92,11 @@ func exposeHostPorts(ctx context.Context, req *ContainerRequest, ports ...int) (
 		opts = append(opts, withNetwork([]string{HostInternal}, &dockerNw))
 	}
 
+	if req.Runtime != "" {
+		// the SSHD container is sandboxed like the container.
+		opts = append(opts, WithRuntime(req.Runtime))
+	}
+
 	// start the SSHD container with the provided options
 	sshdContainer, err := newSshdContainer(ctx, opts...)
 	// Ensure the SSHD container is stopped and removed in case of error.
@@ -107,42 +115,12 @@ func exposeHostPorts(ctx context.Context, req *ContainerRequest, ports ...int) (
 		return sshdConnectHook, fmt.Errorf("inspect sshd container: %w", err)
 	}
 
-	var sshdIP string
-	single := len(inspect.NetworkSettings.Networks) == 1
-	for name, nw := range inspect.NetworkSettings.Networks {
-		if name == sshdFirstNetwork || single {
-			if nw.IPAddress.IsValid() {
-				sshdIP = nw.IPAddress.String()
-				break
-			}
-		}
-	}
-
+	sshdIP := networkIP(inspect, sshdFirstNetwork)
 	if sshdIP == "" {
 		return sshdConnectHook, errors.New("sshd container IP not found")
 	}
 
-	if req.HostConfigModifier == nil {
-		req.HostConfigModifier = func(_ *container.HostConfig) {}
-	}
-
-	// do not override the original HostConfigModifier
-	originalHCM := req.HostConfigModifier
-	req.HostConfigModifier = func(hostConfig *container.HostConfig) {
-		// adding the host internal alias to the container as an extra host
-		// to allow the container to reach the SSHD container.
-		hostConfig.ExtraHosts = append(hostConfig.ExtraHosts, fmt.Sprintf("%s:%s", HostInternal, sshdIP))
-
-		modes := []container.NetworkMode{container.NetworkMode(sshdFirstNetwork), "none", "host"}
-		// if the container is not in one of the modes, attach it to the first network of the SSHD container
-		found := slices.Contains(modes, hostConfig.NetworkMode)
-		if !found {
-			req.Networks = append(req.Networks, sshdFirstNetwork)
-		}
-
-		// invoke the original HostConfigModifier with the updated hostConfig
-		originalHCM(hostConfig)
-	}
+	withHostInternal(req, sshdFirstNetwork, sshdIP)
 
 	stopHooks := []ContainerHook{
 		func(ctx context.Context, _ Container) error {
@@ -173,6 +151,112 @@ func exposeHostPorts(ctx context.Context, req *ContainerRequest, ports ...int) (
 	return sshdConnectHook, nil
 }
 
+// hostAccessNetwork returns the network used by the container in the request to reach the SSHD container,
+// which is the first network of the request, skipping the "bridge" network if there are more.
+// It returns an empty string if the request does not define any network.
+func hostAccessNetwork(req *ContainerRequest) string {
+	var networkName string
+	if len(req.Networks) > 0 {
+		networkName = req.Networks[0]
+	}
+
+	if networkName == Bridge && len(req.Networks) > 1 {
+		networkName = req.Networks[1]
+	}
+
+	return networkName
+}
+
+// networkIP returns the IP address of the container in the given network,
+// or in its only network if the container is attached to just one.
+func networkIP(inspect *container.InspectResponse, networkName string) string {
+	single := len(inspect.NetworkSettings.Networks) == 1
+	for name, nw := range inspect.NetworkSettings.Networks {
+		if name == networkName || single {
+			if nw.IPAddress.IsValid() {
+				return nw.IPAddress.String()
+			}
+		}
+	}
+
+	return ""
+}
+
+// withHostInternal updates the request so that the [HostInternal] hostname resolves to the given IP
+// from the container, attaching the container to the given network if it's not already attached.
+func withHostInternal(req *ContainerRequest, networkName string, ip string) {
+	if req.HostConfigModifier == nil {
+		req.HostConfigModifier = func(_ *container.HostConfig) {}
+	}
+
+	// do not override the original HostConfigModifier
+	originalHCM := req.HostConfigModifier
+	req.HostConfigModifier = func(hostConfig *container.HostConfig) {
+		// adding the host internal alias to the container as an extra host
+		// to allow the container to reach the SSHD container.
+		hostConfig.ExtraHosts = append(hostConfig.ExtraHosts, fmt.Sprintf("%s:%s", HostInternal, ip))
+
+		modes := []container.NetworkMode{container.NetworkMode(networkName), "none", "host"}
+		// if the container is not in one of the modes, attach it to the first network of the SSHD container
+		found := slices.Contains(modes, hostConfig.NetworkMode)
+		if !found {
+			req.Networks = append(req.Networks, networkName)
+		}
+
+		// invoke the original HostConfigModifier with the updated hostConfig
+		originalHCM(hostConfig)
+	}
+}
+
+// useHostGateway returns true if the containers reach the host ports through the host-gateway
+// extra host, instead of the SSHD container, according to the configured host access mode,
+// which defaults to "auto": the host-gateway when the daemon supports it, the SSHD container otherwise.
+func (p *DockerProvider) useHostGateway(ctx context.Context) (bool, error) {
+	switch p.config.HostAccessMode {
+	case config.HostAccessModeSSHD:
+		return false, nil
+	case config.HostAccessModeHostGateway:
+		return true, nil
+	case "", config.HostAccessModeAuto:
+		info, err := p.client.Info(ctx, client.InfoOptions{})
+		if err != nil {
+			return false, fmt.Errorf("docker info: %w", err)
+		}
+
+		return supportsHostGateway(info.Info), nil
+	default:
+		return false, fmt.Errorf("unknown host access mode %q", p.config.HostAccessMode)
+	}
+}
+
+// supportsHostGateway returns true if the daemon resolves the host-gateway extra host to an
+// address where the host ports are reachable. Rootless daemons resolve it to an address in
+// their own network namespace, so they are not supported.
+func supportsHostGateway(info system.Info) bool {
+	if slices.Contains(info.SecurityOptions, "name=rootless") {
+		return false
+	}
+
+	return versions.GreaterThanOrEqualTo(info.ServerVersion, hostGatewayMinVersion)
+}
+
+// withHostGateway updates the request so that the [HostInternal] hostname resolves
+// to the host-gateway of the daemon from the container.
+func withHostGateway(req *ContainerRequest) {
+	if req.HostConfigModifier == nil {
+		req.HostConfigModifier = func(_ *container.HostConfig) {}
+	}
+
+	// do not override the original HostConfigModifier
+	originalHCM := req.HostConfigModifier
+	req.HostConfigModifier = func(hostConfig *container.HostConfig) {
+		hostConfig.ExtraHosts = append(hostConfig.ExtraHosts, HostInternal+":"+hostGateway)
+
+		// invoke the original HostConfigModifier with the updated hostConfig
+		originalHCM(hostConfig)
+	}
+}
+
 // newSshdContainer creates a new SSHD container with the provided options.
 func newSshdContainer(ctx context.Context, opts ...ContainerCustomizer) (*sshdContainer, error) {
 	moduleOpts := make([]ContainerCustomizer, 0, 3+len(opts))
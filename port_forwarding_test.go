@@ -14,6 +14,7 @@ import (
 
 	"github.com/testcontainers/testcontainers-go"
 	tcexec "github.com/testcontainers/testcontainers-go/exec"
+	"github.com/testcontainers/testcontainers-go/internal/config"
 	"github.com/testcontainers/testcontainers-go/network"
 )
 
@@ -22,6 +23,11 @@ const (
 )
 
 func TestExposeHostPorts(t *testing.T) {
+	// the servers listen on the loopback interface, which only the sshd mode reaches.
+	t.Setenv("TESTCONTAINERS_HOST_ACCESS_MODE", config.HostAccessModeSSHD)
+	config.Reset()
+	t.Cleanup(config.Reset)
+
 	hostPorts := make([]int, 3)
 	for i := range hostPorts {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
@@ -151,3 +157,85 @@ func containerHasNoHostAccess(t *testing.T, c testcontainers.Container, ports ..
 		require.Contains(t, response, "bad address")
 	}
 }
+
+func TestExposeHostPort(t *testing.T) {
+	ctx := context.Background()
+
+	nw, err := network.New(ctx)
+	require.NoError(t, err)
+	testcontainers.CleanupNetwork(t, nw)
+
+	// The containers are started before the host server is listening.
+	c1, err := testcontainers.Run(ctx, "alpine",
+		testcontainers.WithSharedHostAccess(),
+		testcontainers.WithCmd("top"),
+	)
+	testcontainers.CleanupContainer(t, c1)
+	require.NoError(t, err)
+
+	c2, err := testcontainers.Run(ctx, "alpine",
+		testcontainers.WithSharedHostAccess(),
+		testcontainers.WithCmd("top"),
+		network.WithNetwork([]string{"myalpine"}, nw),
+	)
+	testcontainers.CleanupContainer(t, c2)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, expectedResponse)
+	}))
+	t.Cleanup(server.Close)
+	port := server.Listener.Addr().(*net.TCPAddr).Port
+
+	// exposeHostPort {
+	address, err := testcontainers.ExposeHostPort(ctx, port)
+	// }
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("%s:%d", testcontainers.HostInternal, port), address)
+	require.Contains(t, testcontainers.ExposedHostPorts(), port)
+
+	containerHasHostAccess(t, c1, port)
+	containerHasHostAccess(t, c2, port)
+
+	require.NoError(t, testcontainers.UnexposeHostPort(port))
+	require.NotContains(t, testcontainers.ExposedHostPorts(), port)
+
+	for _, c := range []testcontainers.Container{c1, c2} {
+		code, _ := httpRequest(t, c, port)
+		require.NotZero(t, code)
+	}
+}
+
+func TestExposeHostPorts_hostGateway(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	t.Setenv("TESTCONTAINERS_HOST_ACCESS_MODE", config.HostAccessModeHostGateway)
+	config.Reset()
+	t.Cleanup(config.Reset)
+
+	// The host-gateway is not the loopback interface, so the server listens on all interfaces.
+	listener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, expectedResponse)
+	}))
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	c, err := testcontainers.Run(context.Background(), "alpine",
+		testcontainers.WithHostPortAccess(port),
+		testcontainers.WithCmd("top"),
+	)
+	testcontainers.CleanupContainer(t, c)
+	require.NoError(t, err)
+
+	inspect, err := c.Inspect(context.Background())
+	require.NoError(t, err)
+	require.Contains(t, inspect.HostConfig.ExtraHosts, testcontainers.HostInternal+":host-gateway")
+
+	containerHasHostAccess(t, c, port)
+}
@@ -0,0 +1,92 @@
+package testcontainers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/system"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/internal/config"
+)
+
+func TestSupportsHostGateway(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     system.Info
+		expected bool
+	}{
+		{name: "docker-20.10", info: system.Info{ServerVersion: "20.10.0"}, expected: true},
+		{name: "docker-28", info: system.Info{ServerVersion: "28.3.1"}, expected: true},
+		{name: "docker-19.03", info: system.Info{ServerVersion: "19.03.15"}, expected: false},
+		{name: "podman", info: system.Info{ServerVersion: "4.9.3"}, expected: false},
+		{
+			name:     "rootless",
+			info:     system.Info{ServerVersion: "28.3.1", SecurityOptions: []string{"name=seccomp,profile=builtin", "name=rootless"}},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, supportsHostGateway(tt.info))
+		})
+	}
+}
+
+func TestUseHostGateway(t *testing.T) {
+	useHostGateway := func(t *testing.T, mode string) (bool, error) {
+		t.Helper()
+
+		p := &DockerProvider{config: config.Config{HostAccessMode: mode}}
+		return p.useHostGateway(context.Background())
+	}
+
+	t.Run("default", func(t *testing.T) {
+		// the default mode is auto, which uses the host-gateway when the daemon supports it.
+		for _, info := range []system.Info{
+			{ServerVersion: "28.3.1"},
+			{ServerVersion: "28.3.1", SecurityOptions: []string{"name=rootless"}},
+		} {
+			p := newInfoProvider(t, info)
+
+			ok, err := p.useHostGateway(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, supportsHostGateway(info), ok)
+		}
+	})
+
+	t.Run("sshd", func(t *testing.T) {
+		ok, err := useHostGateway(t, config.HostAccessModeSSHD)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("host-gateway", func(t *testing.T) {
+		ok, err := useHostGateway(t, config.HostAccessModeHostGateway)
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := useHostGateway(t, "tunnel")
+		require.Error(t, err)
+	})
+}
+
+func TestWithHostGateway(t *testing.T) {
+	req := &ContainerRequest{
+		HostConfigModifier: func(hc *container.HostConfig) {
+			hc.ExtraHosts = append(hc.ExtraHosts, "foo:127.0.0.1")
+		},
+	}
+
+	withHostGateway(req)
+
+	hc := &container.HostConfig{}
+	req.HostConfigModifier(hc)
+
+	require.Equal(t, []string{HostInternal + ":host-gateway", "foo:127.0.0.1"}, hc.ExtraHosts)
+	require.Empty(t, req.Networks)
+}
@@ -119,9 +119,9 @@ func (t ProviderType) GetProvider(opts ...GenericProviderOption) (GenericProvide
 		return provider, nil
 	case ProviderPodman:
 		providerOptions := append(Generic2DockerOptions(opts...), WithDefaultBridgeNetwork(Podman))
-		provider, err := NewDockerProvider(providerOptions...)
+		provider, err := newPodmanDockerProvider(providerOptions...)
 		if err != nil {
-			return nil, fmt.Errorf("%w, failed to create Docker provider", err)
+			return nil, fmt.Errorf("%w, failed to create Podman provider", err)
 		}
 		return provider, nil
 	}
@@ -0,0 +1,500 @@
+package testcontainers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/client"
+
+	"github.com/testcontainers/testcontainers-go/internal/config"
+	"github.com/testcontainers/testcontainers-go/internal/core"
+	"github.com/testcontainers/testcontainers-go/log"
+)
+
+// ErrNoPoolMember is returned when the scheduler of a [ProviderPool] finds no Docker host
+// for a container.
+var ErrNoPoolMember = errors.New("no docker host in the provider pool")
+
+// PoolHost is a Docker host of a [ProviderPool].
+type PoolHost struct {
+	// Host is the address of the Docker daemon, in the format of the DOCKER_HOST
+	// environment variable, e.g. "unix:///var/run/docker.sock" or "tcp://10.0.0.2:2376".
+	Host string
+
+	// CertPath is the directory with the "ca.pem", "cert.pem" and "key.pem" files used
+	// to connect to the Docker daemon with TLS, if any. Otherwise, the TLS material
+	// of the Docker CLI context the host comes from is used, if any.
+	CertPath string
+
+	// DockerSocket is the path of the Docker socket in the Docker host, mounted by the
+	// reaper. It defaults to the path of a unix socket or ssh host, or to "/var/run/docker.sock".
+	DockerSocket string
+
+	// Labels are the labels of the Docker host, used by [LabelScheduler] to place the containers.
+	Labels map[string]string
+}
+
+// dockerSocketPath returns the path of the Docker socket in the Docker host.
+func (h PoolHost) dockerSocketPath() string {
+	if h.DockerSocket != "" {
+		return h.DockerSocket
+	}
+
+	if path, ok := strings.CutPrefix(h.Host, core.DockerSocketSchema); ok {
+		return path
+	}
+
+	if core.IsSSHHost(h.Host) {
+		return core.SSHDockerSocketPath(h.Host)
+	}
+
+	return core.DockerSocketPath
+}
+
+// PoolMember is a Docker host of a [ProviderPool], with the provider creating its containers.
+type PoolMember struct {
+	// Host is the Docker host of the member.
+	Host PoolHost
+
+	provider   *DockerProvider
+	containers atomic.Int64
+}
+
+// Provider returns the provider creating the containers and networks of the Docker host.
+func (m *PoolMember) Provider() *DockerProvider {
+	return m.provider
+}
+
+// Containers returns the number of containers the pool placed in the Docker host which
+// are not terminated yet.
+func (m *PoolMember) Containers() int {
+	return int(m.containers.Load())
+}
+
+// Scheduler places the containers of a [ProviderPool] in its Docker hosts.
+type Scheduler interface {
+	// Schedule returns the member of the pool to create the container in, out of the
+	// given members, which are never empty.
+	Schedule(ctx context.Context, req ContainerRequest, members []*PoolMember) (*PoolMember, error)
+}
+
+// SchedulerFunc is an adapter to allow the use of ordinary functions as a [Scheduler].
+type SchedulerFunc func(ctx context.Context, req ContainerRequest, members []*PoolMember) (*PoolMember, error)
+
+// Schedule implements [Scheduler].
+func (f SchedulerFunc) Schedule(ctx context.Context, req ContainerRequest, members []*PoolMember) (*PoolMember, error) {
+	return f(ctx, req, members)
+}
+
+// RoundRobinScheduler returns a scheduler placing the containers in the Docker hosts in turn.
+func RoundRobinScheduler() Scheduler {
+	var next atomic.Uint64
+	return SchedulerFunc(func(_ context.Context, _ ContainerRequest, members []*PoolMember) (*PoolMember, error) {
+		return members[(next.Add(1)-1)%uint64(len(members))], nil
+	})
+}
+
+// LeastContainersScheduler returns a scheduler placing the containers in the Docker host
+// with the fewest containers placed by the pool and not terminated yet, the first one
+// on ties.
+func LeastContainersScheduler() Scheduler {
+	return SchedulerFunc(func(_ context.Context, _ ContainerRequest, members []*PoolMember) (*PoolMember, error) {
+		return slices.MinFunc(members, func(a, b *PoolMember) int {
+			return a.Containers() - b.Containers()
+		}), nil
+	})
+}
+
+// LabelScheduler returns a scheduler placing the containers with the key label in the Docker
+// hosts with the same value for the key label, e.g. the containers labeled "arch=arm64"
+// in the Docker hosts labeled "arch=arm64". The next scheduler places the container out of
+// the matching Docker hosts, or out of all of them if the container has not the label.
+func LabelScheduler(key string, next Scheduler) Scheduler {
+	return SchedulerFunc(func(ctx context.Context, req ContainerRequest, members []*PoolMember) (*PoolMember, error) {
+		value, ok := req.Labels[key]
+		if !ok {
+			return next.Schedule(ctx, req, members)
+		}
+
+		var matching []*PoolMember
+		for _, m := range members {
+			if v, ok := m.Host.Labels[key]; ok && v == value {
+				matching = append(matching, m)
+			}
+		}
+
+		if len(matching) == 0 {
+			return nil, fmt.Errorf("%w: with label %s=%s", ErrNoPoolMember, key, value)
+		}
+
+		return next.Schedule(ctx, req, matching)
+	})
+}
+
+// ProviderPoolOption is an option of [NewProviderPool].
+type ProviderPoolOption func(*ProviderPool)
+
+// WithScheduler sets the scheduler placing the containers of the pool, which is
+// [RoundRobinScheduler] by default.
+func WithScheduler(s Scheduler) ProviderPoolOption {
+	return func(p *ProviderPool) {
+		p.scheduler = s
+	}
+}
+
+// WithPoolProviderOptions sets the options of the providers of the Docker hosts of the pool.
+func WithPoolProviderOptions(opts ...DockerProviderOption) ProviderPoolOption {
+	return func(p *ProviderPool) {
+		p.providerOpts = append(p.providerOpts, opts...)
+	}
+}
+
+// ProviderPool is a provider spreading the containers across several Docker hosts, placed by
+// a [Scheduler]. The containers and networks are bound to the Docker host they are created
+// in, so their operations, such as exec, logs or removal, go to that Docker host, and each
+// Docker host runs its own reaper. The containers attached to a network created by the pool
+// are placed in the Docker host of the network.
+type ProviderPool struct {
+	members      []*PoolMember
+	scheduler    Scheduler
+	providerOpts []DockerProviderOption
+
+	// networks are the members of the networks created by the pool, by name.
+	networks   map[string]*PoolMember
+	networksMx sync.Mutex
+}
+
+var _ GenericProvider = (*ProviderPool)(nil)
+
+// NewProviderPool returns a provider pool with the given Docker hosts. The Docker daemons
+// are not contacted until the first container or network is created in them.
+func NewProviderPool(ctx context.Context, hosts []PoolHost, opts ...ProviderPoolOption) (*ProviderPool, error) {
+	if len(hosts) == 0 {
+		return nil, errors.New("provider pool: no docker hosts")
+	}
+
+	p := &ProviderPool{
+		scheduler: RoundRobinScheduler(),
+		networks:  map[string]*PoolMember{},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	for _, h := range hosts {
+		provider, err := p.newProvider(ctx, h)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("provider pool: docker host %s: %w", h.Host, err), p.Close())
+		}
+
+		p.members = append(p.members, &PoolMember{Host: h, provider: provider})
+	}
+
+	return p, nil
+}
+
+// newProvider returns the provider of the Docker host, with its own reaper.
+func (p *ProviderPool) newProvider(ctx context.Context, h PoolHost) (*DockerProvider, error) {
+	if _, err := url.Parse(h.Host); err != nil || h.Host == "" {
+		return nil, fmt.Errorf("invalid docker host %q", h.Host)
+	}
+
+	o := &DockerProviderOptions{
+		GenericProviderOptions: &GenericProviderOptions{
+			Logger: log.Default(),
+		},
+	}
+	for _, opt := range p.providerOpts {
+		opt.ApplyDockerTo(o)
+	}
+
+	var opts []client.Opt
+	if h.CertPath != "" {
+		opts = append(opts, client.WithTLSClientConfig(
+			filepath.Join(h.CertPath, "ca.pem"),
+			filepath.Join(h.CertPath, "cert.pem"),
+			filepath.Join(h.CertPath, "key.pem"),
+		))
+	}
+
+	cli, err := core.NewClientWithHost(ctx, h.Host, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("new client: %w", err)
+	}
+
+	provider := &DockerProvider{
+		DockerProviderOptions: o,
+		client: &DockerClient{
+			Client:       cli,
+			config:       config.Read(),
+			dockerSocket: h.dockerSocketPath(),
+		},
+		host:         h.Host,
+		config:       config.Read(),
+		dockerSocket: h.dockerSocketPath(),
+	}
+	provider.spawner = &reaperSpawner{provider: provider}
+
+	return provider, nil
+}
+
+// Members returns the Docker hosts of the pool, in the given order.
+func (p *ProviderPool) Members() []*PoolMember {
+	return slices.Clone(p.members)
+}
+
+// schedule returns the member to create the container in: the member of its networks
+// created by the pool, if any, or the one chosen by the scheduler otherwise.
+func (p *ProviderPool) schedule(ctx context.Context, req ContainerRequest) (*PoolMember, error) {
+	p.networksMx.Lock()
+	var pinned *PoolMember
+	for _, name := range req.Networks {
+		m, ok := p.networks[name]
+		if !ok {
+			continue
+		}
+
+		if pinned != nil && pinned != m {
+			p.networksMx.Unlock()
+			return nil, fmt.Errorf("%w: networks %v are in different docker hosts", ErrNoPoolMember, req.Networks)
+		}
+		pinned = m
+	}
+	p.networksMx.Unlock()
+
+	if pinned != nil {
+		return pinned, nil
+	}
+
+	m, err := p.scheduler.Schedule(ctx, req, slices.Clone(p.members))
+	if err != nil {
+		return nil, fmt.Errorf("schedule: %w", err)
+	}
+
+	if m == nil {
+		return nil, ErrNoPoolMember
+	}
+
+	return m, nil
+}
+
+// track counts the container in the member until it is terminated.
+func (m *PoolMember) track(req *ContainerRequest) {
+	m.containers.Add(1)
+
+	var once sync.Once
+	req.LifecycleHooks = append(req.LifecycleHooks, ContainerLifecycleHooks{
+		PostTerminates: []ContainerHook{
+			func(_ context.Context, _ Container) error {
+				once.Do(func() { m.containers.Add(-1) })
+				return nil
+			},
+		},
+	})
+}
+
+// create schedules the container and creates it with fn in the chosen member.
+func (p *ProviderPool) create(ctx context.Context, req ContainerRequest, fn func(*DockerProvider, ContainerRequest) (Container, error)) (Container, error) {
+	m, err := p.schedule(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.create(req, fn)
+}
+
+// create creates the container with fn in the member, counting it until it is terminated.
+func (m *PoolMember) create(req ContainerRequest, fn func(*DockerProvider, ContainerRequest) (Container, error)) (Container, error) {
+	m.track(&req)
+	c, err := fn(m.provider, req)
+	if err != nil && c == nil {
+		m.containers.Add(-1)
+	}
+
+	return c, err
+}
+
+// CreateContainer creates a container without starting it, in the Docker host chosen
+// by the scheduler.
+func (p *ProviderPool) CreateContainer(ctx context.Context, req ContainerRequest) (Container, error) {
+	return p.create(ctx, req, func(provider *DockerProvider, req ContainerRequest) (Container, error) {
+		return provider.CreateContainer(ctx, req)
+	})
+}
+
+// RunContainer creates and starts a container, in the Docker host chosen by the scheduler.
+func (p *ProviderPool) RunContainer(ctx context.Context, req ContainerRequest) (Container, error) {
+	return p.create(ctx, req, func(provider *DockerProvider, req ContainerRequest) (Container, error) {
+		return provider.RunContainer(ctx, req)
+	})
+}
+
+// ReuseOrCreateContainer reuses the container with the name of the request in any of the
+// Docker hosts, or creates it in the Docker host chosen by the scheduler.
+func (p *ProviderPool) ReuseOrCreateContainer(ctx context.Context, req ContainerRequest) (Container, error) {
+	for _, m := range p.members {
+		c, err := m.provider.findContainerByName(ctx, req.Name)
+		if err != nil {
+			return nil, fmt.Errorf("docker host %s: %w", m.Host.Host, err)
+		}
+
+		if c != nil {
+			return m.create(req, func(provider *DockerProvider, req ContainerRequest) (Container, error) {
+				return provider.ReuseOrCreateContainer(ctx, req)
+			})
+		}
+	}
+
+	return p.create(ctx, req, func(provider *DockerProvider, req ContainerRequest) (Container, error) {
+		return provider.ReuseOrCreateContainer(ctx, req)
+	})
+}
+
+// CreateNetwork creates a network in the Docker host chosen by the scheduler, with
+// the labels of the network. The containers attached to the network are created in
+// the same Docker host.
+func (p *ProviderPool) CreateNetwork(ctx context.Context, req NetworkRequest) (Network, error) {
+	m, err := p.schedule(ctx, ContainerRequest{Labels: req.Labels})
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := m.provider.CreateNetwork(ctx, req)
+	if err != nil {
+		return n, err
+	}
+
+	p.networksMx.Lock()
+	p.networks[req.Name] = m
+	p.networksMx.Unlock()
+
+	return &poolNetwork{Network: n, pool: p, name: req.Name, member: m}, nil
+}
+
+// poolNetwork is a network created by a [ProviderPool], which forgets the Docker
+// host of the network once it is removed.
+type poolNetwork struct {
+	Network
+	pool   *ProviderPool
+	name   string
+	member *PoolMember
+}
+
+// Remove removes the network and forgets its Docker host, unless a network with the
+// same name was created again in another Docker host in the meantime.
+func (n *poolNetwork) Remove(ctx context.Context) error {
+	if err := n.Network.Remove(ctx); err != nil {
+		return err
+	}
+
+	n.pool.networksMx.Lock()
+	if n.pool.networks[n.name] == n.member {
+		delete(n.pool.networks, n.name)
+	}
+	n.pool.networksMx.Unlock()
+
+	return nil
+}
+
+// GetNetwork returns the network from the Docker host it was created in by the pool,
+// or from the first Docker host otherwise.
+func (p *ProviderPool) GetNetwork(ctx context.Context, req NetworkRequest) (network.Inspect, error) {
+	p.networksMx.Lock()
+	m, ok := p.networks[req.Name]
+	p.networksMx.Unlock()
+	if !ok {
+		m = p.members[0]
+	}
+
+	return m.provider.GetNetwork(ctx, req)
+}
+
+// Health returns an error if any of the Docker hosts is not reachable.
+func (p *ProviderPool) Health(ctx context.Context) error {
+	var errs []error
+	for _, m := range p.members {
+		if _, err := m.provider.client.Info(ctx, client.InfoOptions{}); err != nil {
+			errs = append(errs, fmt.Errorf("docker host %s: %w", m.Host.Host, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Config returns the configuration of the providers of the pool.
+func (p *ProviderPool) Config() TestcontainersConfig {
+	return p.members[0].provider.Config()
+}
+
+// ListImages returns the images of all the Docker hosts.
+func (p *ProviderPool) ListImages(ctx context.Context) ([]ImageInfo, error) {
+	var images []ImageInfo
+	for _, m := range p.members {
+		list, err := m.provider.ListImages(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("docker host %s: %w", m.Host.Host, err)
+		}
+
+		for _, img := range list {
+			if !slices.Contains(images, img) {
+				images = append(images, img)
+			}
+		}
+	}
+
+	return images, nil
+}
+
+// SaveImages saves the images from the first Docker host into a tarball.
+func (p *ProviderPool) SaveImages(ctx context.Context, output string, images ...string) error {
+	return p.members[0].provider.SaveImages(ctx, output, images...)
+}
+
+// SaveImagesWithOpts saves the images from the first Docker host into a tarball.
+func (p *ProviderPool) SaveImagesWithOpts(ctx context.Context, output string, images []string, opts ...SaveImageOption) error {
+	return p.members[0].provider.SaveImagesWithOpts(ctx, output, images, opts...)
+}
+
+// PullImage pulls the image in all the Docker hosts.
+func (p *ProviderPool) PullImage(ctx context.Context, img string) error {
+	return p.PullImageWithOpts(ctx, img)
+}
+
+// PullImageWithOpts pulls the image in all the Docker hosts, concurrently.
+func (p *ProviderPool) PullImageWithOpts(ctx context.Context, img string, opts ...PullImageOption) error {
+	errs := make([]error, len(p.members))
+
+	var wg sync.WaitGroup
+	for i, m := range p.members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := m.provider.PullImageWithOpts(ctx, img, opts...); err != nil {
+				errs[i] = fmt.Errorf("docker host %s: %w", m.Host.Host, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// Close closes the clients of all the Docker hosts.
+func (p *ProviderPool) Close() error {
+	var errs []error
+	for _, m := range p.members {
+		if err := m.provider.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("docker host %s: %w", m.Host.Host, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
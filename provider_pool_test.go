@@ -0,0 +1,194 @@
+package testcontainers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/internal/core"
+)
+
+func newTestProviderPool(t *testing.T, hosts []PoolHost, opts ...ProviderPoolOption) *ProviderPool {
+	t.Helper()
+
+	pool, err := NewProviderPool(context.Background(), hosts, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, pool.Close())
+	})
+
+	return pool
+}
+
+func TestNewProviderPool(t *testing.T) {
+	pool := newTestProviderPool(t, []PoolHost{
+		{Host: "unix:///run/user/1000/docker.sock"},
+		{Host: "tcp://10.0.0.2:2375"},
+		{Host: "tcp://10.0.0.3:2375", DockerSocket: "/run/docker.sock"},
+	})
+
+	members := pool.Members()
+	require.Len(t, members, 3)
+
+	for i, want := range []string{"/run/user/1000/docker.sock", core.DockerSocketPath, "/run/docker.sock"} {
+		p := members[i].Provider()
+		require.Equal(t, members[i].Host.Host, p.host)
+		require.Equal(t, members[i].Host.Host, p.client.DaemonHost())
+		require.Equal(t, want, p.dockerSocket)
+
+		// each docker host has its own reaper.
+		require.NotSame(t, spawner, p.reaperSpawner())
+		require.Same(t, p, p.reaperSpawner().provider)
+	}
+
+	_, err := NewProviderPool(context.Background(), nil)
+	require.EqualError(t, err, "provider pool: no docker hosts")
+
+	_, err = NewProviderPool(context.Background(), []PoolHost{{Host: ""}})
+	require.ErrorContains(t, err, `invalid docker host ""`)
+}
+
+func TestProviderPool_schedulers(t *testing.T) {
+	ctx := context.Background()
+	hosts := []PoolHost{
+		{Host: "tcp://10.0.0.1:2375", Labels: map[string]string{"arch": "amd64"}},
+		{Host: "tcp://10.0.0.2:2375", Labels: map[string]string{"arch": "arm64"}},
+		{Host: "tcp://10.0.0.3:2375", Labels: map[string]string{"arch": "arm64"}},
+	}
+
+	t.Run("round-robin", func(t *testing.T) {
+		pool := newTestProviderPool(t, hosts)
+		members := pool.Members()
+
+		for i := range 6 {
+			m, err := pool.schedule(ctx, ContainerRequest{})
+			require.NoError(t, err)
+			require.Same(t, members[i%3], m)
+		}
+	})
+
+	t.Run("least-containers", func(t *testing.T) {
+		pool := newTestProviderPool(t, hosts, WithScheduler(LeastContainersScheduler()))
+		members := pool.Members()
+
+		members[0].containers.Store(2)
+		members[1].containers.Store(1)
+		members[2].containers.Store(1)
+
+		m, err := pool.schedule(ctx, ContainerRequest{})
+		require.NoError(t, err)
+		require.Same(t, members[1], m)
+
+		// the tracked container is counted until it is terminated.
+		req := ContainerRequest{}
+		m.track(&req)
+		require.Equal(t, 2, m.Containers())
+
+		m, err = pool.schedule(ctx, ContainerRequest{})
+		require.NoError(t, err)
+		require.Same(t, members[2], m)
+
+		for _, hook := range req.LifecycleHooks[0].PostTerminates {
+			require.NoError(t, hook(ctx, nil))
+			require.NoError(t, hook(ctx, nil))
+		}
+		require.Equal(t, 1, members[1].Containers())
+	})
+
+	t.Run("label", func(t *testing.T) {
+		pool := newTestProviderPool(t, hosts, WithScheduler(LabelScheduler("arch", RoundRobinScheduler())))
+		members := pool.Members()
+
+		for _, want := range []*PoolMember{members[1], members[2], members[1]} {
+			m, err := pool.schedule(ctx, ContainerRequest{Labels: map[string]string{"arch": "arm64"}})
+			require.NoError(t, err)
+			require.Same(t, want, m)
+		}
+
+		// without the label, all the docker hosts are candidates.
+		m, err := pool.schedule(ctx, ContainerRequest{})
+		require.NoError(t, err)
+		require.Same(t, members[0], m)
+
+		_, err = pool.schedule(ctx, ContainerRequest{Labels: map[string]string{"arch": "s390x"}})
+		require.ErrorIs(t, err, ErrNoPoolMember)
+		require.ErrorContains(t, err, "with label arch=s390x")
+	})
+}
+
+func TestProviderPool_networks(t *testing.T) {
+	ctx := context.Background()
+	pool := newTestProviderPool(t, []PoolHost{
+		{Host: "tcp://10.0.0.1:2375"},
+		{Host: "tcp://10.0.0.2:2375"},
+	})
+	members := pool.Members()
+
+	pool.networks["backend"] = members[1]
+	pool.networks["frontend"] = members[0]
+
+	// the containers of a network created by the pool go to its docker host.
+	for range 2 {
+		m, err := pool.schedule(ctx, ContainerRequest{Networks: []string{"bridge", "backend"}})
+		require.NoError(t, err)
+		require.Same(t, members[1], m)
+	}
+
+	_, err := pool.schedule(ctx, ContainerRequest{Networks: []string{"backend", "frontend"}})
+	require.ErrorIs(t, err, ErrNoPoolMember)
+}
+
+type removeFunc func(context.Context) error
+
+func (f removeFunc) Remove(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestProviderPool_removeNetwork(t *testing.T) {
+	ctx := context.Background()
+	pool := newTestProviderPool(t, []PoolHost{
+		{Host: "tcp://10.0.0.1:2375"},
+		{Host: "tcp://10.0.0.2:2375"},
+	})
+	members := pool.Members()
+
+	errRemove := errors.New("remove failed")
+	failing := &poolNetwork{
+		Network: removeFunc(func(context.Context) error { return errRemove }),
+		pool:    pool,
+		name:    "backend",
+		member:  members[1],
+	}
+	removed := &poolNetwork{
+		Network: removeFunc(func(context.Context) error { return nil }),
+		pool:    pool,
+		name:    "backend",
+		member:  members[1],
+	}
+
+	pool.networks["backend"] = members[1]
+
+	// a network that could not be removed is kept.
+	require.ErrorIs(t, failing.Remove(ctx), errRemove)
+	require.Same(t, members[1], pool.networks["backend"])
+
+	require.NoError(t, removed.Remove(ctx))
+	require.NotContains(t, pool.networks, "backend")
+
+	// a network created again with the same name in another Docker host is kept.
+	pool.networks["backend"] = members[0]
+	require.NoError(t, removed.Remove(ctx))
+	require.Same(t, members[0], pool.networks["backend"])
+}
+
+func TestWithProviderPool(t *testing.T) {
+	pool := newTestProviderPool(t, []PoolHost{{Host: "tcp://10.0.0.1:2375"}})
+
+	req := GenericContainerRequest{}
+	require.NoError(t, WithProviderPool(pool).Customize(&req))
+	require.Same(t, pool, req.Pool)
+
+	require.EqualError(t, WithProviderPool(nil).Customize(&req), "provider pool cannot be nil")
+}
@@ -67,8 +67,15 @@ func TestProviderTypeGetProviderAutodetect(t *testing.T) {
 
 			got, err := tt.tr.GetProvider()
 			require.NoErrorf(t, err, "ProviderType.GetProvider()")
+
 			provider, ok := got.(*DockerProvider)
 			require.Truef(t, ok, "ProviderType.GetProvider() = %T, want %T", got, &DockerProvider{})
+
+			podman, ok := provider.Podman()
+			require.Equal(t, tt.want == Podman, ok)
+			if ok {
+				require.Same(t, provider, podman.DockerProvider)
+			}
 			require.Equalf(t, tt.want, provider.defaultBridgeNetworkName, "ProviderType.GetProvider() = %v, want %v", provider.defaultBridgeNetworkName, tt.want)
 		})
 	}
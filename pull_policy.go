@@ -0,0 +1,156 @@
+package testcontainers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/image"
+
+	"github.com/testcontainers/testcontainers-go/internal/config"
+)
+
+// PullPolicy decides whether the image of a container must be pulled before the container
+// is created. It can be set per request with [WithPullPolicy], or for all the requests with
+// the pull.policy property or the TESTCONTAINERS_PULL_POLICY environment variable.
+type PullPolicy interface {
+	// ShouldPull returns true if the image must be pulled. local is the image found in the
+	// Docker daemon, or nil if the image is not present locally or it does not match the
+	// platform of the request. An error aborts the creation of the container.
+	ShouldPull(ctx context.Context, imageName string, local *image.InspectResponse) (bool, error)
+}
+
+// PullPolicyFunc is an adapter to use an ordinary function as a [PullPolicy].
+type PullPolicyFunc func(ctx context.Context, imageName string, local *image.InspectResponse) (bool, error)
+
+// ShouldPull implements [PullPolicy].
+func (f PullPolicyFunc) ShouldPull(ctx context.Context, imageName string, local *image.InspectResponse) (bool, error) {
+	return f(ctx, imageName, local)
+}
+
+// PullIfNotPresent returns the default pull policy, which pulls the image only if it is not
+// present locally.
+func PullIfNotPresent() PullPolicy {
+	return pullIfNotPresent{}
+}
+
+type pullIfNotPresent struct{}
+
+// ShouldPull implements [PullPolicy].
+func (pullIfNotPresent) ShouldPull(_ context.Context, _ string, local *image.InspectResponse) (bool, error) {
+	return local == nil, nil
+}
+
+// PullAlways returns a pull policy which always pulls the image, as [WithAlwaysPull] does.
+func PullAlways() PullPolicy {
+	return pullAlways{}
+}
+
+type pullAlways struct{}
+
+// ShouldPull implements [PullPolicy].
+func (pullAlways) ShouldPull(context.Context, string, *image.InspectResponse) (bool, error) {
+	return true, nil
+}
+
+// PullMaxAge returns a pull policy which pulls the image if it is not present locally, or if
+// the local image is older than maxAge. Its age is measured from the time it was last tagged
+// locally, or, as the daemon does not record it for most pulled images, from the time it was
+// created, that is built. Useful for mutable tags such as "latest".
+func PullMaxAge(maxAge time.Duration) PullPolicy {
+	return pullMaxAge{maxAge: maxAge}
+}
+
+type pullMaxAge struct {
+	maxAge time.Duration
+}
+
+// ShouldPull implements [PullPolicy].
+func (p pullMaxAge) ShouldPull(_ context.Context, _ string, local *image.InspectResponse) (bool, error) {
+	if local == nil {
+		return true, nil
+	}
+
+	pulledAt, ok := imagePullTime(local)
+	if !ok {
+		return true, nil
+	}
+
+	return time.Since(pulledAt) > p.maxAge, nil
+}
+
+// PullNever returns a pull policy which never pulls the image, failing with a not found error
+// if the image is not present locally. Useful for offline and air-gapped environments, where
+// the images are loaded in advance.
+func PullNever() PullPolicy {
+	return pullNever{}
+}
+
+type pullNever struct{}
+
+// ShouldPull implements [PullPolicy].
+func (pullNever) ShouldPull(_ context.Context, imageName string, local *image.InspectResponse) (bool, error) {
+	if local == nil {
+		return false, errdefs.ErrNotFound.WithMessage(fmt.Sprintf("image %s is not present locally and the pull policy is %q", imageName, config.PullPolicyNever))
+	}
+
+	return false, nil
+}
+
+// imagePullTime returns the time the local image was last tagged, falling back to the time the
+// image was created when it was never tagged locally, which is the case of most pulled images.
+// It is not the time the image was pulled, which the daemon does not record.
+func imagePullTime(img *image.InspectResponse) (time.Time, bool) {
+	if img.Metadata.LastTagTime.IsZero() {
+		created, err := time.Parse(time.RFC3339Nano, img.Created)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return created, true
+	}
+
+	return img.Metadata.LastTagTime, true
+}
+
+// parsePullPolicy returns the pull policy defined by the value of the pull.policy property:
+// "if-not-present", "always", "never" or "max-age:<duration>", such as "max-age:24h".
+func parsePullPolicy(s string) (PullPolicy, error) {
+	switch s {
+	case "", config.PullPolicyIfNotPresent:
+		return PullIfNotPresent(), nil
+	case config.PullPolicyAlways:
+		return PullAlways(), nil
+	case config.PullPolicyNever:
+		return PullNever(), nil
+	}
+
+	if d, ok := strings.CutPrefix(s, config.PullPolicyMaxAge+":"); ok {
+		maxAge, err := time.ParseDuration(d)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max age %q: %w", d, err)
+		}
+		return PullMaxAge(maxAge), nil
+	}
+
+	return nil, fmt.Errorf("unknown pull policy %q", s)
+}
+
+// pullPolicy returns the pull policy of the request: the policy of the request, "always" if the
+// request sets AlwaysPullImage, or the policy defined in the configuration otherwise.
+func (p *DockerProvider) pullPolicy(req *ContainerRequest) (PullPolicy, error) {
+	switch {
+	case req.ImagePullPolicy != nil:
+		return req.ImagePullPolicy, nil
+	case req.AlwaysPullImage:
+		return PullAlways(), nil
+	}
+
+	policy, err := parsePullPolicy(p.config.PullPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("pull policy from configuration: %w", err)
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,162 @@
+package testcontainers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/image"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/internal/config"
+)
+
+func TestPullPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	recent := &image.InspectResponse{Metadata: image.Metadata{LastTagTime: time.Now().Add(-time.Minute)}}
+	old := &image.InspectResponse{Created: time.Now().Add(-48 * time.Hour).Format(time.RFC3339Nano)}
+
+	testCases := []struct {
+		name     string
+		policy   PullPolicy
+		local    *image.InspectResponse
+		expected bool
+	}{
+		{name: "if-not-present/missing", policy: PullIfNotPresent(), expected: true},
+		{name: "if-not-present/present", policy: PullIfNotPresent(), local: recent},
+		{name: "always/missing", policy: PullAlways(), expected: true},
+		{name: "always/present", policy: PullAlways(), local: recent, expected: true},
+		{name: "max-age/missing", policy: PullMaxAge(time.Hour), expected: true},
+		{name: "max-age/recent", policy: PullMaxAge(time.Hour), local: recent},
+		{name: "max-age/old", policy: PullMaxAge(time.Hour), local: old, expected: true},
+		{name: "max-age/unknown-age", policy: PullMaxAge(time.Hour), local: &image.InspectResponse{}, expected: true},
+		{name: "never/present", policy: PullNever(), local: old},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pull, err := tc.policy.ShouldPull(ctx, "alpine", tc.local)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, pull)
+		})
+	}
+
+	t.Run("never/missing", func(t *testing.T) {
+		pull, err := PullNever().ShouldPull(ctx, "alpine", nil)
+		require.False(t, pull)
+		require.True(t, errdefs.IsNotFound(err))
+		require.ErrorContains(t, err, "image alpine is not present locally")
+	})
+}
+
+func TestImagePullTime(t *testing.T) {
+	created := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	tagged := time.Now().Add(-time.Minute)
+
+	t.Run("last-tag-time", func(t *testing.T) {
+		at, ok := imagePullTime(&image.InspectResponse{
+			Created:  created.Format(time.RFC3339Nano),
+			Metadata: image.Metadata{LastTagTime: tagged},
+		})
+		require.True(t, ok)
+		require.Equal(t, tagged, at)
+	})
+
+	t.Run("created-fallback", func(t *testing.T) {
+		// pulled images usually have no last tag time, so their age is their creation time.
+		local := &image.InspectResponse{Created: created.Format(time.RFC3339Nano)}
+
+		at, ok := imagePullTime(local)
+		require.True(t, ok)
+		require.True(t, created.Equal(at))
+
+		pull, err := PullMaxAge(72*time.Hour).ShouldPull(context.Background(), "alpine", local)
+		require.NoError(t, err)
+		require.False(t, pull)
+
+		pull, err = PullMaxAge(24*time.Hour).ShouldPull(context.Background(), "alpine", local)
+		require.NoError(t, err)
+		require.True(t, pull)
+	})
+
+	t.Run("invalid-created", func(t *testing.T) {
+		_, ok := imagePullTime(&image.InspectResponse{Created: "yesterday"})
+		require.False(t, ok)
+	})
+}
+
+func TestParsePullPolicy(t *testing.T) {
+	ctx := context.Background()
+	old := &image.InspectResponse{Metadata: image.Metadata{LastTagTime: time.Now().Add(-2 * time.Hour)}}
+
+	shouldPull := func(t *testing.T, s string, local *image.InspectResponse) bool {
+		t.Helper()
+
+		policy, err := parsePullPolicy(s)
+		require.NoError(t, err)
+
+		pull, err := policy.ShouldPull(ctx, "alpine", local)
+		require.NoError(t, err)
+
+		return pull
+	}
+
+	require.True(t, shouldPull(t, "", nil))
+	require.False(t, shouldPull(t, config.PullPolicyIfNotPresent, old))
+	require.True(t, shouldPull(t, config.PullPolicyAlways, old))
+	require.False(t, shouldPull(t, config.PullPolicyNever, old))
+	require.True(t, shouldPull(t, "max-age:1h", old))
+	require.False(t, shouldPull(t, "max-age:3h", old))
+
+	_, err := parsePullPolicy("max-age:forever")
+	require.ErrorContains(t, err, `invalid max age "forever"`)
+
+	_, err = parsePullPolicy("sometimes")
+	require.EqualError(t, err, `unknown pull policy "sometimes"`)
+}
+
+func TestDockerProvider_pullPolicy(t *testing.T) {
+	custom := PullMaxAge(time.Minute)
+
+	testCases := []struct {
+		name     string
+		config   string
+		req      ContainerRequest
+		expected PullPolicy
+		pull     bool
+	}{
+		{name: "request-policy", config: config.PullPolicyNever, req: ContainerRequest{ImagePullPolicy: custom, AlwaysPullImage: true}, expected: custom},
+		{name: "always-pull", config: config.PullPolicyNever, req: ContainerRequest{AlwaysPullImage: true}, pull: true},
+		{name: "config", config: config.PullPolicyAlways, pull: true},
+		{name: "default", pull: false},
+	}
+
+	present := &image.InspectResponse{Metadata: image.Metadata{LastTagTime: time.Now()}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &DockerProvider{config: config.Config{PullPolicy: tc.config}}
+
+			policy, err := p.pullPolicy(&tc.req)
+			require.NoError(t, err)
+
+			if tc.expected != nil {
+				require.Equal(t, tc.expected, policy)
+				return
+			}
+
+			pull, err := policy.ShouldPull(context.Background(), "alpine", present)
+			require.NoError(t, err)
+			require.Equal(t, tc.pull, pull)
+		})
+	}
+
+	t.Run("invalid-config", func(t *testing.T) {
+		p := &DockerProvider{config: config.Config{PullPolicy: "sometimes"}}
+
+		_, err := p.pullPolicy(&ContainerRequest{})
+		require.ErrorContains(t, err, "pull policy from configuration")
+	})
+}
@@ -91,6 +91,11 @@ func reaperContainerNameFromSessionID(sessionID string) string {
 type reaperSpawner struct {
 	instance *Reaper
 	mtx      sync.Mutex
+
+	// provider is the provider looking up the reaper container, for the spawners of
+	// the providers of other docker hosts than the one extracted from the different
+	// alternatives, which have their own reaper.
+	provider *DockerProvider
 }
 
 // port returns the port that a new reaper should listen on.
@@ -156,18 +161,22 @@ func (r *reaperSpawner) cleanupLocked() error {
 // It will perform a retry with exponential backoff to allow for the container to be started and
 // avoid potential false negatives.
 func (r *reaperSpawner) lookupContainer(ctx context.Context, sessionID string) (*DockerContainer, error) {
-	dockerClient, err := NewDockerClientWithOpts(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("new client: %w", err)
-	}
-	defer dockerClient.Close()
+	provider := r.provider
+	if provider == nil {
+		dockerClient, err := NewDockerClientWithOpts(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("new client: %w", err)
+		}
+		defer dockerClient.Close()
 
-	provider, err := NewDockerProvider()
-	if err != nil {
-		return nil, fmt.Errorf("new provider: %w", err)
-	}
+		provider, err = NewDockerProvider()
+		if err != nil {
+			return nil, fmt.Errorf("new provider: %w", err)
+		}
 
-	provider.SetClient(dockerClient)
+		provider.SetClient(dockerClient)
+	}
+	dockerClient := provider.client
 
 	opts := client.ContainerListOptions{
 		All: true,
@@ -386,7 +395,18 @@ func defaultRyukWaitStrategy(port string) wait.Strategy {
 // newReaper creates a connected Reaper with a sessionID to identify containers
 // and a provider to use.
 func (r *reaperSpawner) newReaper(ctx context.Context, sessionID string, provider ReaperProvider) (reaper *Reaper, err error) {
-	dockerHostMount := core.MustExtractDockerSocket(ctx)
+	dockerHostMount := ""
+	var podman bool
+	if p, ok := provider.(*DockerProvider); ok {
+		dockerHostMount = p.dockerSocket
+		if dockerHostMount == "" && p.podman {
+			dockerHostMount = p.podmanSocket(ctx)
+		}
+		podman = p.podman
+	}
+	if dockerHostMount == "" {
+		dockerHostMount = core.MustExtractDockerSocket(ctx)
+	}
 
 	port := r.port()
 	tcConfig := provider.Config().Config
@@ -401,6 +421,10 @@ func (r *reaperSpawner) newReaper(ctx context.Context, sessionID string, provide
 			hc.Binds = []string{dockerHostMount + ":/var/run/docker.sock"}
 			hc.NetworkMode = Bridge
 			hc.Privileged = tcConfig.RyukPrivileged
+			if podman {
+				// SELinux denies the containers the access to the Podman socket, which is labelled for the host.
+				hc.SecurityOpt = append(hc.SecurityOpt, "label=disable")
+			}
 		},
 		Env: map[string]string{},
 	}
@@ -0,0 +1,187 @@
+package testcontainers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/moby/moby/api/types/registry"
+)
+
+// dockerHubRegistry is the registry name used for Docker Hub by the registry auth providers.
+const dockerHubRegistry = "docker.io"
+
+// RegistryAuthProvider provides the credentials of the registries, taking precedence over the
+// ones of the Docker config file. Register it with [RegisterRegistryAuthProvider] to use it
+// for the image pulls and the builds.
+type RegistryAuthProvider interface {
+	// RegistryAuth returns the credentials of the registry, with the form "host[:port]", being
+	// "docker.io" for Docker Hub. It returns false if the provider has no credentials for it.
+	RegistryAuth(ctx context.Context, registry string) (registry.AuthConfig, bool, error)
+}
+
+// RegistryAuthProviderFunc is an adapter to allow the use of ordinary functions as
+// [RegistryAuthProvider]. For example, to exchange an OAuth2 token for a bearer token,
+// which is set as the RegistryToken of the credentials.
+type RegistryAuthProviderFunc func(ctx context.Context, registry string) (registry.AuthConfig, bool, error)
+
+// RegistryAuth calls f(ctx, registry).
+func (f RegistryAuthProviderFunc) RegistryAuth(ctx context.Context, registry string) (registry.AuthConfig, bool, error) {
+	return f(ctx, registry)
+}
+
+var (
+	registryAuthProvidersMtx sync.RWMutex
+	registryAuthProviders    []*RegistryAuthProvider
+)
+
+// RegisterRegistryAuthProvider registers the provider, which is consulted, in the order of
+// registration, before the Docker config file when looking up the credentials of a registry.
+// It returns a function to unregister the provider.
+func RegisterRegistryAuthProvider(provider RegistryAuthProvider) func() {
+	entry := &provider
+
+	registryAuthProvidersMtx.Lock()
+	defer registryAuthProvidersMtx.Unlock()
+
+	registryAuthProviders = append(registryAuthProviders, entry)
+
+	return func() {
+		registryAuthProvidersMtx.Lock()
+		defer registryAuthProvidersMtx.Unlock()
+
+		registryAuthProviders = slices.DeleteFunc(registryAuthProviders, func(p *RegistryAuthProvider) bool {
+			return p == entry
+		})
+	}
+}
+
+// providedRegistryAuth returns the credentials of the registry from the first registered
+// provider having them. It returns false if none of them has credentials for the registry.
+func providedRegistryAuth(ctx context.Context, reg string) (registry.AuthConfig, bool, error) {
+	registryAuthProvidersMtx.RLock()
+	providers := slices.Clone(registryAuthProviders)
+	registryAuthProvidersMtx.RUnlock()
+
+	if len(providers) == 0 {
+		return registry.AuthConfig{}, false, nil
+	}
+
+	name := normalizeRegistry(reg)
+	for _, provider := range providers {
+		cfg, ok, err := (*provider).RegistryAuth(ctx, name)
+		if err != nil {
+			return registry.AuthConfig{}, false, fmt.Errorf("registry auth provider: %w", err)
+		}
+
+		if ok {
+			if cfg.ServerAddress == "" {
+				cfg.ServerAddress = reg
+			}
+			return cfg, true, nil
+		}
+	}
+
+	return registry.AuthConfig{}, false, nil
+}
+
+// normalizeRegistry returns the registry as "host[:port]" in lower case, removing the scheme
+// and the path of the keys of the Docker config file, and using "docker.io" for Docker Hub.
+func normalizeRegistry(reg string) string {
+	if strings.Contains(reg, "://") {
+		if u, err := url.Parse(reg); err == nil {
+			reg = u.Host
+		}
+	}
+
+	reg, _, _ = strings.Cut(reg, "/")
+	reg = strings.ToLower(reg)
+
+	switch reg {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return dockerHubRegistry
+	}
+
+	return reg
+}
+
+// registryAuthProvider is a [RegistryAuthProvider] for a single registry, whose credentials
+// are returned by auth.
+type registryAuthProvider struct {
+	registry string
+	auth     func(ctx context.Context) (registry.AuthConfig, bool, error)
+}
+
+// RegistryAuth returns the credentials if the registry is the one of the provider.
+func (p registryAuthProvider) RegistryAuth(ctx context.Context, reg string) (registry.AuthConfig, bool, error) {
+	if normalizeRegistry(p.registry) != reg {
+		return registry.AuthConfig{}, false, nil
+	}
+
+	return p.auth(ctx)
+}
+
+// StaticRegistryAuth returns a [RegistryAuthProvider] with the given username and password
+// for the registry, e.g. "ghcr.io" or "docker.io".
+func StaticRegistryAuth(reg, username, password string) RegistryAuthProvider {
+	return registryAuthProvider{
+		registry: reg,
+		auth: func(context.Context) (registry.AuthConfig, bool, error) {
+			return basicAuthConfig(username, password), true, nil
+		},
+	}
+}
+
+// EnvRegistryAuth returns a [RegistryAuthProvider] with the username and password for the
+// registry read from the given environment variables every time the credentials are needed,
+// so they can be refreshed during the tests. It has no credentials if the password variable
+// is not set, or is empty.
+func EnvRegistryAuth(reg, usernameEnv, passwordEnv string) RegistryAuthProvider {
+	return registryAuthProvider{
+		registry: reg,
+		auth: func(context.Context) (registry.AuthConfig, bool, error) {
+			password := os.Getenv(passwordEnv)
+			if password == "" {
+				return registry.AuthConfig{}, false, nil
+			}
+
+			return basicAuthConfig(os.Getenv(usernameEnv), password), true, nil
+		},
+	}
+}
+
+// IdentityTokenRegistryAuth returns a [RegistryAuthProvider] with an identity token for the
+// registry, which the Docker daemon exchanges for an access token using the OAuth2 refresh
+// token flow. The token function is called every time the credentials are needed, so it can
+// return short-lived tokens; it has no credentials if it returns an empty token.
+func IdentityTokenRegistryAuth(reg string, token func(ctx context.Context) (string, error)) RegistryAuthProvider {
+	return registryAuthProvider{
+		registry: reg,
+		auth: func(ctx context.Context) (registry.AuthConfig, bool, error) {
+			t, err := token(ctx)
+			if err != nil {
+				return registry.AuthConfig{}, false, fmt.Errorf("identity token for %s: %w", reg, err)
+			}
+
+			if t == "" {
+				return registry.AuthConfig{}, false, nil
+			}
+
+			return registry.AuthConfig{IdentityToken: t}, true, nil
+		},
+	}
+}
+
+// basicAuthConfig returns the credentials with the given username and password.
+func basicAuthConfig(username, password string) registry.AuthConfig {
+	return registry.AuthConfig{
+		Username: username,
+		Password: password,
+		Auth:     base64.StdEncoding.EncodeToString([]byte(username + ":" + password)),
+	}
+}
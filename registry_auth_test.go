@@ -0,0 +1,172 @@
+package testcontainers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cpuguy83/dockercfg"
+	"github.com/moby/moby/api/types/registry"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/internal/core"
+)
+
+// testDefaultRegistry sets the default registry to Docker Hub, without querying the daemon.
+func testDefaultRegistry(t *testing.T) {
+	t.Helper()
+
+	old := defaultRegistryFn
+	t.Cleanup(func() {
+		defaultRegistryFn = old
+	})
+	defaultRegistryFn = func(context.Context) string {
+		return core.IndexDockerIO
+	}
+}
+
+func TestNormalizeRegistry(t *testing.T) {
+	for reg, expected := range map[string]string{
+		core.IndexDockerIO:                     "docker.io",
+		"registry-1.docker.io":                 "docker.io",
+		"https://registry.hub.docker.com/v2/":  "docker.io",
+		"GHCR.io":                              "ghcr.io",
+		"https://my.private.registry":          "my.private.registry",
+		"localhost:5000":                       "localhost:5000",
+		"http://localhost:5000/some/repo/path": "localhost:5000",
+	} {
+		require.Equal(t, expected, normalizeRegistry(reg), reg)
+	}
+}
+
+func TestRegistryAuthProviders(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("static", func(t *testing.T) {
+		p := StaticRegistryAuth("ghcr.io", "gopher", "secret")
+
+		cfg, ok, err := p.RegistryAuth(ctx, "ghcr.io")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, basicAuthConfig("gopher", "secret"), cfg)
+		require.Equal(t, "Z29waGVyOnNlY3JldA==", cfg.Auth)
+
+		_, ok, err = p.RegistryAuth(ctx, "quay.io")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		p := EnvRegistryAuth("https://index.docker.io/v1/", "TC_TEST_REGISTRY_USER", "TC_TEST_REGISTRY_TOKEN")
+
+		t.Setenv("TC_TEST_REGISTRY_USER", "gopher")
+		t.Setenv("TC_TEST_REGISTRY_TOKEN", "")
+		_, ok, err := p.RegistryAuth(ctx, "docker.io")
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		// the variables are read on every lookup.
+		t.Setenv("TC_TEST_REGISTRY_TOKEN", "short-lived")
+		cfg, ok, err := p.RegistryAuth(ctx, "docker.io")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, basicAuthConfig("gopher", "short-lived"), cfg)
+	})
+
+	t.Run("identity-token", func(t *testing.T) {
+		var token string
+		p := IdentityTokenRegistryAuth("registry.example.com", func(context.Context) (string, error) {
+			if token == "fail" {
+				return "", errors.New("token expired")
+			}
+			return token, nil
+		})
+
+		_, ok, err := p.RegistryAuth(ctx, "registry.example.com")
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		token = "refresh-token"
+		cfg, ok, err := p.RegistryAuth(ctx, "registry.example.com")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, registry.AuthConfig{IdentityToken: "refresh-token"}, cfg)
+
+		token = "fail"
+		_, _, err = p.RegistryAuth(ctx, "registry.example.com")
+		require.EqualError(t, err, "identity token for registry.example.com: token expired")
+	})
+}
+
+func TestDockerImageAuth_providers(t *testing.T) {
+	testDefaultRegistry(t)
+	setAuthConfig(t, "ghcr.io", "config", "file")
+
+	ctx := context.Background()
+
+	t.Run("config-file", func(t *testing.T) {
+		reg, cfg, err := DockerImageAuth(ctx, "ghcr.io/org/app:1.0")
+		require.NoError(t, err)
+		require.Equal(t, "ghcr.io", reg)
+		require.Equal(t, "config", cfg.Username)
+	})
+
+	t.Run("in-order", func(t *testing.T) {
+		t.Cleanup(RegisterRegistryAuthProvider(StaticRegistryAuth("ghcr.io", "first", "secret")))
+		t.Cleanup(RegisterRegistryAuthProvider(StaticRegistryAuth("ghcr.io", "second", "secret")))
+
+		reg, cfg, err := DockerImageAuth(ctx, "ghcr.io/org/app:1.0")
+		require.NoError(t, err)
+		require.Equal(t, "ghcr.io", reg)
+		require.Equal(t, "first", cfg.Username)
+		require.Equal(t, "ghcr.io", cfg.ServerAddress)
+	})
+
+	t.Run("docker-hub", func(t *testing.T) {
+		t.Cleanup(RegisterRegistryAuthProvider(StaticRegistryAuth("docker.io", "gopher", "secret")))
+
+		reg, cfg, err := DockerImageAuth(ctx, "redis:7")
+		require.NoError(t, err)
+		require.Equal(t, core.IndexDockerIO, reg)
+		require.Equal(t, "gopher", cfg.Username)
+	})
+
+	t.Run("unregistered", func(t *testing.T) {
+		unregister := RegisterRegistryAuthProvider(StaticRegistryAuth("quay.io", "gopher", "secret"))
+		unregister()
+
+		_, _, err := DockerImageAuth(ctx, "quay.io/org/app:1.0")
+		require.ErrorIs(t, err, dockercfg.ErrCredentialsNotFound)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Cleanup(RegisterRegistryAuthProvider(RegistryAuthProviderFunc(func(context.Context, string) (registry.AuthConfig, bool, error) {
+			return registry.AuthConfig{}, false, errors.New("token service unavailable")
+		})))
+
+		_, _, err := DockerImageAuth(ctx, "ghcr.io/org/app:1.0")
+		require.EqualError(t, err, "registry auth provider: token service unavailable")
+	})
+}
+
+func TestGetAuthConfigsFromDockerfile_providers(t *testing.T) {
+	testDefaultRegistry(t)
+	testDockerConfigHome(t, "testdata", "not-found")
+	t.Cleanup(RegisterRegistryAuthProvider(StaticRegistryAuth("registry.example.com", "gopher", "secret")))
+
+	registryHost := "registry.example.com"
+	req := &ContainerRequest{
+		FromDockerfile: FromDockerfile{
+			Context:    "./testdata",
+			Dockerfile: "auth.Dockerfile",
+			BuildArgs: map[string]*string{
+				"REGISTRY_HOST": &registryHost,
+			},
+		},
+	}
+
+	authConfigs, err := getAuthConfigsFromDockerfile(req)
+	require.NoError(t, err)
+	require.Len(t, authConfigs, 1)
+	require.Equal(t, "gopher", authConfigs[registryHost].Username)
+}
@@ -0,0 +1,199 @@
+package testcontainers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/moby/moby/api/types/container"
+)
+
+// ErrResourceLimitUnsupported is returned when the Docker daemon cannot apply
+// a resource limit of the container.
+var ErrResourceLimitUnsupported = errors.New("resource limit not supported by the Docker daemon")
+
+// minMemoryLimit is the minimum memory limit accepted by the Docker daemon.
+const minMemoryLimit = 6 * 1024 * 1024
+
+// ulimitNames are the names of the ulimits accepted by the Docker daemon.
+var ulimitNames = []string{
+	"core", "cpu", "data", "fsize", "locks", "memlock", "msgqueue", "nice",
+	"nofile", "nproc", "rss", "rtprio", "rttime", "sigpending", "stack",
+}
+
+// namespacedSysctls are the sysctls of the IPC namespace accepted by the Docker daemon,
+// besides the ones prefixed with "fs.mqueue." and "net.".
+var namespacedSysctls = []string{
+	"kernel.msgmax", "kernel.msgmnb", "kernel.msgmni", "kernel.sem",
+	"kernel.shmall", "kernel.shmmax", "kernel.shmmni", "kernel.shm_rmid_forced",
+}
+
+// WithCPUs limits the CPUs the container can use, e.g. 1.5 for one and a half CPUs.
+func WithCPUs(cpus float64) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if cpus <= 0 || math.IsNaN(cpus) || math.IsInf(cpus, 0) {
+			return fmt.Errorf("invalid CPUs %v: must be greater than zero", cpus)
+		}
+
+		return WithHostConfigModifier(func(hc *container.HostConfig) {
+			hc.NanoCPUs = int64(cpus * 1e9)
+		})(req)
+	}
+}
+
+// WithMemoryLimit limits the memory the container can use, in bytes.
+func WithMemoryLimit(bytes int64) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if bytes < minMemoryLimit {
+			return fmt.Errorf("invalid memory limit %d: must be at least 6MB", bytes)
+		}
+
+		return WithHostConfigModifier(func(hc *container.HostConfig) {
+			hc.Memory = bytes
+		})(req)
+	}
+}
+
+// WithPidsLimit limits the number of processes of the container, or removes
+// the limit with -1.
+func WithPidsLimit(limit int64) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if limit == 0 || limit < -1 {
+			return fmt.Errorf("invalid pids limit %d: must be greater than zero, or -1 for unlimited", limit)
+		}
+
+		return WithHostConfigModifier(func(hc *container.HostConfig) {
+			hc.PidsLimit = &limit
+		})(req)
+	}
+}
+
+// WithUlimits sets the ulimits of the container, replacing the ones with the same name.
+func WithUlimits(ulimits ...*container.Ulimit) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		for _, u := range ulimits {
+			if u == nil {
+				return errors.New("ulimit cannot be nil")
+			}
+			if !slices.Contains(ulimitNames, u.Name) {
+				return fmt.Errorf("invalid ulimit name %q", u.Name)
+			}
+			if u.Hard >= 0 && u.Soft > u.Hard {
+				return fmt.Errorf("invalid ulimit %q: soft limit %d greater than hard limit %d", u.Name, u.Soft, u.Hard)
+			}
+		}
+
+		return WithHostConfigModifier(func(hc *container.HostConfig) {
+			for _, u := range ulimits {
+				hc.Ulimits = slices.DeleteFunc(hc.Ulimits, func(existing *container.Ulimit) bool {
+					return existing.Name == u.Name
+				})
+				hc.Ulimits = append(hc.Ulimits, u)
+			}
+		})(req)
+	}
+}
+
+// WithSysctls sets namespaced kernel parameters of the container, such as "net.ipv4.ip_forward".
+// Only the sysctls of the IPC and network namespaces are accepted, and the network ones cannot
+// be set for a container using the host network.
+func WithSysctls(sysctls map[string]string) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		for key := range sysctls {
+			if !namespacedSysctl(key) {
+				return fmt.Errorf("invalid sysctl %q: not namespaced", key)
+			}
+		}
+
+		return WithHostConfigModifier(func(hc *container.HostConfig) {
+			if hc.Sysctls == nil {
+				hc.Sysctls = make(map[string]string, len(sysctls))
+			}
+			maps.Copy(hc.Sysctls, sysctls)
+		})(req)
+	}
+}
+
+// namespacedSysctl returns true if the given sysctl belongs to a namespace of the container.
+func namespacedSysctl(key string) bool {
+	return slices.Contains(namespacedSysctls, key) ||
+		strings.HasPrefix(key, "fs.mqueue.") ||
+		strings.HasPrefix(key, "net.")
+}
+
+// WithOOMScoreAdj adjusts the out of memory score of the container, from -1000 to 1000:
+// the higher it is, the more likely the container is killed when the host runs out of memory.
+// A rootless daemon only accepts positive adjustments.
+func WithOOMScoreAdj(score int) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if score < -1000 || score > 1000 {
+			return fmt.Errorf("invalid OOM score adjustment %d: must be between -1000 and 1000", score)
+		}
+
+		return WithHostConfigModifier(func(hc *container.HostConfig) {
+			hc.OomScoreAdj = score
+		})(req)
+	}
+}
+
+// validateResources verifies that the Docker daemon of the provider can apply the resource
+// limits of the given host config, returning errors wrapping [ErrResourceLimitUnsupported]
+// otherwise. The daemon capabilities are only retrieved when the container has limits.
+func (p *DockerProvider) validateResources(ctx context.Context, hc *container.HostConfig) error {
+	if hc.NetworkMode.IsHost() {
+		for key := range hc.Sysctls {
+			if strings.HasPrefix(key, "net.") {
+				return fmt.Errorf("sysctl %q: not supported with the host network", key)
+			}
+		}
+	}
+
+	pidsLimit := hc.PidsLimit != nil && *hc.PidsLimit > 0
+	if hc.Memory == 0 && hc.MemorySwap == 0 && hc.NanoCPUs == 0 && hc.CPUQuota == 0 && !pidsLimit && hc.OomScoreAdj >= 0 {
+		return nil
+	}
+
+	caps, err := daemonCapabilities(ctx, p.client)
+	if err != nil {
+		return fmt.Errorf("daemon capabilities: %w", err)
+	}
+
+	// the resource limits of the Windows containers are not backed by cgroups.
+	if caps.OSType != "" && caps.OSType != "linux" {
+		return nil
+	}
+
+	// reason explains why the daemon cannot apply a limit it does not support.
+	reason := "not supported by the kernel of the daemon host"
+	if !caps.resourceLimits() {
+		reason = "rootless daemon with cgroup v1"
+	}
+
+	var errs []error
+	if (hc.Memory != 0 || hc.MemorySwap != 0) && !caps.Has(CapabilityMemoryLimit) {
+		errs = append(errs, fmt.Errorf("memory limit: %w: %s", ErrResourceLimitUnsupported, reason))
+	}
+
+	if hc.NanoCPUs != 0 || hc.CPUQuota != 0 {
+		switch {
+		case !caps.Has(CapabilityCPULimit):
+			errs = append(errs, fmt.Errorf("CPU limit: %w: %s", ErrResourceLimitUnsupported, reason))
+		case caps.NCPU > 0 && hc.NanoCPUs > int64(caps.NCPU)*1e9:
+			errs = append(errs, fmt.Errorf("CPU limit: %w: %v CPUs requested, the daemon host has %d", ErrResourceLimitUnsupported, float64(hc.NanoCPUs)/1e9, caps.NCPU))
+		}
+	}
+
+	if pidsLimit && !caps.Has(CapabilityPidsLimit) {
+		errs = append(errs, fmt.Errorf("pids limit: %w: %s", ErrResourceLimitUnsupported, reason))
+	}
+
+	if hc.OomScoreAdj < 0 && caps.Rootless {
+		errs = append(errs, fmt.Errorf("OOM score adjustment %d: %w: a rootless daemon only accepts positive adjustments", hc.OomScoreAdj, ErrResourceLimitUnsupported))
+	}
+
+	return errors.Join(errs...)
+}
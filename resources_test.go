@@ -0,0 +1,145 @@
+package testcontainers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/system"
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/internal/core"
+)
+
+func TestResourceOptions(t *testing.T) {
+	req := GenericContainerRequest{}
+	require.NoError(t, WithCPUs(1.5)(&req))
+	require.NoError(t, WithMemoryLimit(64*1024*1024)(&req))
+	require.NoError(t, WithPidsLimit(100)(&req))
+	require.NoError(t, WithUlimits(&container.Ulimit{Name: "nofile", Soft: 1024, Hard: 2048})(&req))
+	require.NoError(t, WithUlimits(&container.Ulimit{Name: "nofile", Soft: 512, Hard: 512})(&req))
+	require.NoError(t, WithSysctls(map[string]string{"net.ipv4.ip_forward": "1"})(&req))
+	require.NoError(t, WithSysctls(map[string]string{"kernel.shmmax": "65536"})(&req))
+	require.NoError(t, WithOOMScoreAdj(500)(&req))
+
+	hc := &container.HostConfig{}
+	req.HostConfigModifier(hc)
+	require.Equal(t, int64(1_500_000_000), hc.NanoCPUs)
+	require.Equal(t, int64(64*1024*1024), hc.Memory)
+	require.Equal(t, int64(100), *hc.PidsLimit)
+	require.Equal(t, []*container.Ulimit{{Name: "nofile", Soft: 512, Hard: 512}}, hc.Ulimits)
+	require.Equal(t, map[string]string{"net.ipv4.ip_forward": "1", "kernel.shmmax": "65536"}, hc.Sysctls)
+	require.Equal(t, 500, hc.OomScoreAdj)
+
+	t.Run("invalid", func(t *testing.T) {
+		req := GenericContainerRequest{}
+		require.EqualError(t, WithCPUs(0)(&req), "invalid CPUs 0: must be greater than zero")
+		require.EqualError(t, WithMemoryLimit(1024)(&req), "invalid memory limit 1024: must be at least 6MB")
+		require.EqualError(t, WithPidsLimit(0)(&req), "invalid pids limit 0: must be greater than zero, or -1 for unlimited")
+		require.EqualError(t, WithUlimits(&container.Ulimit{Name: "files"})(&req), `invalid ulimit name "files"`)
+		require.EqualError(t, WithUlimits(&container.Ulimit{Name: "nproc", Soft: 10, Hard: 5})(&req), `invalid ulimit "nproc": soft limit 10 greater than hard limit 5`)
+		require.EqualError(t, WithSysctls(map[string]string{"kernel.pid_max": "1"})(&req), `invalid sysctl "kernel.pid_max": not namespaced`)
+		require.EqualError(t, WithOOMScoreAdj(-1001)(&req), "invalid OOM score adjustment -1001: must be between -1000 and 1000")
+		require.Nil(t, req.HostConfigModifier)
+	})
+}
+
+// newInfoProvider returns a provider of a fake docker host, whose info is the given one.
+func newInfoProvider(t *testing.T, info system.Info) *DockerProvider {
+	t.Helper()
+
+	const host = "tcp://127.0.0.1:1"
+
+	cli, err := core.NewClientWithHost(context.Background(), host)
+	require.NoError(t, err)
+	t.Cleanup(func() { cli.Close() })
+
+	dockerInfoLock.Lock()
+	dockerInfos[host] = client.SystemInfoResult{Info: info}
+	dockerInfoLock.Unlock()
+	t.Cleanup(func() {
+		dockerInfoLock.Lock()
+		defer dockerInfoLock.Unlock()
+		delete(dockerInfos, host)
+	})
+
+	return &DockerProvider{client: &DockerClient{Client: cli}, host: host}
+}
+
+func TestDockerProvider_validateResources(t *testing.T) {
+	ctx := context.Background()
+	pidsLimit := int64(100)
+
+	limits := &container.HostConfig{
+		Resources: container.Resources{
+			Memory:    64 * 1024 * 1024,
+			NanoCPUs:  2_000_000_000,
+			PidsLimit: &pidsLimit,
+		},
+		OomScoreAdj: -100,
+	}
+
+	t.Run("rootful", func(t *testing.T) {
+		p := newInfoProvider(t, system.Info{
+			OSType: "linux", CgroupVersion: "2", NCPU: 4,
+			MemoryLimit: true, CPUCfsQuota: true, PidsLimit: true,
+		})
+
+		require.NoError(t, p.validateResources(ctx, limits))
+	})
+
+	t.Run("rootless/cgroup-v1", func(t *testing.T) {
+		p := newInfoProvider(t, system.Info{
+			OSType: "linux", CgroupVersion: "1", NCPU: 4, SecurityOptions: []string{"name=rootless"},
+			MemoryLimit: true, CPUCfsQuota: true, PidsLimit: true,
+		})
+
+		err := p.validateResources(ctx, limits)
+		require.ErrorIs(t, err, ErrResourceLimitUnsupported)
+		require.EqualError(t, err, "memory limit: resource limit not supported by the Docker daemon: rootless daemon with cgroup v1\n"+
+			"CPU limit: resource limit not supported by the Docker daemon: rootless daemon with cgroup v1\n"+
+			"pids limit: resource limit not supported by the Docker daemon: rootless daemon with cgroup v1\n"+
+			"OOM score adjustment -100: resource limit not supported by the Docker daemon: a rootless daemon only accepts positive adjustments")
+	})
+
+	t.Run("too-many-cpus", func(t *testing.T) {
+		p := newInfoProvider(t, system.Info{OSType: "linux", CgroupVersion: "2", NCPU: 1, CPUCfsQuota: true})
+
+		err := p.validateResources(ctx, &container.HostConfig{Resources: container.Resources{NanoCPUs: 2_000_000_000}})
+		require.EqualError(t, err, "CPU limit: resource limit not supported by the Docker daemon: 2 CPUs requested, the daemon host has 1")
+	})
+
+	t.Run("host-network-sysctl", func(t *testing.T) {
+		p := &DockerProvider{}
+
+		err := p.validateResources(ctx, &container.HostConfig{NetworkMode: "host", Sysctls: map[string]string{"net.core.somaxconn": "1024"}})
+		require.EqualError(t, err, `sysctl "net.core.somaxconn": not supported with the host network`)
+	})
+
+	t.Run("no-limits", func(t *testing.T) {
+		// the daemon is not queried without limits.
+		p := &DockerProvider{}
+
+		require.NoError(t, p.validateResources(ctx, &container.HostConfig{}))
+	})
+}
+
+func TestResourceLimits(t *testing.T) {
+	SkipUnless(t, CapabilityMemoryLimit, CapabilityPidsLimit)
+
+	ctx := context.Background()
+	ctr, err := Run(ctx, nginxAlpineImage,
+		WithMemoryLimit(64*1024*1024),
+		WithPidsLimit(64),
+		WithUlimits(&container.Ulimit{Name: "nofile", Soft: 1024, Hard: 1024}),
+	)
+	CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	inspect, err := ctr.Inspect(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(64*1024*1024), inspect.HostConfig.Memory)
+	require.Equal(t, int64(64), *inspect.HostConfig.PidsLimit)
+	require.Equal(t, []*container.Ulimit{{Name: "nofile", Soft: 1024, Hard: 1024}}, inspect.HostConfig.Ulimits)
+}
@@ -0,0 +1,73 @@
+package testcontainers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+)
+
+// ErrRuntimeUnavailable is returned when the Docker daemon does not have the OCI runtime
+// of the container, see [WithRuntime].
+var ErrRuntimeUnavailable = errors.New("OCI runtime not available in the Docker daemon")
+
+// runtimeFallbacks are the Docker hosts and the OCI runtimes of the configuration they do not
+// have, whose fallback to the default runtime of the daemon is already logged.
+var runtimeFallbacks sync.Map
+
+// WithRuntime runs the container with the given OCI runtime, as registered in the Docker daemon,
+// such as "runsc" for gVisor, "kata" or "crun", to sandbox untrusted images. It takes precedence
+// over the runtime of the configuration, and the container creation fails with an error wrapping
+// [ErrRuntimeUnavailable] when the daemon does not have it: [SkipUnless] with [CapabilityRuntime]
+// skips the tests needing it instead. The sshd container giving access to the host ports of
+// the container runs with the same runtime.
+func WithRuntime(name string) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if name == "" {
+			return errors.New("runtime cannot be empty")
+		}
+
+		req.Runtime = name
+		return nil
+	}
+}
+
+// resolveRuntime sets the OCI runtime of the container in its host config, unless the host config
+// modifiers set it: the runtime of the request, which the daemon must have, or the runtime of the
+// configuration, falling back to the default runtime of the daemon when the daemon does not have it.
+func (p *DockerProvider) resolveRuntime(ctx context.Context, req ContainerRequest, hc *container.HostConfig) error {
+	if hc.Runtime != "" {
+		return nil
+	}
+
+	name := req.Runtime
+	if name == "" {
+		name = p.config.OCIRuntime
+	}
+	if name == "" {
+		return nil
+	}
+
+	info, err := p.client.Info(ctx, client.InfoOptions{})
+	if err != nil {
+		return fmt.Errorf("docker info: %w", err)
+	}
+
+	if _, ok := info.Info.Runtimes[name]; ok {
+		hc.Runtime = name
+		return nil
+	}
+
+	if req.Runtime != "" {
+		return fmt.Errorf("runtime %q: %w", name, ErrRuntimeUnavailable)
+	}
+
+	if _, logged := runtimeFallbacks.LoadOrStore(p.host+"|"+name, true); !logged {
+		p.Logger.Printf("⚠️ The Docker daemon does not have the %q OCI runtime, the containers run with its default runtime %q", name, info.Info.DefaultRuntime)
+	}
+
+	return nil
+}
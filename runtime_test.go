@@ -0,0 +1,104 @@
+package testcontainers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/system"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/log"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestWithRuntime(t *testing.T) {
+	req := GenericContainerRequest{}
+	require.NoError(t, WithRuntime("runsc")(&req))
+	require.Equal(t, "runsc", req.Runtime)
+
+	require.EqualError(t, WithRuntime("")(&req), "runtime cannot be empty")
+}
+
+func TestDockerProvider_resolveRuntime(t *testing.T) {
+	ctx := context.Background()
+
+	newProvider := func(t *testing.T, defaultRuntime string) *DockerProvider {
+		t.Helper()
+
+		p := newInfoProvider(t, system.Info{
+			Runtimes: map[string]system.RuntimeWithStatus{
+				"runc":  {Runtime: system.Runtime{Path: "runc"}},
+				"runsc": {Runtime: system.Runtime{Path: "/usr/local/bin/runsc"}},
+			},
+			DefaultRuntime: "runc",
+		})
+		p.DockerProviderOptions = &DockerProviderOptions{GenericProviderOptions: &GenericProviderOptions{Logger: log.TestLogger(t)}}
+		p.config.OCIRuntime = defaultRuntime
+
+		return p
+	}
+
+	t.Run("request", func(t *testing.T) {
+		p := newProvider(t, "crun")
+
+		hc := &container.HostConfig{}
+		require.NoError(t, p.resolveRuntime(ctx, ContainerRequest{Runtime: "runsc"}, hc))
+		require.Equal(t, "runsc", hc.Runtime)
+	})
+
+	t.Run("request/unavailable", func(t *testing.T) {
+		p := newProvider(t, "")
+
+		err := p.resolveRuntime(ctx, ContainerRequest{Runtime: "kata"}, &container.HostConfig{})
+		require.ErrorIs(t, err, ErrRuntimeUnavailable)
+		require.EqualError(t, err, `runtime "kata": OCI runtime not available in the Docker daemon`)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		p := newProvider(t, "runsc")
+
+		hc := &container.HostConfig{}
+		require.NoError(t, p.resolveRuntime(ctx, ContainerRequest{}, hc))
+		require.Equal(t, "runsc", hc.Runtime)
+	})
+
+	t.Run("config/fallback", func(t *testing.T) {
+		p := newProvider(t, "kata")
+
+		hc := &container.HostConfig{}
+		require.NoError(t, p.resolveRuntime(ctx, ContainerRequest{}, hc))
+		require.Empty(t, hc.Runtime)
+	})
+
+	t.Run("host-config-modifier", func(t *testing.T) {
+		p := newProvider(t, "runsc")
+
+		hc := &container.HostConfig{Runtime: "crun"}
+		require.NoError(t, p.resolveRuntime(ctx, ContainerRequest{Runtime: "kata"}, hc))
+		require.Equal(t, "crun", hc.Runtime)
+	})
+
+	t.Run("capability", func(t *testing.T) {
+		c := newCapabilities(system.Info{Runtimes: map[string]system.RuntimeWithStatus{"kata": {}}}, "", false)
+		require.True(t, c.Has(CapabilityRuntime("kata")))
+		require.False(t, c.Has(CapabilityRuntime("runsc")))
+	})
+}
+
+func TestRuntimeGVisor(t *testing.T) {
+	SkipUnless(t, CapabilityRuntime("runsc"))
+
+	ctx := context.Background()
+	ctr, err := Run(ctx, alpineImage,
+		WithRuntime("runsc"),
+		WithCmd("dmesg"),
+		WithWaitStrategy(wait.ForLog("gVisor")),
+	)
+	CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	inspect, err := ctr.Inspect(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "runsc", inspect.HostConfig.Runtime)
+}
@@ -0,0 +1,158 @@
+package testcontainers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/moby/moby/api/types/container"
+)
+
+// seccompUnconfined is the seccomp profile disabling the seccomp confinement.
+const seccompUnconfined = "unconfined"
+
+// WithReadOnlyRootfs mounts the root filesystem of the container as read only. The container
+// can still write to its volumes and tmpfs mounts, see [WithTmpfs], but the files of the request
+// cannot be copied into a read-only root filesystem.
+func WithReadOnlyRootfs() CustomizeRequestOption {
+	return WithHostConfigModifier(func(hc *container.HostConfig) {
+		hc.ReadonlyRootfs = true
+	})
+}
+
+// WithSeccompProfile confines the system calls of the container with the given seccomp
+// profile, in its JSON format, or disables the confinement with "unconfined".
+func WithSeccompProfile(profile string) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if profile != seccompUnconfined {
+			var buf bytes.Buffer
+			if err := json.Compact(&buf, []byte(profile)); err != nil {
+				return fmt.Errorf("invalid seccomp profile: %w", err)
+			}
+			profile = buf.String()
+		}
+
+		return withSecurityOpt("seccomp", profile)(req)
+	}
+}
+
+// WithAppArmorProfile confines the container with the given AppArmor profile, which must
+// be loaded in the Docker host, or disables the confinement with "unconfined".
+func WithAppArmorProfile(profile string) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if profile == "" {
+			return errors.New("AppArmor profile cannot be empty")
+		}
+
+		return withSecurityOpt("apparmor", profile)(req)
+	}
+}
+
+// WithNoNewPrivileges prevents the processes of the container from gaining new privileges,
+// such as through the setuid binaries.
+func WithNoNewPrivileges() CustomizeRequestOption {
+	return withSecurityOpt("no-new-privileges", "true")
+}
+
+// withSecurityOpt sets the given security option of the container, replacing its previous value.
+func withSecurityOpt(name, value string) CustomizeRequestOption {
+	return WithHostConfigModifier(func(hc *container.HostConfig) {
+		hc.SecurityOpt = slices.DeleteFunc(hc.SecurityOpt, func(opt string) bool {
+			return opt == name || strings.HasPrefix(opt, name+"=") || strings.HasPrefix(opt, name+":")
+		})
+		hc.SecurityOpt = append(hc.SecurityOpt, name+"="+value)
+	})
+}
+
+// WithCapAdd adds the given Linux capabilities to the container, such as "NET_ADMIN",
+// or "ALL" for all of them.
+func WithCapAdd(capabilities ...string) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if err := validateLinuxCapabilities(capabilities); err != nil {
+			return fmt.Errorf("cap add: %w", err)
+		}
+
+		return WithHostConfigModifier(func(hc *container.HostConfig) {
+			hc.CapAdd = appendCapabilities(hc.CapAdd, capabilities)
+		})(req)
+	}
+}
+
+// WithCapDrop drops the given Linux capabilities from the container, such as "NET_RAW",
+// or "ALL" for all of them, which can be added back one by one with [WithCapAdd].
+func WithCapDrop(capabilities ...string) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if err := validateLinuxCapabilities(capabilities); err != nil {
+			return fmt.Errorf("cap drop: %w", err)
+		}
+
+		return WithHostConfigModifier(func(hc *container.HostConfig) {
+			hc.CapDrop = appendCapabilities(hc.CapDrop, capabilities)
+		})(req)
+	}
+}
+
+// validateLinuxCapabilities verifies that the given Linux capabilities are not empty,
+// and do not contain whitespaces.
+func validateLinuxCapabilities(capabilities []string) error {
+	if len(capabilities) == 0 {
+		return errors.New("no capabilities")
+	}
+
+	for _, c := range capabilities {
+		if c == "" || strings.ContainsAny(c, " \t\n") {
+			return fmt.Errorf("invalid capability %q", c)
+		}
+	}
+
+	return nil
+}
+
+// appendCapabilities appends the given Linux capabilities which are not in the list yet.
+func appendCapabilities(list, capabilities []string) []string {
+	for _, c := range capabilities {
+		if !slices.Contains(list, c) {
+			list = append(list, c)
+		}
+	}
+
+	return list
+}
+
+// WithUserNamespace sets the user namespace mode of the container, such as "host" to
+// use the user namespace of the host when the daemon remaps the users to a user namespace.
+func WithUserNamespace(mode string) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if mode == "" {
+			return errors.New("user namespace mode cannot be empty")
+		}
+
+		return WithHostConfigModifier(func(hc *container.HostConfig) {
+			hc.UsernsMode = container.UsernsMode(mode)
+		})(req)
+	}
+}
+
+// WithHardenedDefaults runs the container with a production-like hardening: all the Linux
+// capabilities dropped, no new privileges, and a read-only root filesystem with a writable
+// tmpfs mounted on /tmp. The capabilities needed by the container can be added back with
+// [WithCapAdd], and its other writable paths mounted with [WithTmpfs].
+func WithHardenedDefaults() CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		for _, opt := range []CustomizeRequestOption{
+			WithCapDrop("ALL"),
+			WithNoNewPrivileges(),
+			WithReadOnlyRootfs(),
+			WithTmpfs(map[string]string{"/tmp": "rw,noexec,nosuid"}),
+		} {
+			if err := opt(req); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,105 @@
+package testcontainers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestSecurityOptions(t *testing.T) {
+	req := GenericContainerRequest{}
+	require.NoError(t, WithReadOnlyRootfs()(&req))
+	require.NoError(t, WithSeccompProfile(`{
+		"defaultAction": "SCMP_ACT_ALLOW"
+	}`)(&req))
+	require.NoError(t, WithAppArmorProfile("docker-default")(&req))
+	require.NoError(t, WithNoNewPrivileges()(&req))
+	require.NoError(t, WithCapDrop("ALL")(&req))
+	require.NoError(t, WithCapAdd("NET_ADMIN", "CHOWN")(&req))
+	require.NoError(t, WithCapAdd("NET_ADMIN")(&req))
+	require.NoError(t, WithUserNamespace("host")(&req))
+	// the last profile wins.
+	require.NoError(t, WithAppArmorProfile("unconfined")(&req))
+
+	hc := &container.HostConfig{}
+	req.HostConfigModifier(hc)
+	require.True(t, hc.ReadonlyRootfs)
+	require.Equal(t, []string{
+		`seccomp={"defaultAction":"SCMP_ACT_ALLOW"}`,
+		"no-new-privileges=true",
+		"apparmor=unconfined",
+	}, hc.SecurityOpt)
+	require.Equal(t, []string{"ALL"}, hc.CapDrop)
+	require.Equal(t, []string{"NET_ADMIN", "CHOWN"}, hc.CapAdd)
+	require.Equal(t, container.UsernsMode("host"), hc.UsernsMode)
+
+	t.Run("invalid", func(t *testing.T) {
+		req := GenericContainerRequest{}
+		require.ErrorContains(t, WithSeccompProfile("{")(&req), "invalid seccomp profile")
+		require.EqualError(t, WithAppArmorProfile("")(&req), "AppArmor profile cannot be empty")
+		require.EqualError(t, WithCapAdd()(&req), "cap add: no capabilities")
+		require.EqualError(t, WithCapDrop("NET RAW")(&req), `cap drop: invalid capability "NET RAW"`)
+		require.EqualError(t, WithUserNamespace("")(&req), "user namespace mode cannot be empty")
+		require.Nil(t, req.HostConfigModifier)
+	})
+
+	t.Run("hardened-defaults", func(t *testing.T) {
+		req := GenericContainerRequest{}
+		require.NoError(t, WithHardenedDefaults()(&req))
+		require.Equal(t, map[string]string{"/tmp": "rw,noexec,nosuid"}, req.Tmpfs)
+
+		hc := &container.HostConfig{SecurityOpt: []string{"no-new-privileges:false"}}
+		req.HostConfigModifier(hc)
+		require.True(t, hc.ReadonlyRootfs)
+		require.Equal(t, []string{"ALL"}, hc.CapDrop)
+		require.Equal(t, []string{"no-new-privileges=true"}, hc.SecurityOpt)
+	})
+}
+
+func TestHardenedDefaults(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("read-only", func(t *testing.T) {
+		ctr, err := Run(ctx, alpineImage,
+			WithHardenedDefaults(),
+			WithCmd("sh", "-c", "touch /tmp/ok && ! touch /ko"),
+			WithWaitStrategy(wait.ForExit()),
+		)
+		CleanupContainer(t, ctr)
+		require.NoError(t, err)
+
+		state, err := ctr.State(ctx)
+		require.NoError(t, err)
+		require.Zero(t, state.ExitCode)
+	})
+
+	t.Run("nginx", func(t *testing.T) {
+		// nginx runs with the capabilities to switch to its user, and writable cache and pid directories.
+		ctr, err := Run(ctx, nginxAlpineImage,
+			WithHardenedDefaults(),
+			WithCapAdd("CHOWN", "DAC_OVERRIDE", "SETUID", "SETGID", "NET_BIND_SERVICE"),
+			WithTmpfs(map[string]string{"/var/cache/nginx": "rw", "/run": "rw"}),
+			WithExposedPorts(nginxDefaultPort),
+			WithWaitStrategy(wait.ForListeningPort(nginxDefaultPort)),
+		)
+		CleanupContainer(t, ctr)
+		require.NoError(t, err)
+
+		endpoint, err := ctr.PortEndpoint(ctx, nginxDefaultPort, "http")
+		require.NoError(t, err)
+
+		resp, err := http.Get(endpoint)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		_, err = io.Copy(io.Discard, resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
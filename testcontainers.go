@@ -29,6 +29,21 @@ func MustExtractDockerSocket(ctx context.Context) string {
 	return core.MustExtractDockerSocket(ctx)
 }
 
+// DockerDiscoveryReport is the report of the discovery of the Docker host, listing the
+// strategies tried and why each of them was skipped.
+type DockerDiscoveryReport = core.DiscoveryReport
+
+// DockerHostCandidate is a Docker host discovery strategy of a [DockerDiscoveryReport].
+type DockerHostCandidate = core.DockerHostCandidate
+
+// DiscoveryReport returns the report of the discovery of the Docker host, which is done once,
+// with the strategies tried, in the order described in [MustExtractDockerHost], the Docker host
+// each of them found, and why each of them was skipped. Print it to troubleshoot why the
+// Docker host is not the expected one, or why none was found.
+func DiscoveryReport(ctx context.Context) DockerDiscoveryReport {
+	return core.ExtractDockerHostReport(ctx)
+}
+
 // SessionID returns a unique session ID for the current test session. Because each Go package
 // will be run in a separate process, we need a way to identify the current test session.
 // By test session, we mean:
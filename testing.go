@@ -80,6 +80,21 @@ func SkipIfNotDockerDesktop(t *testing.T, ctx context.Context) {
 	}
 }
 
+// SkipUnless is a utility function capable of skipping tests if the Docker daemon
+// does not have all the given capabilities, or is not reachable.
+func SkipUnless(t testing.TB, capabilities ...Capability) {
+	t.Helper()
+
+	c, err := DaemonCapabilities(context.Background())
+	if err != nil {
+		t.Skipf("Skipping test as the Docker daemon capabilities are unknown: %s", err)
+	}
+
+	if missing := c.Missing(capabilities...); len(missing) > 0 {
+		t.Skipf("Skipping test as the Docker daemon lacks the capabilities: %v", missing)
+	}
+}
+
 // exampleLogConsumer {
 
 // StdoutLogConsumer is a LogConsumer that prints the log to stdout
@@ -118,6 +133,20 @@ func CleanupNetwork(tb testing.TB, network Network) {
 	})
 }
 
+// CleanupPod is a helper function that schedules the Podman pod to be
+// removed when the test ends, after the containers of the pod.
+// This should be the first call after CreatePod(...) in a test before
+// any error check. If pod is nil, it's a no-op.
+func CleanupPod(tb testing.TB, pod *Pod) {
+	tb.Helper()
+
+	tb.Cleanup(func() {
+		if pod != nil {
+			noErrorOrIgnored(tb, pod.Terminate(context.Background()))
+		}
+	})
+}
+
 // noErrorOrIgnored is a helper function that checks if the error is nil or an error
 // we can ignore.
 func noErrorOrIgnored(tb testing.TB, err error) {
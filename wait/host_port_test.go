@@ -62,6 +62,42 @@ func TestWaitForListeningPortSucceeds(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestWaitForListeningPortSucceeds_ipv6(t *testing.T) {
+	listener, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback not available: %s", err)
+	}
+	defer listener.Close()
+
+	rawPort := listener.Addr().(*net.TCPAddr).Port
+	port, err := network.ParsePort(fmt.Sprintf("%d/tcp", rawPort))
+	require.NoError(t, err)
+
+	target := &MockStrategyTarget{
+		HostImpl: func(_ context.Context) (string, error) {
+			return "::1", nil
+		},
+		MappedPortImpl: func(_ context.Context, _ string) (network.Port, error) {
+			return port, nil
+		},
+		StateImpl: func(_ context.Context) (*container.State, error) {
+			return &container.State{
+				Running: true,
+			}, nil
+		},
+		ExecImpl: func(_ context.Context, _ []string, _ ...exec.ProcessOption) (int, io.Reader, error) {
+			return 0, nil, nil
+		},
+	}
+
+	wg := ForListeningPort("80").
+		WithStartupTimeout(5 * time.Second).
+		WithPollInterval(100 * time.Millisecond)
+
+	err = wg.WaitUntilReady(context.Background(), target)
+	require.NoError(t, err)
+}
+
 func TestWaitForListeningPortInternallySucceeds(t *testing.T) {
 	localPort, err := network.ParsePort("80/tcp")
 	require.NoError(t, err)